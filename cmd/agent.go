@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
-	"time"
 
+	"github.com/promptingcompany/openspend-cli/internal/agentkey"
 	"github.com/promptingcompany/openspend-cli/internal/api"
 	"github.com/spf13/cobra"
 )
@@ -17,6 +20,16 @@ func newAgentCmd() *cobra.Command {
 	agentCmd.AddCommand(newAgentCreateCmd())
 	agentCmd.AddCommand(newAgentUpdateCmd())
 	agentCmd.AddCommand(newAgentListCmd())
+	agentCmd.AddCommand(newAgentApplyCmd())
+	agentCmd.AddCommand(newAgentTokenCmd())
+	agentCmd.AddCommand(newAgentBudgetCmd())
+	agentCmd.AddCommand(newAgentActivityCmd())
+	agentCmd.AddCommand(newAgentDescribeCmd())
+	agentCmd.AddCommand(newAgentStatusCmd("disable", agentStatusDisabled, "Disable an agent so its requests are refused"))
+	agentCmd.AddCommand(newAgentStatusCmd("enable", agentStatusActive, "Re-enable a disabled agent"))
+	agentCmd.AddCommand(newAgentDeleteCmd())
+	agentCmd.AddCommand(newAgentClaimCmd())
+	agentCmd.AddCommand(newAgentPruneCmd())
 	return agentCmd
 }
 
@@ -25,22 +38,90 @@ func newAgentCreateCmd() *cobra.Command {
 	var displayName string
 	var kind string
 	var policyID string
+	var keyStrategy string
+	var keyPrefix string
+	var keyTemplate string
+	var keyAttrs map[string]string
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a buyer subject and bind to policy",
+		Long: strings.TrimSpace(`
+Create a buyer subject and bind it to a policy.
+
+Without --external-key a key is generated and checked against existing
+subjects so it is never reused. The agent is only created if the key is
+still free, so a key taken by a parallel run is replaced by the next
+candidate instead of updating that agent:
+
+  ulid      <prefix>-<ulid>, random and sortable (default)
+  template  --key-template rendered with --key-attr values; {{.Seq}} counts
+            up from 1 to the first free key
+  hash      <prefix>-<hash of --key-attr values>, the same for the same
+            attributes; fails if that agent already exists
+
+Attribute names are capitalized in templates: --key-attr team=payments is
+{{.Team}}.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent create --display-name "Buyer Agent"
+  openspend dashboard agent create --key-template '{{.Team}}-{{.Env}}-{{.Seq}}' --key-attr team=payments,env=prod
+  openspend dashboard agent create --key-strategy hash --key-attr team=payments --key-attr env=prod
+`),
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runAgentUpsert(cmd, externalKey, displayName, kind, policyID, true, "ready")
+			strategy := ""
+			if cmd.Flags().Changed("key-strategy") {
+				strategy = keyStrategy
+			}
+			resolved, err := resolveAgentKeyStrategy(strategy, keyTemplate)
+			if err != nil {
+				return err
+			}
+			keyOpts := &agentkey.Options{
+				Strategy: resolved,
+				Prefix:   keyPrefix,
+				Template: keyTemplate,
+				Attrs:    keyAttrs,
+			}
+			return runAgentUpsert(cmd, externalKey, displayName, kind, policyID, keyOpts, "ready")
 		},
 	}
 
-	cmd.Flags().StringVar(&externalKey, "external-key", "", "Subject external key (auto-generated if omitted)")
+	cmd.Flags().StringVar(&externalKey, "external-key", "", "Subject external key (generated if omitted)")
 	cmd.Flags().StringVar(&displayName, "display-name", "", "Display name")
 	cmd.Flags().StringVar(&kind, "kind", "agent", "Subject kind")
 	cmd.Flags().StringVar(&policyID, "policy-id", "", "Optional policy ID override")
+	cmd.Flags().StringVar(&keyStrategy, "key-strategy", string(agentkey.StrategyULID), "Generated key strategy (ulid|template|hash)")
+	cmd.Flags().StringVar(&keyPrefix, "key-prefix", agentkey.DefaultPrefix, "Prefix for ulid and hash keys")
+	cmd.Flags().StringVar(&keyTemplate, "key-template", "", "Go template for generated keys, for example '{{.Team}}-{{.Env}}-{{.Seq}}'")
+	cmd.Flags().StringToStringVar(&keyAttrs, "key-attr", nil, "Attribute for --key-template or the hash strategy (name=value, repeatable)")
 	return cmd
 }
 
+// resolveAgentKeyStrategy picks the key strategy for agent create from an
+// explicit --key-strategy (empty when not given) and --key-template. A
+// template implies the template strategy and is rejected with any other.
+func resolveAgentKeyStrategy(raw, template string) (agentkey.Strategy, error) {
+	hasTemplate := strings.TrimSpace(template) != ""
+	if strings.TrimSpace(raw) == "" {
+		if hasTemplate {
+			return agentkey.StrategyTemplate, nil
+		}
+		return agentkey.StrategyULID, nil
+	}
+	strategy, err := agentkey.ParseStrategy(raw)
+	if err != nil {
+		return "", fmt.Errorf("--key-strategy: %w", err)
+	}
+	switch {
+	case strategy == agentkey.StrategyTemplate && !hasTemplate:
+		return "", fmt.Errorf("--key-strategy template requires --key-template")
+	case strategy != agentkey.StrategyTemplate && hasTemplate:
+		return "", fmt.Errorf("--key-template requires --key-strategy template, not %s", strategy)
+	}
+	return strategy, nil
+}
+
 func newAgentUpdateCmd() *cobra.Command {
 	var externalKey string
 	var displayName string
@@ -51,7 +132,7 @@ func newAgentUpdateCmd() *cobra.Command {
 		Use:   "update",
 		Short: "Update an existing buyer subject and policy binding",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runAgentUpsert(cmd, externalKey, displayName, kind, policyID, false, "updated")
+			return runAgentUpsert(cmd, externalKey, displayName, kind, policyID, nil, "updated")
 		},
 	}
 
@@ -69,30 +150,42 @@ func runAgentUpsert(
 	displayName string,
 	kind string,
 	policyID string,
-	allowGeneratedKey bool,
+	keyOpts *agentkey.Options,
 	outcome string,
 ) error {
-	generatedExternalKey := false
-	if strings.TrimSpace(externalKey) == "" {
-		if !allowGeneratedKey {
-			return fmt.Errorf("--external-key is required")
-		}
-		externalKey = fmt.Sprintf("buyer-agent-%d", time.Now().Unix())
-		generatedExternalKey = true
-		fmt.Fprintf(cmd.OutOrStdout(), "No --external-key provided; using generated key: %s\n", externalKey)
+	if strings.TrimSpace(externalKey) == "" && keyOpts == nil {
+		return fmt.Errorf("--external-key is required")
 	}
 
 	cfg := mustLoadConfig()
 	client := clientFromConfig(cfg)
 
-	res, err := client.CreateAgent(cmd.Context(), api.CreateAgentRequest{
+	req := api.CreateAgentRequest{
 		ExternalKey: externalKey,
 		DisplayName: displayName,
 		Kind:        kind,
 		PolicyID:    policyID,
-	})
-	if err != nil {
-		return err
+	}
+	var res api.CreateAgentResponse
+	generatedExternalKey := strings.TrimSpace(externalKey) == ""
+	if generatedExternalKey {
+		key, err := withGeneratedAgentKey(cmd.Context(), client, *keyOpts, func(key string) error {
+			req.ExternalKey = key
+			req.CreateOnly = true
+			var err error
+			res, err = client.CreateAgent(cmd.Context(), req)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "No --external-key provided; using generated key: %s\n", key)
+	} else {
+		var err error
+		res, err = client.CreateAgent(cmd.Context(), req)
+		if err != nil {
+			return err
+		}
 	}
 	if err := persistAuthFromClient(&cfg, client); err != nil {
 		return err
@@ -111,63 +204,45 @@ func runAgentUpsert(
 	return nil
 }
 
-func newAgentListCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "list",
-		Short: "List agent subjects for current user",
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			cfg := mustLoadConfig()
-			client := clientFromConfig(cfg)
-
-			res, err := client.WhoAmI(cmd.Context())
-			if err != nil {
-				return err
-			}
-			if err := persistAuthFromClient(&cfg, client); err != nil {
-				return err
-			}
-
-			found := 0
-			for _, subject := range res.Subjects {
-				if subject.Kind != "agent" && subject.Kind != "anonymous_agent" {
-					continue
-				}
-				displayName := ""
-				if subject.DisplayName != nil {
-					displayName = strings.TrimSpace(*subject.DisplayName)
-				}
-				externalKey := ""
-				if subject.ExternalKey != nil {
-					externalKey = strings.TrimSpace(*subject.ExternalKey)
-				}
-				policyName := ""
-				if subject.PolicyName != nil {
-					policyName = strings.TrimSpace(*subject.PolicyName)
-				}
-				policyID := ""
-				if subject.PolicyID != nil {
-					policyID = strings.TrimSpace(*subject.PolicyID)
-				}
-				fmt.Fprintf(
-					cmd.OutOrStdout(),
-					"- id=%s key=%s name=%s kind=%s status=%s policy=%s policy_id=%s\n",
-					subject.ID,
-					externalKey,
-					displayName,
-					subject.Kind,
-					subject.Status,
-					policyName,
-					policyID,
-				)
-				found++
-			}
+// agentKeyAttempts bounds how many generated keys are tried when keys are
+// taken between the existing-key check and the create.
+const agentKeyAttempts = 5
+
+// withGeneratedAgentKey generates a key that no existing subject uses and
+// passes it to create, which must fail with 409 Conflict rather than reuse a
+// key taken in the meantime, for example by a parallel run. A conflicting key
+// is marked taken and the next candidate is tried. It returns the key that
+// create accepted.
+func withGeneratedAgentKey(
+	ctx context.Context,
+	client *api.Client,
+	opts agentkey.Options,
+	create func(key string) error,
+) (string, error) {
+	res, err := client.WhoAmI(ctx)
+	if err != nil {
+		return "", fmt.Errorf("check existing keys: %w", err)
+	}
+	taken := make(map[string]bool, len(res.Subjects))
+	for _, subject := range res.Subjects {
+		if subject.ExternalKey != nil {
+			taken[strings.TrimSpace(*subject.ExternalKey)] = true
+		}
+	}
 
-			if found == 0 {
-				fmt.Fprintln(cmd.OutOrStdout(), "No agents found.")
-				return nil
-			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Total agents: %d\n", found)
-			return nil
-		},
+	for attempt := 0; attempt < agentKeyAttempts; attempt++ {
+		key, err := agentkey.Generate(opts, func(key string) bool { return taken[key] })
+		if errors.Is(err, agentkey.ErrTaken) {
+			return "", fmt.Errorf("%w; use agent update to change the existing agent", err)
+		}
+		if err != nil {
+			return "", err
+		}
+		err = create(key)
+		if !api.IsStatus(err, http.StatusConflict) {
+			return key, err
+		}
+		taken[key] = true
 	}
+	return "", fmt.Errorf("generated keys kept colliding with new agents after %d attempts", agentKeyAttempts)
 }
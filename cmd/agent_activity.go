@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+	"github.com/spf13/cobra"
+)
+
+const (
+	activityOutputText = "text"
+	activityOutputCSV  = "csv"
+)
+
+var activityCSVHeader = []string{
+	"occurred_at",
+	"type",
+	"resource_url",
+	"price_base_units",
+	"amount",
+	"asset",
+	"network",
+	"decision",
+	"policy_id",
+	"rule_id",
+	"event_id",
+}
+
+func newAgentActivityCmd() *cobra.Command {
+	var (
+		sinceRaw string
+		output   string
+		follow   bool
+		interval time.Duration
+		limit    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "activity <agent-key>",
+		Short: "List an agent's purchases and invocations",
+		Long: strings.TrimSpace(`
+List an agent's purchases and invocations, oldest first, with the policy
+decision and the rule that matched. Prices are shown in base units and as an
+amount of the asset.
+
+--since takes a duration back from now (24h, 7d) or an RFC 3339 time.
+--output csv writes one row per event for spreadsheets. --follow keeps
+polling for new events every --interval until interrupted.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent activity buyer-agent-1 --since 7d
+  openspend dashboard agent activity buyer-agent-1 --since 2026-01-01T00:00:00Z --output csv > activity.csv
+  openspend dashboard agent activity buyer-agent-1 --since 1h --follow
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+			since, err := parseSince(sinceRaw, time.Now())
+			if err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+			output = strings.ToLower(strings.TrimSpace(output))
+			if output != activityOutputText && output != activityOutputCSV {
+				return fmt.Errorf("--output must be one of: text, csv")
+			}
+			if limit <= 0 {
+				return fmt.Errorf("--limit must be positive")
+			}
+			if follow && interval < time.Second {
+				return fmt.Errorf("--interval must be at least 1s")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			ctx := cmd.Context()
+			if follow {
+				var stop context.CancelFunc
+				ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+				defer stop()
+			}
+
+			var printer activityPrinter
+			if output == activityOutputCSV {
+				printer = newActivityCSVPrinter(cmd.OutOrStdout())
+			} else {
+				printer = &activityTextPrinter{out: cmd.OutOrStdout()}
+			}
+
+			req := api.AgentActivityRequest{Since: since, Limit: limit}
+			var seen activityWatermark
+			for {
+				it := client.AgentActivity(key, req)
+				for it.Next(ctx) {
+					event := it.Item()
+					// Polls that resume from the last event's time see it again.
+					if seen.has(event) {
+						continue
+					}
+					if err := printer.Event(event); err != nil {
+						return err
+					}
+					seen.add(event)
+				}
+				if err := printer.Flush(); err != nil {
+					return err
+				}
+				if err := it.Err(); err != nil {
+					if follow && ctx.Err() != nil {
+						break
+					}
+					return err
+				}
+				if err := persistAuthFromClient(&cfg, client); err != nil {
+					return err
+				}
+				if !follow {
+					break
+				}
+
+				req = resumeActivity(req, it.Cursor(), seen.at)
+				select {
+				case <-ctx.Done():
+				case <-time.After(interval):
+				}
+				if ctx.Err() != nil {
+					break
+				}
+			}
+
+			return printer.Close()
+		},
+	}
+
+	cmd.Flags().StringVar(&sinceRaw, "since", "7d", "Only events after this duration ago (24h, 7d) or RFC 3339 time; empty for all")
+	cmd.Flags().StringVar(&output, "output", activityOutputText, "Output format (text|csv)")
+	cmd.Flags().BoolVar(&follow, "follow", false, "Keep polling for new events until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Polling interval for --follow")
+	cmd.Flags().IntVar(&limit, "limit", 100, "Page size")
+	return cmd
+}
+
+// parseSince accepts a duration back from now (see parseDuration) or an RFC
+// 3339 time. An empty value means no lower bound.
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	d, err := parseDuration(raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value %q (for example 24h, 7d or 2026-01-01T00:00:00Z)", raw)
+	}
+	return now.Add(-d), nil
+}
+
+// resumeActivity returns the request that polls for events after the last
+// fetched page. Servers that give no cursor are polled again from the time of
+// the last event seen.
+func resumeActivity(req api.AgentActivityRequest, cursor string, lastAt time.Time) api.AgentActivityRequest {
+	if cursor != "" {
+		req.Cursor = cursor
+		return req
+	}
+	if !lastAt.IsZero() {
+		req.Since = lastAt
+	}
+	return req
+}
+
+// activityWatermark remembers the latest event time seen and the IDs of the
+// events from the start of that second on, so polling again from that time
+// does not repeat them even when the server compares whole seconds.
+type activityWatermark struct {
+	at  time.Time
+	ids map[string]time.Time
+}
+
+func (w *activityWatermark) add(event api.AgentActivityEvent) {
+	at, err := time.Parse(time.RFC3339Nano, event.OccurredAt)
+	if err != nil {
+		return
+	}
+	if w.ids == nil {
+		w.ids = make(map[string]time.Time)
+	}
+	if at.After(w.at) {
+		w.at = at
+		floor := at.Truncate(time.Second)
+		for id, t := range w.ids {
+			if t.Before(floor) {
+				delete(w.ids, id)
+			}
+		}
+	}
+	if !at.Before(w.at.Truncate(time.Second)) {
+		w.ids[event.ID] = at
+	}
+}
+
+func (w *activityWatermark) has(event api.AgentActivityEvent) bool {
+	_, ok := w.ids[event.ID]
+	return ok
+}
+
+type activityPrinter interface {
+	Event(api.AgentActivityEvent) error
+	// Flush writes buffered output; it is called after every poll.
+	Flush() error
+	// Close writes any trailer once no more events will follow.
+	Close() error
+}
+
+type activityTextPrinter struct {
+	out     io.Writer
+	summary activitySummary
+}
+
+func (p *activityTextPrinter) Event(event api.AgentActivityEvent) error {
+	row := activityRow(event)
+	fmt.Fprintf(
+		p.out,
+		"- at=%s type=%s resource_url=%s price=%s amount=%s asset=%s network=%s decision=%s policy_id=%s rule_id=%s\n",
+		row[0],
+		row[1],
+		row[2],
+		row[3],
+		row[4],
+		row[5],
+		row[6],
+		row[7],
+		row[8],
+		row[9],
+	)
+	p.summary.add(event)
+	return nil
+}
+
+func (p *activityTextPrinter) Flush() error { return nil }
+
+func (p *activityTextPrinter) Close() error {
+	p.summary.print(p.out)
+	return nil
+}
+
+type activityCSVPrinter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newActivityCSVPrinter(out io.Writer) *activityCSVPrinter {
+	return &activityCSVPrinter{w: csv.NewWriter(out)}
+}
+
+func (p *activityCSVPrinter) Event(event api.AgentActivityEvent) error {
+	if err := p.header(); err != nil {
+		return err
+	}
+	return p.w.Write(activityRow(event))
+}
+
+func (p *activityCSVPrinter) Flush() error {
+	p.w.Flush()
+	return p.w.Error()
+}
+
+// Close writes the header for an empty export.
+func (p *activityCSVPrinter) Close() error {
+	if err := p.header(); err != nil {
+		return err
+	}
+	return p.Flush()
+}
+
+func (p *activityCSVPrinter) header() error {
+	if p.wroteHeader {
+		return nil
+	}
+	p.wroteHeader = true
+	return p.w.Write(activityCSVHeader)
+}
+
+// activityRow renders an event in activityCSVHeader order.
+func activityRow(event api.AgentActivityEvent) []string {
+	price := derefTrimmed(event.Price)
+	asset := money.NormalizeSymbol(derefTrimmed(event.Asset))
+	network := derefTrimmed(event.Network)
+	amount := ""
+	if base, err := money.ParseBaseUnits(price); err == nil && asset != "" {
+		if human, err := money.FromBaseUnits(base, asset, network); err == nil {
+			amount = money.FormatRat(human)
+		}
+	}
+	return []string{
+		event.OccurredAt,
+		event.Type,
+		event.ResourceURL,
+		price,
+		amount,
+		asset,
+		network,
+		event.Decision,
+		derefTrimmed(event.PolicyID),
+		derefTrimmed(event.RuleID),
+		event.ID,
+	}
+}
+
+func derefTrimmed(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
+
+// activitySummary counts events and totals the base units spent on allowed
+// events per asset.
+type activitySummary struct {
+	total   int
+	allowed int
+	denied  int
+	spent   map[string]*big.Int
+}
+
+func (s *activitySummary) add(event api.AgentActivityEvent) {
+	s.total++
+	switch strings.ToLower(strings.TrimSpace(event.Decision)) {
+	case "allow", "allowed":
+		s.allowed++
+	case "deny", "denied":
+		s.denied++
+		return
+	default:
+		return
+	}
+	asset := money.NormalizeSymbol(derefTrimmed(event.Asset))
+	base, err := money.ParseBaseUnits(derefTrimmed(event.Price))
+	if err != nil || asset == "" {
+		return
+	}
+	if s.spent == nil {
+		s.spent = make(map[string]*big.Int)
+	}
+	if s.spent[asset] == nil {
+		s.spent[asset] = new(big.Int)
+	}
+	s.spent[asset].Add(s.spent[asset], base)
+}
+
+func (s *activitySummary) print(out io.Writer) {
+	if s.total == 0 {
+		fmt.Fprintln(out, "No activity.")
+		return
+	}
+	fmt.Fprintf(out, "Total events: %d (allowed %d, denied %d)\n", s.total, s.allowed, s.denied)
+	assets := make([]string, 0, len(s.spent))
+	for asset := range s.spent {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	for _, asset := range assets {
+		fmt.Fprintf(out, "Spent: %s\n", money.FormatBaseUnits(s.spent[asset].String(), asset, ""))
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func activityEvent(id, at, decision, price string) api.AgentActivityEvent {
+	asset, network := "USDC", "base"
+	return api.AgentActivityEvent{
+		ID:          id,
+		OccurredAt:  at,
+		Type:        "purchase",
+		ResourceURL: "https://api.example.com/v1/ocr",
+		Price:       &price,
+		Asset:       &asset,
+		Network:     &network,
+		Decision:    decision,
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		raw     string
+		want    time.Time
+		wantErr bool
+	}{
+		{raw: "", want: time.Time{}},
+		{raw: "7d", want: now.Add(-7 * 24 * time.Hour)},
+		{raw: "90m", want: now.Add(-90 * time.Minute)},
+		{raw: "2026-01-01T00:00:00Z", want: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{raw: "last week", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.raw, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%q: expected error", tt.raw)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.raw, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("%q: expected %s, got %s", tt.raw, tt.want, got)
+		}
+	}
+}
+
+func TestResumeActivity(t *testing.T) {
+	req := api.AgentActivityRequest{Since: time.Unix(0, 0), Limit: 10}
+
+	lastAt := time.Date(2026, 10, 18, 0, 0, 1, 500000000, time.UTC)
+	next := resumeActivity(req, "c2", lastAt)
+	if next.Cursor != "c2" || next.Limit != 10 {
+		t.Fatalf("expected cursor resume, got %+v", next)
+	}
+
+	next = resumeActivity(req, "", lastAt)
+	if next.Cursor != "" || !next.Since.Equal(lastAt) {
+		t.Fatalf("expected time resume, got %+v", next)
+	}
+
+	var seen activityWatermark
+	seen.add(activityEvent("e1", "2026-10-18T00:00:00.900Z", "allow", "1"))
+	seen.add(activityEvent("e2", "2026-10-18T00:00:01.200Z", "allow", "1"))
+	seen.add(activityEvent("e3", "2026-10-18T00:00:01.500Z", "allow", "1"))
+	if !seen.at.Equal(lastAt) {
+		t.Fatalf("expected watermark %s, got %s", lastAt, seen.at)
+	}
+	// A server comparing whole seconds returns e2 again after resuming at e3.
+	for _, id := range []string{"e2", "e3"} {
+		if !seen.has(activityEvent(id, "", "allow", "1")) {
+			t.Fatalf("expected %s in the watermark second to be seen", id)
+		}
+	}
+	if seen.has(activityEvent("e1", "2026-10-18T00:00:00.900Z", "allow", "1")) {
+		t.Fatalf("expected events before the watermark second to be forgotten")
+	}
+	if seen.has(activityEvent("e4", "2026-10-18T00:00:01.500Z", "allow", "1")) {
+		t.Fatalf("expected e4 to be new")
+	}
+}
+
+func TestActivityPrinters(t *testing.T) {
+	events := []api.AgentActivityEvent{
+		activityEvent("e1", "2026-10-18T00:00:00Z", "allow", "250000"),
+		activityEvent("e2", "2026-10-18T00:00:01Z", "deny", "9000000"),
+		activityEvent("e3", "2026-10-18T00:00:02Z", "allow", "1000000"),
+	}
+
+	var text bytes.Buffer
+	printer := &activityTextPrinter{out: &text}
+	for _, event := range events {
+		if err := printer.Event(event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := printer.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"- at=2026-10-18T00:00:00Z type=purchase resource_url=https://api.example.com/v1/ocr price=250000 amount=0.25 asset=USDC network=base decision=allow",
+		"Total events: 3 (allowed 2, denied 1)",
+		"Spent: 1250000 (1.25 USDC)",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, text.String())
+		}
+	}
+
+	var csvOut bytes.Buffer
+	csvPrinter := newActivityCSVPrinter(&csvOut)
+	if err := csvPrinter.Event(events[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := csvPrinter.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "occurred_at,type,resource_url,price_base_units,amount,asset,network,decision,policy_id,rule_id,event_id\n" +
+		"2026-10-18T00:00:01Z,purchase,https://api.example.com/v1/ocr,9000000,9,USDC,base,deny,,,e2\n"
+	if csvOut.String() != want {
+		t.Fatalf("unexpected CSV:\n%s", csvOut.String())
+	}
+
+	csvOut.Reset()
+	if err := newActivityCSVPrinter(&csvOut).Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(csvOut.String(), "occurred_at,") {
+		t.Fatalf("expected a header for an empty export, got %q", csvOut.String())
+	}
+}
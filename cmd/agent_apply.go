@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/promptingcompany/openspend-cli/internal/agentfile"
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+const defaultAgentApplyConcurrency = 4
+
+func newAgentApplyCmd() *cobra.Command {
+	var file string
+	var dryRun bool
+	var prune bool
+	var force bool
+	var allowEmpty bool
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create and update agents from a YAML, JSON or CSV manifest",
+		Long: strings.TrimSpace(`
+Reconcile agent subjects with a manifest file.
+
+Agents are matched by external key. Missing agents are created and agents
+whose display name, kind or policy differ are updated; empty display names
+and policy IDs in the file leave the server values alone. With --prune,
+agents of kind agent that are not in the file are deleted after confirmation
+(skip it with --force). Anonymous agents are never pruned. An empty manifest
+would prune every agent, so it is refused unless --allow-empty is given.
+
+YAML and JSON manifests list agents under an agents key:
+
+  kind: AgentList
+  agents:
+    - externalKey: buyer-agent-1
+      displayName: Buyer Agent 1
+      policyId: <policy-id>
+
+CSV manifests need a header row naming the columns:
+
+  external_key,display_name,kind,policy_id
+  buyer-agent-1,Buyer Agent 1,agent,<policy-id>
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent apply -f agents.yaml --dry-run
+  openspend dashboard agent apply -f agents.csv --concurrency 8
+  openspend dashboard agent apply -f agents.yaml --prune
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(file) == "" {
+				return fmt.Errorf("--file is required")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+			manifest, err := agentfile.Load(file)
+			if err != nil {
+				return err
+			}
+			if err := checkPruneManifest(manifest, file, prune, allowEmpty); err != nil {
+				return err
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			whoami, err := client.WhoAmI(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+			changes := agentfile.Plan(manifest, existingAgents(whoami), prune)
+
+			if dryRun {
+				printAgentPlan(cmd.OutOrStdout(), changes)
+				return nil
+			}
+
+			if pruned := agentKeys(changes, agentfile.ActionPrune); len(pruned) > 0 && !force {
+				prompt := fmt.Sprintf(
+					"Delete %d agent(s) not in %s (%s)?",
+					len(pruned),
+					file,
+					strings.Join(pruned, ", "),
+				)
+				confirmed, err := confirmAction(cmd, prompt)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+					return nil
+				}
+			}
+
+			errs := applyAgentChanges(cmd.Context(), changes, concurrency, func(ctx context.Context, change agentfile.Change) error {
+				return applyAgentChange(ctx, client, change)
+			})
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			failed := printAgentApplyResults(cmd.OutOrStdout(), changes, errs)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d agent change(s) failed", failed, len(changes))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Agent manifest (.yaml, .yml, .json or .csv)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the planned changes without applying them")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete agents that are not in the manifest")
+	cmd.Flags().BoolVar(&force, "force", false, "Prune without asking for confirmation")
+	cmd.Flags().BoolVar(&allowEmpty, "allow-empty", false, "Allow --prune with a manifest that lists no agents")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultAgentApplyConcurrency, "Maximum number of concurrent requests")
+	return cmd
+}
+
+// checkPruneManifest refuses to prune against a manifest with no agents,
+// such as an empty YAML file or a CSV with only a header, unless allowed.
+func checkPruneManifest(manifest agentfile.Manifest, file string, prune, allowEmpty bool) error {
+	if !prune || allowEmpty || len(manifest.Agents) > 0 {
+		return nil
+	}
+	return fmt.Errorf("%s lists no agents; --prune would delete every agent (pass --allow-empty to do that)", file)
+}
+
+// existingAgents collects agent subjects from whoami, which lists a subject
+// once per policy binding.
+func existingAgents(res api.WhoAmIResponse) []agentfile.Existing {
+	agents := make([]agentfile.Existing, 0, len(res.Subjects))
+	index := make(map[string]int, len(res.Subjects))
+	for _, subject := range res.Subjects {
+		if subject.Kind != "agent" && subject.Kind != "anonymous_agent" {
+			continue
+		}
+		i, seen := index[subject.ID]
+		if !seen {
+			existing := agentfile.Existing{ID: subject.ID, Kind: subject.Kind, Status: subject.Status}
+			if subject.ExternalKey != nil {
+				existing.ExternalKey = strings.TrimSpace(*subject.ExternalKey)
+			}
+			if subject.DisplayName != nil {
+				existing.DisplayName = strings.TrimSpace(*subject.DisplayName)
+			}
+			i = len(agents)
+			index[subject.ID] = i
+			agents = append(agents, existing)
+		}
+		if subject.PolicyID != nil && strings.TrimSpace(*subject.PolicyID) != "" {
+			agents[i].PolicyIDs = append(agents[i].PolicyIDs, strings.TrimSpace(*subject.PolicyID))
+		}
+	}
+	return agents
+}
+
+func applyAgentChange(ctx context.Context, client *api.Client, change agentfile.Change) error {
+	switch change.Action {
+	case agentfile.ActionCreate, agentfile.ActionUpdate:
+		_, err := client.CreateAgent(ctx, api.CreateAgentRequest{
+			ExternalKey: change.Agent.ExternalKey,
+			DisplayName: change.Agent.DisplayName,
+			Kind:        change.Agent.EffectiveKind(),
+			PolicyID:    change.Agent.PolicyID,
+		})
+		return err
+	case agentfile.ActionPrune:
+		return client.DeleteAgent(ctx, change.Existing.ExternalKey)
+	default:
+		return nil
+	}
+}
+
+// applyAgentChanges runs apply for every change that does something, with at
+// most workers calls in flight. Errors are returned in plan order.
+func applyAgentChanges(
+	ctx context.Context,
+	changes []agentfile.Change,
+	workers int,
+	apply func(context.Context, agentfile.Change) error,
+) []error {
+	errs := make([]error, len(changes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = apply(ctx, changes[i])
+			}
+		}()
+	}
+	for i, change := range changes {
+		if change.Action == agentfile.ActionUnchanged {
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return errs
+}
+
+func printAgentPlan(out io.Writer, changes []agentfile.Change) {
+	for _, change := range changes {
+		line := fmt.Sprintf("- key=%s action=%s", change.Key(), change.Action)
+		if len(change.Fields) > 0 {
+			line += " fields=" + strings.Join(change.Fields, ",")
+		}
+		fmt.Fprintln(out, line)
+	}
+	counts := agentfile.Counts(changes)
+	fmt.Fprintf(
+		out,
+		"Plan: %d to create, %d to update, %d unchanged, %d to prune.\n",
+		counts[agentfile.ActionCreate],
+		counts[agentfile.ActionUpdate],
+		counts[agentfile.ActionUnchanged],
+		counts[agentfile.ActionPrune],
+	)
+}
+
+// printAgentApplyResults prints one line per change and returns the number
+// of failures.
+func printAgentApplyResults(out io.Writer, changes []agentfile.Change, errs []error) int {
+	applied := make(map[agentfile.Action]int)
+	failed := 0
+	for i, change := range changes {
+		result := "ok"
+		if errs[i] != nil {
+			result = "error: " + errs[i].Error()
+			failed++
+		} else {
+			applied[change.Action]++
+		}
+		fmt.Fprintf(out, "- key=%s action=%s result=%s\n", change.Key(), change.Action, result)
+	}
+	fmt.Fprintf(
+		out,
+		"Applied: %d created, %d updated, %d unchanged, %d pruned, %d failed.\n",
+		applied[agentfile.ActionCreate],
+		applied[agentfile.ActionUpdate],
+		applied[agentfile.ActionUnchanged],
+		applied[agentfile.ActionPrune],
+		failed,
+	)
+	return failed
+}
+
+func agentKeys(changes []agentfile.Change, action agentfile.Action) []string {
+	keys := make([]string, 0)
+	for _, change := range changes {
+		if change.Action == action {
+			keys = append(keys, change.Key())
+		}
+	}
+	return keys
+}
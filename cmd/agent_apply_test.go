@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/agentfile"
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func TestExistingAgents(t *testing.T) {
+	var res api.WhoAmIResponse
+	if err := json.Unmarshal([]byte(`{"subjects":[
+		{"id":"s1","kind":"agent","externalKey":"buyer-1","displayName":"Buyer","status":"active","policyId":"pol_1"},
+		{"id":"s1","kind":"agent","externalKey":"buyer-1","displayName":"Buyer","status":"active","policyId":"pol_2"},
+		{"id":"s2","kind":"anonymous_agent","status":"active"},
+		{"id":"u1","kind":"user","status":"active","policyId":"pol_1"}
+	]}`), &res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agents := existingAgents(res)
+	if len(agents) != 2 {
+		t.Fatalf("expected 2 agents, got %+v", agents)
+	}
+	if agents[0].ExternalKey != "buyer-1" || strings.Join(agents[0].PolicyIDs, ",") != "pol_1,pol_2" {
+		t.Fatalf("unexpected first agent: %+v", agents[0])
+	}
+	if agents[1].ID != "s2" || agents[1].PolicyIDs != nil {
+		t.Fatalf("unexpected second agent: %+v", agents[1])
+	}
+}
+
+func TestApplyAgentChanges(t *testing.T) {
+	changes := make([]agentfile.Change, 0, 20)
+	for i := 0; i < 20; i++ {
+		action := agentfile.ActionCreate
+		if i%5 == 0 {
+			action = agentfile.ActionUnchanged
+		}
+		changes = append(changes, agentfile.Change{Action: action, Agent: agentfile.Agent{ExternalKey: string(rune('a' + i))}})
+	}
+
+	var mu sync.Mutex
+	inFlight, maxInFlight, calls := 0, 0, 0
+	release := make(chan struct{})
+	go func() {
+		for i := 0; i < 16; i++ {
+			release <- struct{}{}
+		}
+	}()
+
+	errs := applyAgentChanges(context.Background(), changes, 3, func(_ context.Context, change agentfile.Change) error {
+		mu.Lock()
+		inFlight++
+		calls++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		<-release
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if change.Agent.ExternalKey == "b" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	if calls != 16 {
+		t.Fatalf("expected 16 calls (unchanged rows skipped), got %d", calls)
+	}
+	if maxInFlight > 3 {
+		t.Fatalf("expected at most 3 concurrent calls, got %d", maxInFlight)
+	}
+	if errs[1] == nil || errs[0] != nil || errs[2] != nil {
+		t.Fatalf("expected only the second change to fail, got %v", errs)
+	}
+
+	var out bytes.Buffer
+	failed := printAgentApplyResults(&out, changes, errs)
+	if failed != 1 {
+		t.Fatalf("expected 1 failure, got %d", failed)
+	}
+	for _, want := range []string{
+		"- key=b action=create result=error: boom",
+		"- key=a action=unchanged result=ok",
+		"Applied: 15 created, 0 updated, 4 unchanged, 0 pruned, 1 failed.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCheckPruneManifest(t *testing.T) {
+	empty, err := agentfile.Decode([]byte("external_key,display_name,kind,policy_id\n"), agentfile.FormatCSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	one := agentfile.Manifest{Agents: []agentfile.Agent{{ExternalKey: "buyer-1"}}}
+
+	tests := []struct {
+		name       string
+		manifest   agentfile.Manifest
+		prune      bool
+		allowEmpty bool
+		wantErr    bool
+	}{
+		{name: "empty without prune", manifest: empty},
+		{name: "empty with prune", manifest: empty, prune: true, wantErr: true},
+		{name: "empty with prune allowed", manifest: empty, prune: true, allowEmpty: true},
+		{name: "agents with prune", manifest: one, prune: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPruneManifest(tt.manifest, "agents.csv", tt.prune, tt.allowEmpty)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
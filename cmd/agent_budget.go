@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+	"github.com/spf13/cobra"
+)
+
+func newAgentBudgetCmd() *cobra.Command {
+	budgetCmd := &cobra.Command{
+		Use:   "budget",
+		Short: "Manage per-agent spending budgets",
+		Long: strings.TrimSpace(`
+Manage per-agent spending budgets. A budget caps what an agent may spend per
+day and per calendar month in one asset, on top of what its policy allows.
+`),
+	}
+	budgetCmd.AddCommand(newAgentBudgetSetCmd())
+	budgetCmd.AddCommand(newAgentBudgetGetCmd())
+	budgetCmd.AddCommand(newAgentBudgetClearCmd())
+	return budgetCmd
+}
+
+func newAgentBudgetSetCmd() *cobra.Command {
+	var (
+		daily   string
+		monthly string
+		asset   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set <agent-key>",
+		Short: "Set an agent's daily and monthly spending limits",
+		Long: strings.TrimSpace(`
+Set an agent's daily and monthly spending limits. Limits are amounts of
+--asset (20 means 20 USDC); an amount with another unit, such as 25USD, is
+converted through the FX table.
+
+A limit that is not given keeps its current value when the asset is
+unchanged; changing the asset drops it.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent budget set buyer-agent-1 --daily 20 --monthly 200 --asset USDC
+  openspend dashboard agent budget set buyer-agent-1 --monthly 500
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+			if strings.TrimSpace(daily) == "" && strings.TrimSpace(monthly) == "" {
+				return fmt.Errorf("set --daily, --monthly or both")
+			}
+
+			cfg := mustLoadConfig()
+			fx, err := fxTableFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetAgentBudget(cmd.Context(), key)
+			if err != nil && !api.IsStatus(err, http.StatusNotFound) {
+				return err
+			}
+
+			req := api.SetAgentBudgetRequest{Asset: money.NormalizeSymbol(asset)}
+			if existing := current.Budget; existing != nil {
+				if req.Asset == "" {
+					req.Asset = money.NormalizeSymbol(existing.Asset)
+				}
+				if req.Asset == money.NormalizeSymbol(existing.Asset) {
+					req.DailyLimit = existing.DailyLimit
+					req.MonthlyLimit = existing.MonthlyLimit
+				}
+			}
+			if req.Asset == "" {
+				return fmt.Errorf("--asset is required for a new budget")
+			}
+			if strings.TrimSpace(daily) != "" {
+				limit, err := budgetBaseUnits(daily, req.Asset, fx)
+				if err != nil {
+					return fmt.Errorf("--daily: %w", err)
+				}
+				req.DailyLimit = &limit
+			}
+			if strings.TrimSpace(monthly) != "" {
+				limit, err := budgetBaseUnits(monthly, req.Asset, fx)
+				if err != nil {
+					return fmt.Errorf("--monthly: %w", err)
+				}
+				req.MonthlyLimit = &limit
+			}
+
+			res, err := client.SetAgentBudget(cmd.Context(), key, req)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Budget set: key=%s\n", key)
+			budget := res.Budget
+			if budget == nil {
+				budget = &api.AgentBudget{Asset: req.Asset, DailyLimit: req.DailyLimit, MonthlyLimit: req.MonthlyLimit}
+			}
+			printAgentBudget(cmd.OutOrStdout(), budget)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&daily, "daily", "", "Daily limit (for example 20 or 25USD)")
+	cmd.Flags().StringVar(&monthly, "monthly", "", "Monthly limit (for example 200 or 250USD)")
+	cmd.Flags().StringVar(&asset, "asset", "", "Budget asset (for example USDC); defaults to the current budget's asset")
+	return cmd
+}
+
+func newAgentBudgetGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <agent-key>",
+		Short: "Show an agent's spending budget and current spend",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.GetAgentBudget(cmd.Context(), key)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			printAgentBudget(cmd.OutOrStdout(), res.Budget)
+			return nil
+		},
+	}
+}
+
+func newAgentBudgetClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear <agent-key>",
+		Short: "Remove an agent's spending budget",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			if err := client.ClearAgentBudget(cmd.Context(), key); err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Budget cleared: key=%s\n", key)
+			return nil
+		},
+	}
+}
+
+// budgetBaseUnits converts a budget limit to base units of asset. Unlike
+// --max-price, a plain number is an amount of asset rather than base units.
+// Converted limits are rounded down like other caps. Budgets are not tied to
+// a network, so limits always use the asset's default decimals.
+func budgetBaseUnits(raw, asset string, fx money.FXTable) (string, error) {
+	amount, err := money.ParseAmount(raw)
+	if err != nil {
+		return "", err
+	}
+	if amount.Unit == "" {
+		amount.Unit = money.NormalizeSymbol(asset)
+	}
+	base, _, err := money.ResolveBaseUnits(amount.String(), asset, "", fx)
+	if err != nil {
+		return "", err
+	}
+	return base.String(), nil
+}
+
+func printAgentBudget(out io.Writer, budget *api.AgentBudget) {
+	if budget == nil {
+		fmt.Fprintln(out, "Budget: (none)")
+		return
+	}
+	asset := money.NormalizeSymbol(budget.Asset)
+	fmt.Fprintf(out, "Budget asset: %s\n", asset)
+	for _, period := range []struct {
+		name  string
+		limit *string
+		spent *string
+	}{
+		{"Daily", budget.DailyLimit, budget.DailySpent},
+		{"Monthly", budget.MonthlyLimit, budget.MonthlySpent},
+	} {
+		limit := "(none)"
+		if period.limit != nil && strings.TrimSpace(*period.limit) != "" {
+			limit = money.FormatBaseUnits(*period.limit, asset, "")
+		}
+		fmt.Fprintf(out, "%s limit: %s\n", period.name, limit)
+		if period.spent != nil && strings.TrimSpace(*period.spent) != "" {
+			fmt.Fprintf(out, "%s spent: %s\n", period.name, money.FormatBaseUnits(*period.spent, asset, ""))
+		}
+	}
+}
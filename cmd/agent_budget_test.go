@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+func TestBudgetBaseUnits(t *testing.T) {
+	fx, err := money.DefaultFX().With(map[string]float64{"EUR": 1.25, "ETH": 3000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		raw     string
+		asset   string
+		want    string
+		wantErr bool
+	}{
+		{raw: "20", asset: "USDC", want: "20000000"},
+		{raw: "0.5USDC", asset: "USDC", want: "500000"},
+		{raw: "4EUR", asset: "USDC", want: "5000000"},
+		{raw: "25USD", asset: "ETH", want: "8333333333333333"},
+		{raw: "0.0000001", asset: "USDC", wantErr: true},
+		{raw: "twenty", asset: "USDC", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := budgetBaseUnits(tt.raw, tt.asset, fx)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error, got %s", tt.raw, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.raw, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: expected %s, got %s", tt.raw, tt.want, got)
+		}
+	}
+}
+
+func TestPrintAgentBudget(t *testing.T) {
+	limit, spent := "20000000", "19500000"
+	var out bytes.Buffer
+	printAgentBudget(&out, &api.AgentBudget{Asset: "usdc", DailyLimit: &limit, DailySpent: &spent})
+	for _, want := range []string{
+		"Budget asset: USDC",
+		"Daily limit: 20000000 (20 USDC)",
+		"Daily spent: 19500000 (19.5 USDC)",
+		"Monthly limit: (none)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printAgentBudget(&out, nil)
+	if out.String() != "Budget: (none)\n" {
+		t.Fatalf("unexpected output for no budget: %q", out.String())
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/agentkey"
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func newAgentClaimCmd() *cobra.Command {
+	var externalKey string
+	var displayName string
+	var keyPrefix string
+
+	cmd := &cobra.Command{
+		Use:   "claim <anonymous-subject-id>",
+		Short: "Promote an anonymous agent to an identified agent",
+		Long: strings.TrimSpace(`
+Promote an anonymous agent to an identified agent by giving it an external key.
+The subject keeps its ID, activity history and policy bindings, and starts
+passing policy rules that require an identified agent.
+
+Without --external-key a <prefix>-<ulid> key is generated.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent list --kind anonymous_agent
+  openspend dashboard agent claim <subject-id> --external-key buyer-agent-7 --display-name "Buyer Agent 7"
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subjectID := strings.TrimSpace(args[0])
+			if subjectID == "" {
+				return fmt.Errorf("subject ID is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetAgent(cmd.Context(), subjectID)
+			if err != nil {
+				return err
+			}
+			if current.Subject.Kind != agentKindAnonymous {
+				return fmt.Errorf(
+					"subject %s is a %s, not an anonymous agent",
+					subjectID,
+					current.Subject.Kind,
+				)
+			}
+
+			req := api.ClaimAgentRequest{
+				ExternalKey: strings.TrimSpace(externalKey),
+				DisplayName: strings.TrimSpace(displayName),
+			}
+			var res api.AgentDetailsResponse
+			if req.ExternalKey == "" {
+				key, err := withGeneratedAgentKey(cmd.Context(), client, agentkey.Options{
+					Strategy: agentkey.StrategyULID,
+					Prefix:   keyPrefix,
+				}, func(key string) error {
+					req.ExternalKey = key
+					var err error
+					res, err = client.ClaimAgent(cmd.Context(), subjectID, req)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "No --external-key provided; using generated key: %s\n", key)
+			} else {
+				res, err = client.ClaimAgent(cmd.Context(), subjectID, req)
+				if err != nil {
+					return err
+				}
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Agent claimed: id=%s key=%s name=%s kind=%s bindings=%d\n",
+				res.Subject.ID,
+				res.Subject.ExternalKey,
+				agentLabel(res.Subject),
+				res.Subject.Kind,
+				len(res.Bindings),
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&externalKey, "external-key", "", "External key for the agent (generated if omitted)")
+	cmd.Flags().StringVar(&displayName, "display-name", "", "Display name")
+	cmd.Flags().StringVar(&keyPrefix, "key-prefix", agentkey.DefaultPrefix, "Prefix for a generated key")
+	return cmd
+}
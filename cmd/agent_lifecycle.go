@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+const (
+	agentStatusActive   = "active"
+	agentStatusDisabled = "disabled"
+)
+
+func newAgentDescribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe <agent-key>",
+		Short: "Describe an agent subject and its bound policies",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.GetAgent(cmd.Context(), key)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			printAgentDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+}
+
+// newAgentStatusCmd builds agent enable and agent disable.
+func newAgentStatusCmd(name, status, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   name + " <agent-key>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.SetAgentStatus(cmd.Context(), key, status)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Agent %sd: id=%s key=%s status=%s\n",
+				name,
+				res.Subject.ID,
+				res.Subject.ExternalKey,
+				res.Subject.Status,
+			)
+			return nil
+		},
+	}
+}
+
+func newAgentDeleteCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <agent-key>",
+		Short: "Delete an agent subject",
+		Long: strings.TrimSpace(`
+Delete an agent subject and its policy bindings. Asks for confirmation unless
+--force is given. Use agent disable to cut an agent off while keeping it.
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetAgent(cmd.Context(), key)
+			if err != nil {
+				return err
+			}
+
+			if !force {
+				prompt := fmt.Sprintf(
+					"Delete agent %s (%s) and its %d policy binding(s)? This cannot be undone.",
+					agentLabel(current.Subject),
+					current.Subject.ID,
+					len(current.Bindings),
+				)
+				confirmed, err := confirmAction(cmd, prompt)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+					return nil
+				}
+			}
+
+			if err := client.DeleteAgent(cmd.Context(), key); err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Agent deleted: %s\n", key)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Delete without asking for confirmation")
+	return cmd
+}
+
+func printAgentDetails(out io.Writer, res api.AgentDetailsResponse) {
+	subject := res.Subject
+	displayName := "(none)"
+	if subject.DisplayName != nil && strings.TrimSpace(*subject.DisplayName) != "" {
+		displayName = strings.TrimSpace(*subject.DisplayName)
+	}
+	lastSeen := "(never)"
+	if subject.LastSeenAt != nil && strings.TrimSpace(*subject.LastSeenAt) != "" {
+		lastSeen = strings.TrimSpace(*subject.LastSeenAt)
+	}
+
+	fmt.Fprintf(out, "Agent: %s (%s)\n", displayName, subject.ID)
+	fmt.Fprintf(out, "External key: %s\n", subject.ExternalKey)
+	fmt.Fprintf(out, "Kind: %s\n", subject.Kind)
+	fmt.Fprintf(out, "Status: %s\n", subject.Status)
+	fmt.Fprintf(out, "Created: %s\n", subject.CreatedAt)
+	fmt.Fprintf(out, "Last seen: %s\n", lastSeen)
+	if subject.Status == agentStatusDisabled {
+		fmt.Fprintln(out, "Note: disabled agents are refused regardless of policy.")
+	}
+
+	bindings := make([]policy.Binding, 0, len(res.Bindings))
+	for _, b := range res.Bindings {
+		bindings = append(bindings, policy.Binding{
+			PolicyID:     b.PolicyID,
+			PolicyName:   b.PolicyName,
+			PolicyStatus: b.PolicyStatus,
+			Precedence:   b.Precedence,
+			Active:       b.Active,
+		})
+	}
+	printSubjectResolution(out, policy.ResolveBindings(bindings))
+	printAgentBudget(out, res.Budget)
+}
+
+func agentLabel(subject api.AgentSubject) string {
+	if subject.DisplayName != nil && strings.TrimSpace(*subject.DisplayName) != "" {
+		return strings.TrimSpace(*subject.DisplayName)
+	}
+	if strings.TrimSpace(subject.ExternalKey) != "" {
+		return strings.TrimSpace(subject.ExternalKey)
+	}
+	return subject.ID
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func TestPrintAgentDetails(t *testing.T) {
+	name := "Buyer Agent"
+	res := api.AgentDetailsResponse{
+		Subject: api.AgentSubject{
+			ID:          "sub_1",
+			ExternalKey: "buyer-agent-1",
+			DisplayName: &name,
+			Kind:        "agent",
+			Status:      agentStatusDisabled,
+			CreatedAt:   "2026-01-02T03:04:05Z",
+		},
+		Bindings: []api.AgentPolicyBinding{
+			{PolicyID: "pol_2", PolicyName: "Fallback", PolicyStatus: "active", Precedence: 100, Active: true},
+			{PolicyID: "pol_1", PolicyName: "Team", PolicyStatus: "active", Precedence: 10, Active: true},
+		},
+	}
+
+	var out bytes.Buffer
+	printAgentDetails(&out, res)
+	text := out.String()
+	for _, want := range []string{
+		"Agent: Buyer Agent (sub_1)",
+		"External key: buyer-agent-1",
+		"Status: disabled",
+		"Last seen: (never)",
+		"disabled agents are refused",
+		"1. policy_id=pol_1",
+		"Effective policy: pol_1 (Team)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestAgentLabel(t *testing.T) {
+	name := "  "
+	tests := []struct {
+		subject api.AgentSubject
+		want    string
+	}{
+		{subject: api.AgentSubject{ID: "sub_1", ExternalKey: "key-1", DisplayName: &name}, want: "key-1"},
+		{subject: api.AgentSubject{ID: "sub_1"}, want: "sub_1"},
+	}
+	for _, tt := range tests {
+		if got := agentLabel(tt.subject); got != tt.want {
+			t.Fatalf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
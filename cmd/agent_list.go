@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	agentSortName     = "name"
+	agentSortCreated  = "created"
+	agentSortLastSeen = "last-seen"
+)
+
+func newAgentListCmd() *cobra.Command {
+	var (
+		status   string
+		kind     string
+		policyID string
+		search   string
+		sortBy   string
+		cursor   string
+		limit    int
+		all      bool
+		idleRaw  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List agent subjects for current user",
+		Long: strings.TrimSpace(`
+List agent subjects for the current user, one line per policy binding.
+Filters, sorting and paging are applied by the server; pass --cursor from a
+previous page to continue, or --all to fetch every page. With servers that
+predate the agent list endpoint, agents are derived from whoami subjects
+instead and filtered locally, and every agent is listed at once.
+
+--search matches the external key, display name or ID, case-insensitively.
+--sort last-seen lists the most recently seen agents first.
+
+--idle lists only agents not seen for that long (agents never seen count from
+when they were created), for example to find anonymous agents to clean up
+with agent prune.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent list
+  openspend dashboard agent list --status disabled --search buyer --sort last-seen
+  openspend dashboard agent list --policy-id <policy-id> --all
+  openspend dashboard agent list --kind anonymous_agent --idle 30d
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			req := api.ListAgentsRequest{
+				Status:   strings.ToLower(strings.TrimSpace(status)),
+				Kind:     strings.TrimSpace(kind),
+				PolicyID: strings.TrimSpace(policyID),
+				Search:   strings.TrimSpace(search),
+				Sort:     strings.ToLower(strings.TrimSpace(sortBy)),
+				Cursor:   strings.TrimSpace(cursor),
+				Limit:    limit,
+			}
+			switch req.Status {
+			case "", agentStatusActive, agentStatusDisabled:
+			default:
+				return fmt.Errorf("--status must be one of: %s, %s", agentStatusActive, agentStatusDisabled)
+			}
+			if err := validateAgentKind(req.Kind); err != nil {
+				return err
+			}
+			switch req.Sort {
+			case agentSortName, agentSortCreated, agentSortLastSeen:
+			default:
+				return fmt.Errorf("--sort must be one of: %s, %s, %s", agentSortName, agentSortCreated, agentSortLastSeen)
+			}
+			if limit <= 0 {
+				return fmt.Errorf("--limit must be positive")
+			}
+			if all && req.Cursor != "" {
+				return fmt.Errorf("--all and --cursor cannot be used together")
+			}
+			var idle time.Duration
+			if strings.TrimSpace(idleRaw) != "" {
+				var err error
+				if idle, err = parseDuration(idleRaw); err != nil {
+					return fmt.Errorf("--idle: %w", err)
+				}
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			agents, next, err := listAgents(cmd, client, req, all)
+			if api.IsStatus(err, http.StatusNotFound, http.StatusMethodNotAllowed) {
+				if req.Cursor != "" {
+					return fmt.Errorf("--cursor is not supported by this server")
+				}
+				fmt.Fprintln(cmd.ErrOrStderr(), "Agent list endpoint unavailable; deriving agents from whoami subjects.")
+				agents, err = listAgentsFromWhoAmI(cmd, client, req)
+			}
+			if err != nil {
+				return err
+			}
+			if idle > 0 {
+				agents, err = filterIdleAgents(cmd, client, agents, time.Now().Add(-idle))
+				if err != nil {
+					return err
+				}
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if len(agents) == 0 && next == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), "No agents found.")
+				return nil
+			}
+			for _, agent := range agents {
+				fmt.Fprintf(
+					cmd.OutOrStdout(),
+					"- id=%s key=%s name=%s kind=%s status=%s policy=%s policy_id=%s",
+					agent.ID,
+					agent.ExternalKey,
+					agent.DisplayName,
+					agent.Kind,
+					agent.Status,
+					agent.PolicyName,
+					agent.PolicyID,
+				)
+				if idle > 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), " last_seen=%s", agentLastSeenLabel(agent))
+				}
+				fmt.Fprintln(cmd.OutOrStdout())
+			}
+			if next != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Showing %d agents (next page: --cursor %s)\n", len(agents), next)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Total agents: %d\n", len(agents))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&status, "status", "", "Only agents with this status (active|disabled)")
+	cmd.Flags().StringVar(&kind, "kind", "", "Only agents of this kind (agent|anonymous_agent)")
+	cmd.Flags().StringVar(&policyID, "policy-id", "", "Only agents bound to this policy")
+	cmd.Flags().StringVar(&search, "search", "", "Only agents whose key, name or ID contains this text (case-insensitive)")
+	cmd.Flags().StringVar(&sortBy, "sort", agentSortName, "Sort order (name|created|last-seen)")
+	cmd.Flags().StringVar(&cursor, "cursor", "", "Continue from the cursor printed with a previous page")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Page size")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page")
+	cmd.Flags().StringVar(&idleRaw, "idle", "", "Only agents not seen for this long (for example 30d)")
+	return cmd
+}
+
+// listAgents returns one page, or every page when all is set, along with the
+// cursor of the next page ("" on the last page).
+func listAgents(
+	cmd *cobra.Command,
+	client *api.Client,
+	req api.ListAgentsRequest,
+	all bool,
+) ([]agentSummary, string, error) {
+	if !all {
+		res, err := client.ListAgents(cmd.Context(), req)
+		if err != nil {
+			return nil, "", err
+		}
+		next := ""
+		if res.HasMore {
+			next = derefTrimmed(res.NextCursor)
+		}
+		return agentSummariesFromList(res.Agents), next, nil
+	}
+
+	items := make([]api.AgentListItem, 0)
+	it := client.Agents(req)
+	for it.Next(cmd.Context()) {
+		items = append(items, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, "", err
+	}
+	return agentSummariesFromList(items), "", nil
+}
+
+// listAgentsFromWhoAmI lists agents from whoami subjects, for servers without
+// the agent list endpoint. Filters and sorting are applied locally.
+func listAgentsFromWhoAmI(cmd *cobra.Command, client *api.Client, req api.ListAgentsRequest) ([]agentSummary, error) {
+	res, err := client.WhoAmI(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	agents := filterAgents(filterAgentsByKind(agentSummariesFromWhoAmI(res), req.Kind), req)
+	sortAgents(agents, req.Sort)
+	return agents, nil
+}
+
+// agentSummariesFromList flattens agents to one row per policy binding, or a
+// single row for an agent with no bindings.
+func agentSummariesFromList(items []api.AgentListItem) []agentSummary {
+	agents := make([]agentSummary, 0, len(items))
+	for _, item := range items {
+		agent := agentSummary{
+			ID:          item.ID,
+			ExternalKey: strings.TrimSpace(item.ExternalKey),
+			DisplayName: derefTrimmed(item.DisplayName),
+			Kind:        item.Kind,
+			Status:      item.Status,
+			CreatedAt:   strings.TrimSpace(item.CreatedAt),
+			LastSeenAt:  derefTrimmed(item.LastSeenAt),
+		}
+		if len(item.Bindings) == 0 {
+			agents = append(agents, agent)
+			continue
+		}
+		for _, binding := range item.Bindings {
+			agent.PolicyID = binding.PolicyID
+			agent.PolicyName = binding.PolicyName
+			agents = append(agents, agent)
+		}
+	}
+	return agents
+}
+
+// filterAgents applies the status, policy and search filters of req.
+func filterAgents(agents []agentSummary, req api.ListAgentsRequest) []agentSummary {
+	search := strings.ToLower(req.Search)
+	out := make([]agentSummary, 0, len(agents))
+	for _, agent := range agents {
+		if req.Status != "" && agent.Status != req.Status {
+			continue
+		}
+		if req.PolicyID != "" && agent.PolicyID != req.PolicyID {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(agent.ExternalKey), search) &&
+			!strings.Contains(strings.ToLower(agent.DisplayName), search) &&
+			!strings.Contains(strings.ToLower(agent.ID), search) {
+			continue
+		}
+		out = append(out, agent)
+	}
+	return out
+}
+
+// sortAgents orders agents by name (display name, else external key, else
+// ID), by creation time, or by last-seen time with the most recent first and
+// never-seen agents last. Ties keep their order.
+func sortAgents(agents []agentSummary, by string) {
+	name := func(agent agentSummary) string {
+		for _, value := range []string{agent.DisplayName, agent.ExternalKey, agent.ID} {
+			if value != "" {
+				return strings.ToLower(value)
+			}
+		}
+		return ""
+	}
+	parse := func(raw string) (time.Time, bool) {
+		t, err := time.Parse(time.RFC3339, raw)
+		return t, err == nil
+	}
+
+	sort.SliceStable(agents, func(i, j int) bool {
+		switch by {
+		case agentSortCreated:
+			a, aok := parse(agents[i].CreatedAt)
+			b, bok := parse(agents[j].CreatedAt)
+			if aok != bok {
+				return aok
+			}
+			return a.Before(b)
+		case agentSortLastSeen:
+			a, aok := parse(agents[i].LastSeenAt)
+			b, bok := parse(agents[j].LastSeenAt)
+			if aok != bok {
+				return aok
+			}
+			return a.After(b)
+		default:
+			return name(agents[i]) < name(agents[j])
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func TestAgentSummariesFromList(t *testing.T) {
+	var res api.ListAgentsResponse
+	if err := json.Unmarshal([]byte(`{"agents":[
+		{"id":"s1","externalKey":"buyer-1","displayName":" Buyer ","kind":"agent","status":"active","createdAt":"2026-01-01T00:00:00Z",
+		 "bindings":[{"policyId":"pol_1","policyName":"Team"},{"policyId":"pol_2","policyName":"Fallback"}]},
+		{"id":"s2","kind":"anonymous_agent","status":"active","lastSeenAt":"2026-10-01T00:00:00Z","bindings":[]}
+	],"nextCursor":"c2","hasMore":true}`), &res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agents := agentSummariesFromList(res.Agents)
+	if len(agents) != 3 {
+		t.Fatalf("expected one row per binding, got %+v", agents)
+	}
+	if agents[0].PolicyID != "pol_1" || agents[1].PolicyID != "pol_2" || agents[1].DisplayName != "Buyer" {
+		t.Fatalf("unexpected binding rows: %+v", agents[:2])
+	}
+	if agents[2].ID != "s2" || agents[2].PolicyID != "" || agents[2].LastSeenAt != "2026-10-01T00:00:00Z" {
+		t.Fatalf("unexpected unbound row: %+v", agents[2])
+	}
+}
+
+func TestFilterAgents(t *testing.T) {
+	agents := []agentSummary{
+		{ID: "s1", ExternalKey: "buyer-1", Status: "active", PolicyID: "pol_1"},
+		{ID: "s2", DisplayName: "Research Buyer", Status: "disabled", PolicyID: "pol_2"},
+		{ID: "s3", ExternalKey: "seller-1", Status: "active", PolicyID: "pol_1"},
+	}
+
+	tests := []struct {
+		name string
+		req  api.ListAgentsRequest
+		want string
+	}{
+		{name: "no filters", want: "s1,s2,s3"},
+		{name: "status", req: api.ListAgentsRequest{Status: "active"}, want: "s1,s3"},
+		{name: "policy", req: api.ListAgentsRequest{PolicyID: "pol_2"}, want: "s2"},
+		{name: "search matches key and name", req: api.ListAgentsRequest{Search: "BUYER"}, want: "s1,s2"},
+		{name: "search matches id", req: api.ListAgentsRequest{Search: "s3"}, want: "s3"},
+		{name: "combined", req: api.ListAgentsRequest{Status: "active", Search: "buyer"}, want: "s1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := agentIDs(filterAgents(agents, tt.req)); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSortAgents(t *testing.T) {
+	agents := []agentSummary{
+		{ID: "s1", ExternalKey: "charlie", CreatedAt: "2026-03-01T00:00:00Z", LastSeenAt: "2026-10-01T00:00:00Z"},
+		{ID: "s2", DisplayName: "Alpha", CreatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "s3", ExternalKey: "bravo", CreatedAt: "2026-02-01T00:00:00Z", LastSeenAt: "2026-10-10T00:00:00Z"},
+	}
+
+	tests := []struct {
+		by   string
+		want string
+	}{
+		{by: agentSortName, want: "s2,s3,s1"},
+		{by: agentSortCreated, want: "s2,s3,s1"},
+		{by: agentSortLastSeen, want: "s3,s1,s2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			sorted := append([]agentSummary(nil), agents...)
+			sortAgents(sorted, tt.by)
+			if got := agentIDs(sorted); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func agentIDs(agents []agentSummary) string {
+	ids := make([]string, 0, len(agents))
+	for _, agent := range agents {
+		ids = append(ids, agent.ID)
+	}
+	return strings.Join(ids, ",")
+}
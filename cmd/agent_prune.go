@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/agentfile"
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	agentKindAgent     = "agent"
+	agentKindAnonymous = "anonymous_agent"
+)
+
+// agentSummary is one whoami agent row, flattened. Whoami lists a subject
+// once per policy binding.
+type agentSummary struct {
+	ID          string
+	ExternalKey string
+	DisplayName string
+	Kind        string
+	Status      string
+	PolicyID    string
+	PolicyName  string
+	CreatedAt   string
+	LastSeenAt  string
+}
+
+func newAgentPruneCmd() *cobra.Command {
+	var (
+		kind        string
+		idleRaw     string
+		dryRun      bool
+		force       bool
+		concurrency int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete agents that have been idle for a while",
+		Long: strings.TrimSpace(`
+Delete agents that have not been seen for --idle (agents never seen count from
+when they were created). Only anonymous agents are pruned unless --kind says
+otherwise; policies that require an identified agent refuse anonymous ones
+anyway. Use agent claim to keep an anonymous agent.
+
+Lists the agents and asks for confirmation unless --force is given.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent prune --idle 30d --dry-run
+  openspend dashboard agent prune --idle 30d --force
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			kind = strings.TrimSpace(kind)
+			if err := validateAgentKind(kind); err != nil {
+				return err
+			}
+			if strings.TrimSpace(idleRaw) == "" {
+				return fmt.Errorf("--idle is required")
+			}
+			idle, err := parseDuration(idleRaw)
+			if err != nil {
+				return fmt.Errorf("--idle: %w", err)
+			}
+			if idle <= 0 {
+				return fmt.Errorf("--idle must be positive")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.WhoAmI(cmd.Context())
+			if err != nil {
+				return err
+			}
+			agents := uniqueAgents(filterAgentsByKind(agentSummariesFromWhoAmI(res), kind))
+			agents, err = filterIdleAgents(cmd, client, agents, time.Now().Add(-idle))
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if len(agents) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No idle agents found.")
+				return nil
+			}
+			for _, agent := range agents {
+				fmt.Fprintf(
+					cmd.OutOrStdout(),
+					"- id=%s key=%s kind=%s last_seen=%s\n",
+					agent.ID,
+					agent.ExternalKey,
+					agent.Kind,
+					agentLastSeenLabel(agent),
+				)
+			}
+			if dryRun {
+				fmt.Fprintf(cmd.OutOrStdout(), "Would delete %d agent(s).\n", len(agents))
+				return nil
+			}
+
+			if !force {
+				prompt := fmt.Sprintf("Delete %d idle agent(s) and their policy bindings? This cannot be undone.", len(agents))
+				confirmed, err := confirmAction(cmd, prompt)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+					return nil
+				}
+			}
+
+			changes := make([]agentfile.Change, 0, len(agents))
+			for _, agent := range agents {
+				changes = append(changes, agentfile.Change{
+					Action: agentfile.ActionPrune,
+					Existing: &agentfile.Existing{
+						ID:          agent.ID,
+						ExternalKey: agent.ExternalKey,
+						Kind:        agent.Kind,
+						Status:      agent.Status,
+					},
+				})
+			}
+			// Anonymous agents have no external key, so delete by ID.
+			errs := applyAgentChanges(cmd.Context(), changes, concurrency, func(ctx context.Context, change agentfile.Change) error {
+				return client.DeleteAgent(ctx, change.Existing.ID)
+			})
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			failed := printAgentPruneResults(cmd.OutOrStdout(), agents, errs)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d agent deletion(s) failed", failed, len(agents))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kind, "kind", agentKindAnonymous, "Only agents of this kind (agent|anonymous_agent, empty for both)")
+	cmd.Flags().StringVar(&idleRaw, "idle", "", "Delete agents not seen for this long (for example 30d)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the agents that would be deleted")
+	cmd.Flags().BoolVar(&force, "force", false, "Delete without asking for confirmation")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultAgentApplyConcurrency, "Maximum number of concurrent requests")
+	return cmd
+}
+
+func printAgentPruneResults(out io.Writer, agents []agentSummary, errs []error) int {
+	failed := 0
+	for i, agent := range agents {
+		result := "ok"
+		if errs[i] != nil {
+			result = "error: " + errs[i].Error()
+			failed++
+		}
+		fmt.Fprintf(out, "- id=%s key=%s result=%s\n", agent.ID, agent.ExternalKey, result)
+	}
+	fmt.Fprintf(out, "Pruned: %d deleted, %d failed.\n", len(agents)-failed, failed)
+	return failed
+}
+
+func validateAgentKind(kind string) error {
+	switch kind {
+	case "", agentKindAgent, agentKindAnonymous:
+		return nil
+	default:
+		return fmt.Errorf("--kind must be one of: %s, %s", agentKindAgent, agentKindAnonymous)
+	}
+}
+
+// agentSummariesFromWhoAmI returns the agent rows of a whoami response.
+func agentSummariesFromWhoAmI(res api.WhoAmIResponse) []agentSummary {
+	agents := make([]agentSummary, 0, len(res.Subjects))
+	for _, subject := range res.Subjects {
+		if subject.Kind != agentKindAgent && subject.Kind != agentKindAnonymous {
+			continue
+		}
+		agents = append(agents, agentSummary{
+			ID:          subject.ID,
+			ExternalKey: derefTrimmed(subject.ExternalKey),
+			DisplayName: derefTrimmed(subject.DisplayName),
+			Kind:        subject.Kind,
+			Status:      subject.Status,
+			PolicyID:    derefTrimmed(subject.PolicyID),
+			PolicyName:  derefTrimmed(subject.PolicyName),
+			CreatedAt:   derefTrimmed(subject.CreatedAt),
+			LastSeenAt:  derefTrimmed(subject.LastSeenAt),
+		})
+	}
+	return agents
+}
+
+// filterAgentsByKind keeps agents of kind; an empty kind keeps all.
+func filterAgentsByKind(agents []agentSummary, kind string) []agentSummary {
+	if kind == "" {
+		return agents
+	}
+	out := make([]agentSummary, 0, len(agents))
+	for _, agent := range agents {
+		if agent.Kind == kind {
+			out = append(out, agent)
+		}
+	}
+	return out
+}
+
+// uniqueAgents keeps the first row of each subject.
+func uniqueAgents(agents []agentSummary) []agentSummary {
+	seen := make(map[string]bool, len(agents))
+	out := make([]agentSummary, 0, len(agents))
+	for _, agent := range agents {
+		if seen[agent.ID] {
+			continue
+		}
+		seen[agent.ID] = true
+		out = append(out, agent)
+	}
+	return out
+}
+
+// filterIdleAgents keeps agents idle since before cutoff. Agents whose
+// timestamps whoami omits are looked up one by one; agents whose activity is
+// still unknown are skipped with a warning.
+func filterIdleAgents(cmd *cobra.Command, client *api.Client, agents []agentSummary, cutoff time.Time) ([]agentSummary, error) {
+	details := make(map[string]api.AgentSubject)
+	for _, agent := range agents {
+		if agent.CreatedAt != "" || agent.LastSeenAt != "" {
+			continue
+		}
+		if _, ok := details[agent.ID]; ok {
+			continue
+		}
+		res, err := client.GetAgent(cmd.Context(), agent.ID)
+		if err != nil {
+			return nil, fmt.Errorf("look up agent %s: %w", agent.ID, err)
+		}
+		details[agent.ID] = res.Subject
+	}
+
+	out := make([]agentSummary, 0, len(agents))
+	unknown := 0
+	for _, agent := range agents {
+		if subject, ok := details[agent.ID]; ok {
+			agent.CreatedAt = strings.TrimSpace(subject.CreatedAt)
+			agent.LastSeenAt = derefTrimmed(subject.LastSeenAt)
+		}
+		since, ok := agentIdleSince(agent)
+		if !ok {
+			unknown++
+			continue
+		}
+		if since.Before(cutoff) {
+			out = append(out, agent)
+		}
+	}
+	if unknown > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipped %d agent(s) with no last-seen or creation time.\n", unknown)
+	}
+	return out, nil
+}
+
+// agentIdleSince returns when the agent was last seen, or created when it
+// was never seen.
+func agentIdleSince(agent agentSummary) (time.Time, bool) {
+	for _, raw := range []string{agent.LastSeenAt, agent.CreatedAt} {
+		if raw == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func agentLastSeenLabel(agent agentSummary) string {
+	if agent.LastSeenAt != "" {
+		return agent.LastSeenAt
+	}
+	return "(never)"
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func TestAgentSummariesFromWhoAmI(t *testing.T) {
+	var res api.WhoAmIResponse
+	if err := json.Unmarshal([]byte(`{"subjects":[
+		{"id":"s1","kind":"agent","externalKey":" buyer-1 ","status":"active","policyId":"pol_1","lastSeenAt":"2026-10-01T00:00:00Z"},
+		{"id":"s1","kind":"agent","externalKey":"buyer-1","status":"active","policyId":"pol_2"},
+		{"id":"s2","kind":"anonymous_agent","status":"active","createdAt":"2026-08-01T00:00:00Z"},
+		{"id":"u1","kind":"user","status":"active"}
+	]}`), &res); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agents := agentSummariesFromWhoAmI(res)
+	if len(agents) != 3 || agents[0].ExternalKey != "buyer-1" || agents[0].LastSeenAt != "2026-10-01T00:00:00Z" {
+		t.Fatalf("unexpected agents: %+v", agents)
+	}
+	if unique := uniqueAgents(agents); len(unique) != 2 || unique[0].PolicyID != "pol_1" {
+		t.Fatalf("expected the first row per subject, got %+v", unique)
+	}
+	if anonymous := filterAgentsByKind(agents, agentKindAnonymous); len(anonymous) != 1 || anonymous[0].ID != "s2" {
+		t.Fatalf("unexpected anonymous agents: %+v", anonymous)
+	}
+	if all := filterAgentsByKind(agents, ""); len(all) != 3 {
+		t.Fatalf("expected an empty kind to keep all agents, got %+v", all)
+	}
+}
+
+func TestFilterIdleAgents(t *testing.T) {
+	agents := []agentSummary{
+		{ID: "recent", LastSeenAt: "2026-10-17T00:00:00Z", CreatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "stale", LastSeenAt: "2026-09-01T00:00:00Z"},
+		{ID: "never-seen-old", CreatedAt: "2026-08-01T00:00:00Z"},
+		{ID: "never-seen-new", CreatedAt: "2026-10-10T00:00:00Z"},
+		{ID: "bad-time", LastSeenAt: "yesterday"},
+	}
+	cmd := &cobra.Command{}
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	cutoff := time.Date(2026, 9, 18, 0, 0, 0, 0, time.UTC)
+	idle, err := filterIdleAgents(cmd, nil, agents, cutoff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(idle) != 2 || idle[0].ID != "stale" || idle[1].ID != "never-seen-old" {
+		t.Fatalf("unexpected idle agents: %+v", idle)
+	}
+	if !strings.Contains(stderr.String(), "skipped 1 agent(s)") {
+		t.Fatalf("expected a warning for the unparsable time, got %q", stderr.String())
+	}
+	if agentLastSeenLabel(idle[1]) != "(never)" {
+		t.Fatalf("expected (never), got %s", agentLastSeenLabel(idle[1]))
+	}
+}
+
+func TestPrintAgentPruneResults(t *testing.T) {
+	agents := []agentSummary{{ID: "s1"}, {ID: "s2", ExternalKey: "buyer-2"}}
+	var out bytes.Buffer
+	failed := printAgentPruneResults(&out, agents, []error{nil, errors.New("boom")})
+	if failed != 1 {
+		t.Fatalf("expected 1 failure, got %d", failed)
+	}
+	want := "- id=s1 key= result=ok\n- id=s2 key=buyer-2 result=error: boom\nPruned: 1 deleted, 1 failed.\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
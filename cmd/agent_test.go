@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/agentkey"
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func TestWithGeneratedAgentKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"subjects":[{"id":"s1","kind":"agent","externalKey":"agent-1","status":"active"}]}`)
+	}))
+	defer server.Close()
+	client := api.New(api.Options{BaseURL: server.URL, SessionToken: "tok", AuthTokenType: "bearer"})
+	conflict := &api.StatusError{Operation: "agent create", StatusCode: http.StatusConflict}
+
+	t.Run("retries keys taken in the meantime", func(t *testing.T) {
+		var tried []string
+		key, err := withGeneratedAgentKey(context.Background(), client, agentkey.Options{
+			Strategy: agentkey.StrategyTemplate,
+			Template: "agent-{{.Seq}}",
+		}, func(key string) error {
+			tried = append(tried, key)
+			if key == "agent-2" {
+				return conflict
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if key != "agent-3" || strings.Join(tried, ",") != "agent-2,agent-3" {
+			t.Fatalf("expected agent-3 after agent-2 conflicted, got %s (tried %v)", key, tried)
+		}
+	})
+
+	t.Run("deterministic key taken", func(t *testing.T) {
+		_, err := withGeneratedAgentKey(context.Background(), client, agentkey.Options{
+			Strategy: agentkey.StrategyHash,
+			Attrs:    map[string]string{"team": "payments"},
+		}, func(string) error { return conflict })
+		if !errors.Is(err, agentkey.ErrTaken) {
+			t.Fatalf("expected ErrTaken, got %v", err)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		attempts := 0
+		_, err := withGeneratedAgentKey(context.Background(), client, agentkey.Options{Strategy: agentkey.StrategyULID}, func(string) error {
+			attempts++
+			return conflict
+		})
+		if err == nil || attempts != agentKeyAttempts {
+			t.Fatalf("expected an error after %d attempts, got %v after %d", agentKeyAttempts, err, attempts)
+		}
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		boom := errors.New("boom")
+		_, err := withGeneratedAgentKey(context.Background(), client, agentkey.Options{Strategy: agentkey.StrategyULID}, func(string) error {
+			return boom
+		})
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected boom, got %v", err)
+		}
+	})
+}
+
+func TestResolveAgentKeyStrategy(t *testing.T) {
+	tests := []struct {
+		raw      string
+		template string
+		want     agentkey.Strategy
+		wantErr  string
+	}{
+		{want: agentkey.StrategyULID},
+		{template: "{{.Team}}-{{.Seq}}", want: agentkey.StrategyTemplate},
+		{raw: "hash", want: agentkey.StrategyHash},
+		{raw: "template", template: "{{.Team}}-{{.Seq}}", want: agentkey.StrategyTemplate},
+		{raw: "template", wantErr: "requires --key-template"},
+		{raw: "ulid", template: "{{.Team}}-{{.Seq}}", wantErr: "--key-template requires --key-strategy template"},
+		{raw: "hash", template: "{{.Team}}", wantErr: "--key-template requires --key-strategy template"},
+		{raw: "random", wantErr: "--key-strategy"},
+	}
+	for _, tt := range tests {
+		got, err := resolveAgentKeyStrategy(tt.raw, tt.template)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("%s/%s: expected error containing %q, got %v", tt.raw, tt.template, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("%s/%s: expected %s, got %s (%v)", tt.raw, tt.template, tt.want, got, err)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const defaultAgentTokenTTL = "24h"
+
+func newAgentTokenCmd() *cobra.Command {
+	tokenCmd := &cobra.Command{
+		Use:   "token",
+		Short: "Mint and manage CLI tokens for agents",
+		Long: strings.TrimSpace(`
+Mint and manage CLI tokens that let an agent runtime act as the agent without
+an interactive login. Tokens are minted from the admin identity.
+`),
+	}
+	tokenCmd.AddCommand(newAgentTokenCreateCmd())
+	tokenCmd.AddCommand(newAgentTokenListCmd())
+	tokenCmd.AddCommand(newAgentTokenRevokeCmd())
+	return tokenCmd
+}
+
+func newAgentTokenCreateCmd() *cobra.Command {
+	var ttlRaw string
+	var outputFile string
+	var envFile string
+
+	cmd := &cobra.Command{
+		Use:   "create <agent-key>",
+		Short: "Mint a CLI token for an agent",
+		Long: strings.TrimSpace(`
+Mint a CLI token for an agent. Without --output-file or --env-file the token
+is the only thing printed on stdout, so it can be captured by a script;
+details go to stderr.
+
+--env-file writes OPENSPEND_MARKETPLACE_BASE_URL, OPENSPEND_AUTH_SESSION_TOKEN
+and OPENSPEND_AUTH_TOKEN_TYPE, which the CLI reads in place of a stored login.
+Files are created with mode 0600.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard agent token create buyer-agent-1 --ttl 24h
+  openspend dashboard agent token create buyer-agent-1 --ttl 7d --env-file agent.env
+  docker run --env-file agent.env my-agent-image
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+			ttl, err := parseDuration(ttlRaw)
+			if err != nil {
+				return fmt.Errorf("--ttl: %w", err)
+			}
+			if ttl < time.Minute {
+				return fmt.Errorf("--ttl must be at least 1m")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.ExchangeCliAuth(cmd.Context(), api.ExchangeCliAuthRequest{
+				LoginAs:            config.AuthLoginAsAgent,
+				SubjectExternalKey: key,
+				TTLSeconds:         int64(ttl / time.Second),
+			})
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+			if res.LoginAs != config.AuthLoginAsAgent {
+				return fmt.Errorf("server returned a %q token instead of an agent token", res.LoginAs)
+			}
+
+			tokenID := ""
+			if res.TokenID != nil {
+				tokenID = strings.TrimSpace(*res.TokenID)
+			}
+			expiresAt := ""
+			if res.ExpiresAt != nil {
+				expiresAt = res.ExpiresAt.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(
+				cmd.ErrOrStderr(),
+				"Agent token created: key=%s token_id=%s expires_at=%s\n",
+				key,
+				tokenID,
+				expiresAt,
+			)
+
+			if outputFile == "" && envFile == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), res.CliToken)
+				return nil
+			}
+			if outputFile != "" {
+				if err := writeSecretFile(outputFile, res.CliToken+"\n"); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Token written to %s\n", outputFile)
+			}
+			if envFile != "" {
+				if err := writeSecretFile(envFile, agentTokenEnv(cfg.Marketplace.BaseURL, res.CliToken)); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Env file written to %s\n", envFile)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&ttlRaw, "ttl", defaultAgentTokenTTL, "Token lifetime (for example 1h, 24h or 7d)")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Write the token to this file instead of stdout")
+	cmd.Flags().StringVar(&envFile, "env-file", "", "Write an env file for the agent runtime")
+	return cmd
+}
+
+func newAgentTokenListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <agent-key>",
+		Short: "List the CLI tokens minted for an agent",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.ListAgentTokens(cmd.Context(), key)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if len(res.Tokens) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No tokens.")
+				return nil
+			}
+			for _, token := range res.Tokens {
+				lastUsed := ""
+				if token.LastUsedAt != nil {
+					lastUsed = strings.TrimSpace(*token.LastUsedAt)
+				}
+				createdBy := ""
+				if token.CreatedBy != nil {
+					createdBy = strings.TrimSpace(*token.CreatedBy)
+				}
+				fmt.Fprintf(
+					cmd.OutOrStdout(),
+					"- id=%s created_at=%s created_by=%s expires_at=%s last_used_at=%s revoked=%t\n",
+					token.ID,
+					token.CreatedAt,
+					createdBy,
+					token.ExpiresAt,
+					lastUsed,
+					token.Revoked,
+				)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Total tokens: %d\n", len(res.Tokens))
+			return nil
+		},
+	}
+}
+
+func newAgentTokenRevokeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <agent-key> <token-id>",
+		Short: "Revoke a CLI token minted for an agent",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.TrimSpace(args[0])
+			if key == "" {
+				return fmt.Errorf("agent key is required")
+			}
+			tokenID := strings.TrimSpace(args[1])
+			if tokenID == "" {
+				return fmt.Errorf("token ID is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			if err := client.RevokeAgentToken(cmd.Context(), key, tokenID); err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Token revoked: key=%s id=%s\n", key, tokenID)
+			return nil
+		},
+	}
+}
+
+// agentTokenEnv renders an env file that points the CLI at baseURL with
+// token as its session.
+func agentTokenEnv(baseURL, token string) string {
+	return fmt.Sprintf(
+		"OPENSPEND_MARKETPLACE_BASE_URL=%s\nOPENSPEND_AUTH_SESSION_TOKEN=%s\nOPENSPEND_AUTH_TOKEN_TYPE=%s\n",
+		baseURL,
+		token,
+		config.AuthTokenBearer,
+	)
+}
+
+// writeSecretFile writes content readable only by the current user,
+// tightening the mode of an existing file.
+func writeSecretFile(path, content string) error {
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
+// parseDuration accepts Go durations plus a day suffix, for example 90m,
+// 24h or 7d.
+func parseDuration(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q (for example 24h or 7d)", raw)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (for example 24h or 7d)", raw)
+	}
+	return d, nil
+}
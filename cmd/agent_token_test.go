@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{raw: "24h", want: 24 * time.Hour},
+		{raw: " 90m ", want: 90 * time.Minute},
+		{raw: "7d", want: 7 * 24 * time.Hour},
+		{raw: "0.5d", want: 12 * time.Hour},
+		{raw: "30", wantErr: true},
+		{raw: "d", wantErr: true},
+		{raw: "-1d", wantErr: true},
+		{raw: "-1h", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDuration(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%q: expected error, got %s", tt.raw, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.raw, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q: expected %s, got %s", tt.raw, tt.want, got)
+		}
+	}
+}
+
+func TestWriteAgentTokenEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.env")
+	if err := os.WriteFile(path, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := writeSecretFile(path, agentTokenEnv("https://openspend.ai", "ospcli-v1.abc.def")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected mode 0600, got %s", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := strings.Join([]string{
+		"OPENSPEND_MARKETPLACE_BASE_URL=https://openspend.ai",
+		"OPENSPEND_AUTH_SESSION_TOKEN=ospcli-v1.abc.def",
+		"OPENSPEND_AUTH_TOKEN_TYPE=bearer",
+	}, "\n") + "\n"
+	if string(data) != want {
+		t.Fatalf("unexpected env file:\n%s", data)
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/audit"
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"github.com/spf13/cobra"
+)
+
+const (
+	auditOutputText   = "text"
+	auditOutputNDJSON = "ndjson"
+)
+
+// auditRecord is one NDJSON line: the entry as returned by the server plus
+// the field-level changes derived from it.
+type auditRecord struct {
+	api.AuditEntry
+	Changes []policyfile.FieldChange `json:"changes"`
+}
+
+func newAuditCmd() *cobra.Command {
+	var (
+		actor    string
+		target   string
+		action   string
+		sinceRaw string
+		untilRaw string
+		output   string
+		limit    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "List administrative changes to policies and subjects",
+		Long: strings.TrimSpace(`
+List administrative changes to policies and subjects, oldest first: who made
+the change (a user, or an agent through a CLI token), the action, the target
+and the fields that changed.
+
+--actor matches an actor ID, name or token ID; --target matches a policy ID or
+a subject key or ID. --since and --until take a duration back from now (24h,
+7d) or an RFC 3339 time.
+
+--output ndjson writes one JSON object per line, with the raw before and after
+state and the derived changes, for shipping to a log pipeline or SIEM.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard audit --since 7d
+  openspend dashboard audit --target <policy-id> --action policy.update
+  openspend dashboard audit --actor alice@example.com --since 2026-10-01T00:00:00Z --output ndjson > audit.ndjson
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			now := time.Now()
+			since, err := parseSince(sinceRaw, now)
+			if err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+			until, err := parseSince(untilRaw, now)
+			if err != nil {
+				return fmt.Errorf("--until: %w", err)
+			}
+			if !since.IsZero() && !until.IsZero() && !until.After(since) {
+				return fmt.Errorf("--until must be after --since")
+			}
+			output = strings.ToLower(strings.TrimSpace(output))
+			if output != auditOutputText && output != auditOutputNDJSON {
+				return fmt.Errorf("--output must be one of: text, ndjson")
+			}
+			if limit <= 0 {
+				return fmt.Errorf("--limit must be positive")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			it := client.Audit(api.AuditRequest{
+				Actor:  strings.TrimSpace(actor),
+				Target: strings.TrimSpace(target),
+				Action: strings.TrimSpace(action),
+				Since:  since,
+				Until:  until,
+				Limit:  limit,
+			})
+			out := cmd.OutOrStdout()
+			count := 0
+			for it.Next(cmd.Context()) {
+				entry := it.Item()
+				changes, err := audit.Diff(entry.Before, entry.After)
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: entry %s: %v\n", entry.ID, err)
+				}
+				if output == auditOutputNDJSON {
+					if err := writeAuditRecord(out, entry, changes); err != nil {
+						return err
+					}
+				} else {
+					printAuditEntry(out, entry, changes)
+				}
+				count++
+			}
+			if err := it.Err(); err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if output == auditOutputText {
+				if count == 0 {
+					fmt.Fprintln(out, "No audit entries found.")
+					return nil
+				}
+				fmt.Fprintf(out, "Total entries: %d\n", count)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&actor, "actor", "", "Only changes by this actor (ID, name or token ID)")
+	cmd.Flags().StringVar(&target, "target", "", "Only changes to this policy or subject")
+	cmd.Flags().StringVar(&action, "action", "", "Only this action (for example policy.update or agent.create)")
+	cmd.Flags().StringVar(&sinceRaw, "since", "7d", "Only changes after this duration ago (24h, 7d) or RFC 3339 time; empty for all")
+	cmd.Flags().StringVar(&untilRaw, "until", "", "Only changes before this duration ago or RFC 3339 time")
+	cmd.Flags().StringVar(&output, "output", auditOutputText, "Output format (text|ndjson)")
+	cmd.Flags().IntVar(&limit, "limit", 100, "Page size")
+	return cmd
+}
+
+func writeAuditRecord(out io.Writer, entry api.AuditEntry, changes []policyfile.FieldChange) error {
+	if changes == nil {
+		changes = []policyfile.FieldChange{}
+	}
+	line, err := json.Marshal(auditRecord{AuditEntry: entry, Changes: changes})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", line)
+	return err
+}
+
+func printAuditEntry(out io.Writer, entry api.AuditEntry, changes []policyfile.FieldChange) {
+	fmt.Fprintf(
+		out,
+		"- at=%s actor=%s action=%s target=%s:%s",
+		entry.OccurredAt,
+		auditActorLabel(entry.Actor),
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+	)
+	if name := derefTrimmed(entry.TargetName); name != "" {
+		fmt.Fprintf(out, " target_name=%s", name)
+	}
+	if tokenID := derefTrimmed(entry.Actor.TokenID); tokenID != "" {
+		fmt.Fprintf(out, " token_id=%s", tokenID)
+	}
+	fmt.Fprintln(out)
+	for _, change := range changes {
+		fmt.Fprintf(out, "    %s: %s\n", change.Field, formatFieldChange(change))
+	}
+}
+
+// auditActorLabel renders an actor as type:name, falling back to the ID.
+func auditActorLabel(actor api.AuditActor) string {
+	name := derefTrimmed(actor.Name)
+	if name == "" {
+		name = actor.ID
+	}
+	if actor.Type == "" {
+		return name
+	}
+	return actor.Type + ":" + name
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+)
+
+func TestPrintAuditEntry(t *testing.T) {
+	name, targetName, tokenID := "alice@example.com", "Buyer", "tok_1"
+	entry := api.AuditEntry{
+		ID:         "aud_1",
+		OccurredAt: "2026-10-18T09:00:00Z",
+		Actor:      api.AuditActor{Type: "user", ID: "u1", Name: &name, TokenID: &tokenID},
+		Action:     "policy.update",
+		TargetType: "policy",
+		TargetID:   "pol_1",
+		TargetName: &targetName,
+		Before:     json.RawMessage(`{"status":"active"}`),
+		After:      json.RawMessage(`{"status":"inactive"}`),
+	}
+	changes := []policyfile.FieldChange{{Field: "status", From: "active", To: "inactive"}}
+
+	var text bytes.Buffer
+	printAuditEntry(&text, entry, changes)
+	want := "- at=2026-10-18T09:00:00Z actor=user:alice@example.com action=policy.update target=policy:pol_1 target_name=Buyer token_id=tok_1\n" +
+		"    status: active -> inactive\n"
+	if text.String() != want {
+		t.Fatalf("unexpected output:\n%s", text.String())
+	}
+
+	var ndjson bytes.Buffer
+	if err := writeAuditRecord(&ndjson, entry, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	line := ndjson.String()
+	if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+		t.Fatalf("expected a single line, got %q", line)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record["id"] != "aud_1" || record["action"] != "policy.update" {
+		t.Fatalf("unexpected record: %v", record)
+	}
+	if changes, ok := record["changes"].([]any); !ok || len(changes) != 0 {
+		t.Fatalf("expected an empty changes array, got %v", record["changes"])
+	}
+	if before, ok := record["before"].(map[string]any); !ok || before["status"] != "active" {
+		t.Fatalf("expected the raw before state, got %v", record["before"])
+	}
+}
+
+func TestAuditActorLabel(t *testing.T) {
+	name := "buyer-agent-1"
+	tests := []struct {
+		actor api.AuditActor
+		want  string
+	}{
+		{actor: api.AuditActor{Type: "agent", ID: "s1", Name: &name}, want: "agent:buyer-agent-1"},
+		{actor: api.AuditActor{Type: "user", ID: "u1"}, want: "user:u1"},
+		{actor: api.AuditActor{ID: "u1"}, want: "u1"},
+	}
+	for _, tt := range tests {
+		if got := auditActorLabel(tt.actor); got != tt.want {
+			t.Fatalf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
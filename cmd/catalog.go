@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/catalog"
+	"github.com/promptingcompany/openspend-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newCatalogCmd() *cobra.Command {
+	catalogCmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Offline marketplace catalog snapshots",
+	}
+	catalogCmd.AddCommand(newCatalogSyncCmd())
+	catalogCmd.AddCommand(newCatalogInfoCmd())
+	return catalogCmd
+}
+
+func newCatalogSyncCmd() *cobra.Command {
+	var file string
+	var queries []string
+	var networks []string
+	var pageSize int
+	var maxPages int
+	var signingKeyFile string
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Download the marketplace catalog into a local snapshot file",
+		Long: strings.TrimSpace(`
+Download the marketplace catalog into a local snapshot file.
+
+Sync pages through search for each --query and stores the deduplicated items
+(prices, networks, scores, origins) in a bbolt file, stamped with a
+timestamp version and a SHA-256 digest. With --signing-key-file or
+OPENSPEND_CATALOG_SIGNING_KEY the digest is also HMAC-signed, so sandboxes
+holding the same key can verify copies before use.
+`),
+		Example: strings.TrimSpace(`
+  openspend catalog sync
+  openspend catalog sync --query "image generation" --query "speech to text" --file ./catalog.db
+  openspend search "ocr" --offline --catalog-file ./catalog.db
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if pageSize <= 0 {
+				return fmt.Errorf("--page-size must be positive")
+			}
+			path, err := resolveCatalogPath(file)
+			if err != nil {
+				return err
+			}
+			key, err := loadCatalogSigningKey(signingKeyFile)
+			if err != nil {
+				return err
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			items := make([]api.SearchResultItem, 0)
+			for _, query := range queries {
+				query = strings.TrimSpace(query)
+				if query == "" {
+					continue
+				}
+				for page := 0; maxPages <= 0 || page < maxPages; page++ {
+					res, err := client.Search(cmd.Context(), api.SearchRequest{
+						Query:    query,
+						Networks: networks,
+						Limit:    pageSize,
+						Offset:   page * pageSize,
+					})
+					if err != nil {
+						return err
+					}
+					items = append(items, res.Items...)
+					fetched := page*pageSize + len(res.Items)
+					if len(res.Items) < pageSize || (res.Pagination.Total > 0 && fetched >= res.Pagination.Total) {
+						break
+					}
+				}
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			snapshot := catalog.NewSnapshot(cfg.Marketplace.BaseURL, queries, catalog.Dedupe(items), time.Now())
+			if err := snapshot.Sign(key); err != nil {
+				return err
+			}
+			if err := catalog.Write(path, snapshot); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Catalog snapshot %s written to %s (items=%d signed=%t)\n",
+				snapshot.Manifest.Version,
+				path,
+				snapshot.Manifest.ItemCount,
+				snapshot.Manifest.Signature != "",
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Snapshot file (default ~/.config/openspend/catalog.db or OPENSPEND_CATALOG_PATH)")
+	cmd.Flags().StringArrayVar(&queries, "query", []string{"*"}, "Search query to page through (repeatable)")
+	cmd.Flags().StringSliceVar(&networks, "network", nil, "Network filter (repeatable)")
+	cmd.Flags().IntVar(&pageSize, "page-size", 50, "Results per search page")
+	cmd.Flags().IntVar(&maxPages, "max-pages", 0, "Maximum pages per query (0 for no limit)")
+	cmd.Flags().StringVar(&signingKeyFile, "signing-key-file", "", "File holding the HMAC key used to sign the snapshot")
+	return cmd
+}
+
+func newCatalogInfoCmd() *cobra.Command {
+	var file string
+	var signingKeyFile string
+
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Show and verify a local catalog snapshot",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := resolveCatalogPath(file)
+			if err != nil {
+				return err
+			}
+			key, err := loadCatalogSigningKey(signingKeyFile)
+			if err != nil {
+				return err
+			}
+			snapshot, err := catalog.Read(path)
+			if err != nil {
+				return err
+			}
+
+			m := snapshot.Manifest
+			fmt.Fprintf(cmd.OutOrStdout(), "File: %s\n", path)
+			fmt.Fprintf(cmd.OutOrStdout(), "Version: %s (schema %d)\n", m.Version, m.SchemaVersion)
+			fmt.Fprintf(cmd.OutOrStdout(), "Created: %s\n", m.CreatedAt.Format(time.RFC3339))
+			fmt.Fprintf(cmd.OutOrStdout(), "Source: %s\n", m.Source)
+			fmt.Fprintf(cmd.OutOrStdout(), "Queries: %s\n", strings.Join(m.Queries, ", "))
+			fmt.Fprintf(cmd.OutOrStdout(), "Items: %d\n", len(snapshot.Items))
+			fmt.Fprintf(cmd.OutOrStdout(), "Digest: %s\n", m.Digest)
+			if m.Signature != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Signature: %s (%s)\n", m.Signature, m.SignatureAlgorithm)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), "Signature: (unsigned)")
+			}
+
+			if err := snapshot.Verify(key); err != nil {
+				return err
+			}
+			if len(key) > 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Verification: digest and signature OK")
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), "Verification: digest OK (no signing key supplied)")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Snapshot file (default ~/.config/openspend/catalog.db or OPENSPEND_CATALOG_PATH)")
+	cmd.Flags().StringVar(&signingKeyFile, "signing-key-file", "", "File holding the HMAC key used to verify the snapshot")
+	return cmd
+}
+
+// searchOffline runs req against a local catalog snapshot. The digest is
+// always verified; the signature is verified when a signing key is set.
+func searchOffline(file string, req api.SearchRequest) (api.SearchResponse, error) {
+	path, err := resolveCatalogPath(file)
+	if err != nil {
+		return api.SearchResponse{}, err
+	}
+	key, err := loadCatalogSigningKey("")
+	if err != nil {
+		return api.SearchResponse{}, err
+	}
+	snapshot, err := catalog.Read(path)
+	if err != nil {
+		return api.SearchResponse{}, err
+	}
+	if err := snapshot.Verify(key); err != nil {
+		return api.SearchResponse{}, err
+	}
+
+	hits, err := catalog.NewIndex(snapshot.Items).Search(req)
+	if err != nil {
+		return api.SearchResponse{}, err
+	}
+	var res api.SearchResponse
+	res.Items = make([]api.SearchResultItem, 0, len(hits))
+	for _, hit := range hits {
+		res.Items = append(res.Items, hit.Item)
+	}
+	res.Pagination.Total = len(res.Items)
+	res.Pagination.Limit = req.Limit
+	res.Pagination.Offset = req.Offset
+	return res, nil
+}
+
+func resolveCatalogPath(file string) (string, error) {
+	if strings.TrimSpace(file) != "" {
+		return strings.TrimSpace(file), nil
+	}
+	return config.CatalogPath()
+}
+
+func loadCatalogSigningKey(file string) ([]byte, error) {
+	if strings.TrimSpace(file) != "" {
+		data, err := os.ReadFile(strings.TrimSpace(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+		return []byte(strings.TrimSpace(string(data))), nil
+	}
+	return []byte(strings.TrimSpace(os.Getenv("OPENSPEND_CATALOG_SIGNING_KEY"))), nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// confirmAction asks a yes/no question on the command's input, defaulting
+// to no. End of input counts as no.
+func confirmAction(cmd *cobra.Command, prompt string) (bool, error) {
+	reader := bufio.NewReader(cmd.InOrStdin())
+	for {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s (y/N): ", prompt)
+		raw, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(raw)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		default:
+			fmt.Fprintln(cmd.OutOrStdout(), "Please answer y or N.")
+		}
+
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+	}
+}
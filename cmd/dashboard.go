@@ -9,5 +9,8 @@ func newDashboardCmd() *cobra.Command {
 	}
 	dashboardCmd.AddCommand(newAgentCmd())
 	dashboardCmd.AddCommand(newPolicyCmd())
+	dashboardCmd.AddCommand(newSubjectCmd())
+	dashboardCmd.AddCommand(newSpendCmd())
+	dashboardCmd.AddCommand(newAuditCmd())
 	return dashboardCmd
 }
@@ -1,5 +1,17 @@
 package cmd
 
+import "fmt"
+
+// ExitError asks main to exit with Code without printing anything further;
+// the command has already written its output.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 func Execute() error {
 	return executeWithContext()
 }
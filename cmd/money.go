@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/config"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+// fxTableFromConfig builds the FX table from built-in stablecoin pegs, the
+// [money] fx_rates config table and the optional FX file, in that order.
+func fxTableFromConfig(cfg config.Config) (money.FXTable, error) {
+	fx, err := money.DefaultFX().With(cfg.Money.FXRates)
+	if err != nil {
+		return money.FXTable{}, err
+	}
+	if strings.TrimSpace(cfg.Money.FXFile) != "" {
+		return fx.LoadFXFile(strings.TrimSpace(cfg.Money.FXFile))
+	}
+	return fx, nil
+}
+
+// resolveSearchBudget converts req.BudgetMax from unit into the budget asset.
+// A unit without a budget asset becomes the budget asset, unless it is fiat.
+func resolveSearchBudget(req *api.SearchRequest, unit string, fx money.FXTable) error {
+	unit = money.NormalizeSymbol(unit)
+	if req.BudgetMax == nil || unit == "" {
+		return nil
+	}
+	asset := money.NormalizeSymbol(req.BudgetAsset)
+	if asset == "" {
+		if money.IsFiat(unit) {
+			return fmt.Errorf("a budget asset (--budget-asset or asset:) is required to convert a %s budget", unit)
+		}
+		req.BudgetAsset = unit
+		return nil
+	}
+	amount, err := money.ParseAmount(money.FormatFloat(*req.BudgetMax))
+	if err != nil {
+		return err
+	}
+	amount.Unit = unit
+	converted, err := fx.Convert(amount, asset)
+	if err != nil {
+		return err
+	}
+	value, _ := converted.Float64()
+	req.BudgetMax = &value
+	return nil
+}
+
+// resolveMaxPriceFlag converts a --max-price value to base units. Plain
+// integers are taken as base units; values with a unit are converted using
+// the asset's decimals and the FX table. It returns the asset the base units
+// are denominated in, which is the unit itself when no asset was given.
+func resolveMaxPriceFlag(cfg config.Config, raw, asset, network string) (int64, string, error) {
+	fx, err := fxTableFromConfig(cfg)
+	if err != nil {
+		return 0, "", err
+	}
+	base, resolvedAsset, err := money.ResolveBaseUnits(raw, asset, network, fx)
+	if err != nil {
+		return 0, "", fmt.Errorf("--max-price: %w", err)
+	}
+	if !base.IsInt64() {
+		return 0, "", fmt.Errorf("--max-price %q is too large", raw)
+	}
+	return base.Int64(), resolvedAsset, nil
+}
+
+func formatItemPrice(item api.SearchResultItem) string {
+	return strings.TrimSpace(money.FormatFloat(item.MinPrice) + " " + item.Asset)
+}
@@ -3,10 +3,13 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
 	"github.com/spf13/cobra"
 )
 
@@ -16,39 +19,105 @@ func newPolicyCmd() *cobra.Command {
 		Short: "Policy management",
 	}
 	policyCmd.AddCommand(newPolicyInitCmd())
+	policyCmd.AddCommand(newPolicyTemplatesCmd())
 	policyCmd.AddCommand(newPolicyListCmd())
 	policyCmd.AddCommand(newPolicyUpdateCmd())
 	policyCmd.AddCommand(newPolicyDescribeCmd())
+	policyCmd.AddCommand(newPolicyExportCmd())
+	policyCmd.AddCommand(newPolicyApplyCmd())
+	policyCmd.AddCommand(newPolicyPlanCmd())
+	policyCmd.AddCommand(newPolicyDiffCmd())
+	policyCmd.AddCommand(newPolicyRuleCmd())
+	policyCmd.AddCommand(newPolicySimulateCmd())
+	policyCmd.AddCommand(newPolicyLintCmd())
+	policyCmd.AddCommand(newPolicyHistoryCmd())
+	policyCmd.AddCommand(newPolicyShowCmd())
+	policyCmd.AddCommand(newPolicyRollbackCmd())
+	policyCmd.AddCommand(newPolicyCloneCmd())
+	policyCmd.AddCommand(newPolicyArchiveCmd())
+	policyCmd.AddCommand(newPolicyDeleteCmd())
+	policyCmd.AddCommand(newPolicyBindCmd())
+	policyCmd.AddCommand(newPolicyUnbindCmd())
+	policyCmd.AddCommand(newPolicyBindingsCmd())
 	return policyCmd
 }
 
 func newPolicyInitCmd() *cobra.Command {
 	var buyer bool
+	var mode string
+	var templateName string
 	var name string
 	var description string
 	var asset string
 	var network string
 	var denyHosts string
-	var maxPrice int64
+	var maxPrice string
 
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize a base policy",
+		Long: strings.TrimSpace(`
+Initialize a base policy.
+
+Pass --buyer or --mode to initialize a policy for that mode, or --template to
+create one from a built-in or user template (see policy templates list). Flags
+such as --name, --asset and --max-price override the template's values; asset,
+network and max price apply to its global allow rule and --deny-hosts adds
+deny rules.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy init --buyer
+  openspend dashboard policy init --mode sell --name "My Seller Policy"
+  openspend dashboard policy init --template strict-buyer --max-price 0.05USDC
+`),
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if !buyer {
-				return fmt.Errorf("only buyer initialization is supported for now; pass --buyer")
+			resolvedMode, err := resolveInitMode(buyer, mode)
+			if err != nil {
+				return err
+			}
+			if resolvedMode == "" && strings.TrimSpace(templateName) == "" {
+				return fmt.Errorf("pass --buyer, --mode or --template")
 			}
 			cfg := mustLoadConfig()
+
+			if strings.TrimSpace(templateName) != "" {
+				return initPolicyFromTemplate(cmd, cfg, templateName, policyTemplateOverrides{
+					mode:        resolvedMode,
+					name:        name,
+					description: description,
+					asset:       asset,
+					network:     network,
+					maxPrice:    maxPrice,
+					denyHosts:   splitHosts(denyHosts),
+				})
+			}
+
 			client := clientFromConfig(cfg)
 
 			var maxPricePtr *int64
-			if maxPrice > 0 {
-				maxPricePtr = &maxPrice
+			if strings.TrimSpace(maxPrice) != "" {
+				baseUnits, resolvedAsset, err := resolveMaxPriceFlag(cfg, maxPrice, asset, network)
+				if err != nil {
+					return err
+				}
+				if baseUnits > 0 {
+					maxPricePtr = &baseUnits
+				}
+				asset = resolvedAsset
+			}
+
+			label := policyModeLabel(resolvedMode)
+			if !cmd.Flags().Changed("name") {
+				name = "CLI " + label + " Policy"
+			}
+			if !cmd.Flags().Changed("description") {
+				description = "Default " + strings.ToLower(label) + " policy created by OpenSpend CLI"
 			}
 
 			payload := api.InitPolicyRequest{
 				Name:        name,
 				Description: description,
+				Mode:        resolvedMode,
 				Asset:       asset,
 				Network:     network,
 				MaxPrice:    maxPricePtr,
@@ -69,30 +138,119 @@ func newPolicyInitCmd() *cobra.Command {
 			if res.Created {
 				state = "created"
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Buyer policy %s: %s (%s)\n", state, res.Policy.Name, res.Policy.ID)
+			fmt.Fprintf(cmd.OutOrStdout(), "%s policy %s: %s (%s)\n", label, state, res.Policy.Name, res.Policy.ID)
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&buyer, "buyer", false, "Initialize a buyer policy")
-	cmd.Flags().StringVar(&name, "name", "CLI Buyer Policy", "Policy name")
-	cmd.Flags().StringVar(&description, "description", "Default buyer policy created by OpenSpend CLI", "Policy description")
+	cmd.Flags().BoolVar(&buyer, "buyer", false, "Initialize a buyer policy (same as --mode buy)")
+	cmd.Flags().StringVar(&mode, "mode", "", "Policy mode (buy|sell|both)")
+	cmd.Flags().StringVar(&templateName, "template", "", "Create the policy from a template (see policy templates list)")
+	cmd.Flags().StringVar(&name, "name", "", "Policy name (default: CLI <Mode> Policy, or the template's name)")
+	cmd.Flags().StringVar(&description, "description", "", "Policy description")
 	cmd.Flags().StringVar(&asset, "asset", "", "Optional preferred asset")
 	cmd.Flags().StringVar(&network, "network", "", "Optional preferred network")
 	cmd.Flags().StringVar(&denyHosts, "deny-hosts", "", "Comma-separated deny hosts")
-	cmd.Flags().Int64Var(&maxPrice, "max-price", 0, "Optional max price (base units)")
+	cmd.Flags().StringVar(
+		&maxPrice,
+		"max-price",
+		"",
+		"Optional max price: base units (500000) or an amount with unit (0.5USDC, 5USD)",
+	)
 	return cmd
 }
 
+// resolveInitMode combines --buyer and --mode, returning "" when neither is set.
+func resolveInitMode(buyer bool, mode string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(mode))
+	switch value {
+	case "", "buy", "sell", "both":
+	default:
+		return "", fmt.Errorf("--mode must be one of: buy, sell, both")
+	}
+	if buyer {
+		if value != "" && value != "buy" {
+			return "", fmt.Errorf("--buyer conflicts with --mode %s", value)
+		}
+		return "buy", nil
+	}
+	return value, nil
+}
+
+func policyModeLabel(mode string) string {
+	switch mode {
+	case "sell":
+		return "Seller"
+	case "both":
+		return "Buyer/seller"
+	default:
+		return "Buyer"
+	}
+}
+
 func newPolicyListCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		mode         string
+		status       string
+		nameContains string
+		cursor       string
+		limit        int
+		all          bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List policies visible to current user",
+		Long: strings.TrimSpace(`
+List policies visible to the current user, including policies with no bound
+subjects. Filters and paging are applied by the server; pass --cursor from a
+previous page to continue, or --all to fetch every page. With servers that
+predate the policy list endpoint, policies are derived from whoami subjects
+instead, so unbound policies are missing and --status is unavailable.
+`),
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			req := api.ListPoliciesRequest{
+				Mode:         strings.ToLower(strings.TrimSpace(mode)),
+				Status:       strings.ToLower(strings.TrimSpace(status)),
+				NameContains: strings.TrimSpace(nameContains),
+				Limit:        limit,
+			}
+			cursor = strings.TrimSpace(cursor)
+			switch req.Mode {
+			case "", "buy", "sell", "both":
+			default:
+				return fmt.Errorf("--mode must be one of: buy, sell, both")
+			}
+			switch req.Status {
+			case "", "active", "inactive", "archived":
+			default:
+				return fmt.Errorf("--status must be one of: active, inactive, archived")
+			}
+			if limit <= 0 {
+				return fmt.Errorf("--limit must be positive")
+			}
+			if all && cursor != "" {
+				return fmt.Errorf("--all and --cursor cannot be used together")
+			}
+			if cursor != "" {
+				value, err := strconv.Atoi(cursor)
+				if err != nil || value < 0 {
+					return fmt.Errorf("--cursor must be a cursor printed with a previous page")
+				}
+				req.Offset = value
+			}
+
 			cfg := mustLoadConfig()
 			client := clientFromConfig(cfg)
 
-			res, err := client.WhoAmI(cmd.Context())
+			items, total, next, err := listPolicies(cmd, client, req, all)
+			if api.IsStatus(err, http.StatusNotFound, http.StatusMethodNotAllowed) {
+				if req.Status != "" {
+					return fmt.Errorf("--status is not supported by this server")
+				}
+				fmt.Fprintln(cmd.ErrOrStderr(), "Policy list endpoint unavailable; deriving policies from whoami subjects.")
+				items, total, next, err = listPoliciesFromWhoAmI(cmd, client, req, all)
+			}
 			if err != nil {
 				return err
 			}
@@ -100,75 +258,161 @@ func newPolicyListCmd() *cobra.Command {
 				return err
 			}
 
-			type policySummary struct {
-				ID         string
-				Name       string
-				Mode       string
-				SubjectCnt int
-			}
-
-			policies := make(map[string]policySummary)
-			for _, subject := range res.Subjects {
-				id := ""
-				if subject.PolicyID != nil {
-					id = strings.TrimSpace(*subject.PolicyID)
-				}
-				name := ""
-				if subject.PolicyName != nil {
-					name = strings.TrimSpace(*subject.PolicyName)
-				}
-				mode := ""
-				if subject.PolicyMode != nil {
-					mode = strings.TrimSpace(*subject.PolicyMode)
-				}
-				if id == "" && name == "" {
-					continue
-				}
-
-				key := id
-				if key == "" {
-					key = "name:" + name
-				}
-				current := policies[key]
-				if current.ID == "" {
-					current.ID = id
-				}
-				if current.Name == "" {
-					current.Name = name
-				}
-				if current.Mode == "" {
-					current.Mode = mode
-				}
-				current.SubjectCnt++
-				policies[key] = current
-			}
-
-			if len(policies) == 0 {
+			if len(items) == 0 && next == "" {
 				fmt.Fprintln(cmd.OutOrStdout(), "No policies found.")
 				return nil
 			}
-
-			keys := make([]string, 0, len(policies))
-			for key := range policies {
-				keys = append(keys, key)
-			}
-			sort.Strings(keys)
-
-			for _, key := range keys {
-				p := policies[key]
+			for _, p := range items {
 				fmt.Fprintf(
 					cmd.OutOrStdout(),
-					"- id=%s name=%s mode=%s subjects=%d\n",
+					"- id=%s name=%s mode=%s status=%s version=%d updated_at=%s bindings=%d\n",
 					p.ID,
 					p.Name,
 					p.Mode,
-					p.SubjectCnt,
+					p.Status,
+					p.Version,
+					p.UpdatedAt,
+					p.BindingCount,
 				)
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Total policies: %d\n", len(keys))
+			if next != "" {
+				of := ""
+				if total > 0 {
+					of = fmt.Sprintf(" of %d", total)
+				}
+				fmt.Fprintf(
+					cmd.OutOrStdout(),
+					"Showing %d-%d%s policies (next page: --cursor %s)\n",
+					req.Offset+1,
+					req.Offset+len(items),
+					of,
+					next,
+				)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Total policies: %d\n", total)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&mode, "mode", "", "Only policies with this mode (buy|sell|both)")
+	cmd.Flags().StringVar(&status, "status", "", "Only policies with this status (active|inactive|archived)")
+	cmd.Flags().StringVar(&nameContains, "name-contains", "", "Only policies whose name contains this text (case-insensitive)")
+	cmd.Flags().StringVar(&cursor, "cursor", "", "Continue from the cursor printed with a previous page")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Page size")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page")
+	return cmd
+}
+
+// listPolicies returns one page, or every page when all is set, along with
+// the total (0 when the server gave none and more pages may follow) and the
+// cursor of the next page ("" on the last page).
+func listPolicies(
+	cmd *cobra.Command,
+	client *api.Client,
+	req api.ListPoliciesRequest,
+	all bool,
+) ([]api.PolicyListItem, int, string, error) {
+	if !all {
+		res, err := client.ListPolicies(cmd.Context(), req)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		offset, more := res.NextOffset(req.Offset, req.Limit)
+		if !more {
+			return res.Items, max(res.Pagination.Total, offset), "", nil
+		}
+		return res.Items, res.Pagination.Total, strconv.Itoa(offset), nil
+	}
+
+	items := make([]api.PolicyListItem, 0)
+	it := client.Policies(req)
+	for it.Next(cmd.Context()) {
+		items = append(items, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, 0, "", err
+	}
+	return items, len(items), "", nil
+}
+
+// listPoliciesFromWhoAmI derives policies from the subjects bound to them,
+// for servers without the policy list endpoint. Filters and paging are
+// applied locally and BindingCount counts the subjects seen.
+func listPoliciesFromWhoAmI(
+	cmd *cobra.Command,
+	client *api.Client,
+	req api.ListPoliciesRequest,
+	all bool,
+) ([]api.PolicyListItem, int, string, error) {
+	res, err := client.WhoAmI(cmd.Context())
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	policies := make(map[string]api.PolicyListItem)
+	for _, subject := range res.Subjects {
+		id := ""
+		if subject.PolicyID != nil {
+			id = strings.TrimSpace(*subject.PolicyID)
+		}
+		name := ""
+		if subject.PolicyName != nil {
+			name = strings.TrimSpace(*subject.PolicyName)
+		}
+		mode := ""
+		if subject.PolicyMode != nil {
+			mode = strings.TrimSpace(*subject.PolicyMode)
+		}
+		if id == "" && name == "" {
+			continue
+		}
+
+		key := id
+		if key == "" {
+			key = "name:" + name
+		}
+		current := policies[key]
+		if current.ID == "" {
+			current.ID = id
+		}
+		if current.Name == "" {
+			current.Name = name
+		}
+		if current.Mode == "" {
+			current.Mode = mode
+		}
+		current.BindingCount++
+		policies[key] = current
+	}
+
+	keys := make([]string, 0, len(policies))
+	for key := range policies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]api.PolicyListItem, 0, len(keys))
+	for _, key := range keys {
+		p := policies[key]
+		if req.Mode != "" && p.Mode != req.Mode {
+			continue
+		}
+		if req.NameContains != "" && !strings.Contains(strings.ToLower(p.Name), strings.ToLower(req.NameContains)) {
+			continue
+		}
+		items = append(items, p)
+	}
+
+	total := len(items)
+	if req.Offset >= total {
+		return nil, total, "", nil
+	}
+	items = items[req.Offset:]
+	if !all && len(items) > req.Limit {
+		return items[:req.Limit], total, strconv.Itoa(req.Offset + req.Limit), nil
+	}
+	return items, total, "", nil
 }
 
 func newPolicyDescribeCmd() *cobra.Command {
@@ -205,7 +449,7 @@ func newPolicyUpdateCmd() *cobra.Command {
 		status                      string
 		mode                        string
 		minScore                    int
-		maxPrice                    int64
+		maxPrice                    string
 		asset                       string
 		network                     string
 		denyHosts                   string
@@ -228,6 +472,7 @@ func newPolicyUpdateCmd() *cobra.Command {
 				return fmt.Errorf("policy ID is required")
 			}
 
+			cfg := mustLoadConfig()
 			patch := map[string]any{}
 
 			if cmd.Flags().Changed("name") {
@@ -288,10 +533,19 @@ func newPolicyUpdateCmd() *cobra.Command {
 				patch["maxPrice"] = nil
 			}
 			if cmd.Flags().Changed("max-price") {
-				if maxPrice < 0 {
-					return fmt.Errorf("--max-price must be non-negative")
+				maxPriceAsset := ""
+				if cmd.Flags().Changed("asset") {
+					maxPriceAsset = asset
+				}
+				baseUnits, resolvedAsset, err := resolveMaxPriceFlag(cfg, maxPrice, maxPriceAsset, network)
+				if err != nil {
+					return err
+				}
+				patch["maxPrice"] = baseUnits
+				// A unit on --max-price pins the rule asset so base units stay meaningful.
+				if resolvedAsset != "" && !cmd.Flags().Changed("asset") && !clearAsset {
+					patch["asset"] = resolvedAsset
 				}
-				patch["maxPrice"] = maxPrice
 			}
 
 			if clearAsset && cmd.Flags().Changed("asset") {
@@ -353,7 +607,6 @@ func newPolicyUpdateCmd() *cobra.Command {
 				return fmt.Errorf("no update fields provided")
 			}
 
-			cfg := mustLoadConfig()
 			client := clientFromConfig(cfg)
 
 			res, err := client.UpdatePolicy(cmd.Context(), policyID, patch)
@@ -377,7 +630,12 @@ func newPolicyUpdateCmd() *cobra.Command {
 	cmd.Flags().StringVar(&mode, "mode", "", "Updated policy mode (buy|sell|both)")
 	cmd.Flags().IntVar(&minScore, "min-score", 0, "Updated minimum score for allow global rule")
 	cmd.Flags().BoolVar(&clearMinScore, "clear-min-score", false, "Clear minimum score on allow global rule")
-	cmd.Flags().Int64Var(&maxPrice, "max-price", 0, "Updated max price (base units) for allow global rule")
+	cmd.Flags().StringVar(
+		&maxPrice,
+		"max-price",
+		"",
+		"Updated max price for allow global rule: base units (500000) or an amount with unit (0.5USDC, 5USD)",
+	)
 	cmd.Flags().BoolVar(&clearMaxPrice, "clear-max-price", false, "Clear max price on allow global rule")
 	cmd.Flags().StringVar(&asset, "asset", "", "Updated preferred asset for allow global rule")
 	cmd.Flags().BoolVar(&clearAsset, "clear-asset", false, "Clear preferred asset on allow global rule")
@@ -419,7 +677,11 @@ func printPolicyDetails(out io.Writer, res api.PolicyDetailsResponse) {
 	}
 	budgetMax := "(none)"
 	if res.Summary.BudgetMax != nil && strings.TrimSpace(*res.Summary.BudgetMax) != "" {
-		budgetMax = strings.TrimSpace(*res.Summary.BudgetMax)
+		budgetAsset := ""
+		if len(res.Summary.AllowAssets) == 1 {
+			budgetAsset = res.Summary.AllowAssets[0]
+		}
+		budgetMax = money.FormatBaseUnits(*res.Summary.BudgetMax, budgetAsset, "")
 	}
 
 	fmt.Fprintf(out, "Policy: %s (%s)\n", policyName, res.Policy.ID)
@@ -459,7 +721,7 @@ func printPolicyDetails(out io.Writer, res api.PolicyDetailsResponse) {
 		}
 		maxPrice := ""
 		if rule.MaxPrice != nil {
-			maxPrice = strings.TrimSpace(*rule.MaxPrice)
+			maxPrice = money.FormatBaseUnits(*rule.MaxPrice, asset, network)
 		}
 		requireIdentified := ""
 		if rule.RequireIdentifiedAgent != nil {
@@ -484,7 +746,11 @@ func printPolicyDetails(out io.Writer, res api.PolicyDetailsResponse) {
 	}
 
 	fmt.Fprintf(out, "Subject bindings: %d\n", len(res.SubjectBindings))
-	for _, binding := range res.SubjectBindings {
+	printPolicyBindings(out, res.SubjectBindings)
+}
+
+func printPolicyBindings(out io.Writer, bindings []api.PolicySubjectBinding) {
+	for _, binding := range bindings {
 		externalKey := ""
 		if binding.ExternalKey != nil {
 			externalKey = strings.TrimSpace(*binding.ExternalKey)
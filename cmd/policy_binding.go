@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+const defaultBindingPrecedence = 100
+
+func newPolicyBindCmd() *cobra.Command {
+	var precedence int
+	var active, inactive bool
+
+	cmd := &cobra.Command{
+		Use:   "bind <policy-id> <subject-key>",
+		Short: "Bind a subject to a policy or change its precedence",
+		Long: strings.TrimSpace(`
+Bind a subject to a policy. When a subject is bound to several policies, the
+active binding with the lowest precedence decides; see subject policies.
+
+Binding an already bound subject updates only the flags given: without
+--precedence an existing binding keeps its precedence (a new one gets 100),
+and without --active or --inactive it keeps its active flag (a new one is
+active).
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy bind <policy-id> buyer-agent-1 --precedence 10
+  openspend dashboard policy bind <policy-id> buyer-agent-1 --inactive
+  openspend dashboard policy bind <policy-id> buyer-agent-1 --active
+`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+			subjectKey := strings.TrimSpace(args[1])
+			if subjectKey == "" {
+				return fmt.Errorf("subject key is required")
+			}
+			if active && inactive {
+				return fmt.Errorf("use either --active or --inactive")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			if current.Policy.Status == "archived" {
+				return fmt.Errorf("policy %s is archived; subjects cannot be bound to it", policyID)
+			}
+
+			existing, bound := findPolicyBinding(current.SubjectBindings, subjectKey)
+			var existingBinding *api.PolicySubjectBinding
+			if bound {
+				existingBinding = &existing
+			}
+			var precedenceFlag *int
+			if cmd.Flags().Changed("precedence") {
+				precedenceFlag = &precedence
+			}
+			var activeFlag *bool
+			if active || inactive {
+				activeFlag = &active
+			}
+			binding := policyBindingInput(subjectKey, existingBinding, precedenceFlag, activeFlag)
+
+			res, err := client.BindPolicySubject(cmd.Context(), policyID, binding)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			outcome := "bound"
+			if bound {
+				outcome = "binding updated"
+			}
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Subject %s: policy=%s subject=%s precedence=%d active=%t\n",
+				outcome,
+				policyID,
+				subjectKey,
+				binding.Precedence,
+				binding.Active,
+			)
+			if bound && existing.Precedence != binding.Precedence {
+				fmt.Fprintf(cmd.OutOrStdout(), "Precedence changed: %d -> %d\n", existing.Precedence, binding.Precedence)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Subject bindings: %d\n", len(res.SubjectBindings))
+			printPolicyBindings(cmd.OutOrStdout(), sortedPolicyBindings(res.SubjectBindings))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&precedence, "precedence", defaultBindingPrecedence, "Binding precedence; lower values win")
+	cmd.Flags().BoolVar(&active, "active", false, "Mark the binding active")
+	cmd.Flags().BoolVar(&inactive, "inactive", false, "Keep the binding but mark it inactive")
+	return cmd
+}
+
+// policyBindingInput builds the binding sent by policy bind. An existing
+// binding keeps its precedence and active flag unless precedence or active
+// is given.
+func policyBindingInput(
+	subjectKey string,
+	existing *api.PolicySubjectBinding,
+	precedence *int,
+	active *bool,
+) api.PolicyBindingInput {
+	binding := api.PolicyBindingInput{
+		SubjectExternalKey: subjectKey,
+		Precedence:         defaultBindingPrecedence,
+		Active:             true,
+	}
+	if existing != nil {
+		binding.Precedence = existing.Precedence
+		binding.Active = existing.Active
+	}
+	if precedence != nil {
+		binding.Precedence = *precedence
+	}
+	if active != nil {
+		binding.Active = *active
+	}
+	return binding
+}
+
+func newPolicyUnbindCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unbind <policy-id> <subject-key>",
+		Short: "Remove a subject's binding from a policy",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+			subjectKey := strings.TrimSpace(args[1])
+			if subjectKey == "" {
+				return fmt.Errorf("subject key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.UnbindPolicySubject(cmd.Context(), policyID, subjectKey)
+			if api.IsStatus(err, http.StatusNotFound) {
+				return fmt.Errorf("subject %s is not bound to policy %s", subjectKey, policyID)
+			}
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Subject unbound: policy=%s subject=%s\n", policyID, subjectKey)
+			fmt.Fprintf(cmd.OutOrStdout(), "Subject bindings: %d\n", len(res.SubjectBindings))
+			printPolicyBindings(cmd.OutOrStdout(), sortedPolicyBindings(res.SubjectBindings))
+			return nil
+		},
+	}
+}
+
+func newPolicyBindingsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bindings <policy-id>",
+		Short: "List the subjects bound to a policy",
+		Long: strings.TrimSpace(`
+List the subjects bound to a policy, lowest precedence first.
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if len(res.SubjectBindings) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No subject bindings.")
+				return nil
+			}
+			printPolicyBindings(cmd.OutOrStdout(), sortedPolicyBindings(res.SubjectBindings))
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Total bindings: %d (active: %d)\n",
+				len(res.SubjectBindings),
+				len(activePolicyBindings(res)),
+			)
+			return nil
+		},
+	}
+}
+
+// findPolicyBinding finds the binding for subject, matched by external key
+// or subject ID.
+func findPolicyBinding(bindings []api.PolicySubjectBinding, subject string) (api.PolicySubjectBinding, bool) {
+	for _, binding := range bindings {
+		if binding.SubjectID == subject {
+			return binding, true
+		}
+		if binding.ExternalKey != nil && strings.TrimSpace(*binding.ExternalKey) == subject {
+			return binding, true
+		}
+	}
+	return api.PolicySubjectBinding{}, false
+}
+
+// sortedPolicyBindings returns a copy of bindings ordered by precedence, then
+// subject label.
+func sortedPolicyBindings(bindings []api.PolicySubjectBinding) []api.PolicySubjectBinding {
+	sorted := append([]api.PolicySubjectBinding(nil), bindings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Precedence != sorted[j].Precedence {
+			return sorted[i].Precedence < sorted[j].Precedence
+		}
+		return policyBindingLabel(sorted[i]) < policyBindingLabel(sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policy"
+)
+
+func TestFindPolicyBinding(t *testing.T) {
+	key := "agent-1"
+	bindings := []api.PolicySubjectBinding{
+		{SubjectID: "sub_1", ExternalKey: &key, Precedence: 10},
+		{SubjectID: "sub_2", Precedence: 20},
+	}
+
+	if got, ok := findPolicyBinding(bindings, "agent-1"); !ok || got.SubjectID != "sub_1" {
+		t.Fatalf("expected sub_1 by external key, got %+v (ok=%t)", got, ok)
+	}
+	if got, ok := findPolicyBinding(bindings, "sub_2"); !ok || got.Precedence != 20 {
+		t.Fatalf("expected sub_2 by ID, got %+v (ok=%t)", got, ok)
+	}
+	if _, ok := findPolicyBinding(bindings, "missing"); ok {
+		t.Fatalf("expected no binding for unknown subject")
+	}
+}
+
+func TestPolicyBindingInput(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	inactive := &api.PolicySubjectBinding{SubjectID: "sub_1", Precedence: 10, Active: false}
+
+	tests := []struct {
+		name       string
+		existing   *api.PolicySubjectBinding
+		precedence *int
+		active     *bool
+		want       api.PolicyBindingInput
+	}{
+		{name: "new binding", want: api.PolicyBindingInput{SubjectExternalKey: "agent-1", Precedence: 100, Active: true}},
+		{
+			name:   "new inactive binding",
+			active: boolPtr(false),
+			want:   api.PolicyBindingInput{SubjectExternalKey: "agent-1", Precedence: 100},
+		},
+		{
+			name:       "precedence keeps inactive",
+			existing:   inactive,
+			precedence: intPtr(5),
+			want:       api.PolicyBindingInput{SubjectExternalKey: "agent-1", Precedence: 5},
+		},
+		{
+			name:     "reactivate",
+			existing: inactive,
+			active:   boolPtr(true),
+			want:     api.PolicyBindingInput{SubjectExternalKey: "agent-1", Precedence: 10, Active: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policyBindingInput("agent-1", tt.existing, tt.precedence, tt.active)
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSortedPolicyBindings(t *testing.T) {
+	bindings := []api.PolicySubjectBinding{
+		{SubjectID: "sub_c", Precedence: 20},
+		{SubjectID: "sub_b", Precedence: 10},
+		{SubjectID: "sub_a", Precedence: 20},
+	}
+
+	sorted := sortedPolicyBindings(bindings)
+	got := []string{sorted[0].SubjectID, sorted[1].SubjectID, sorted[2].SubjectID}
+	if strings.Join(got, ",") != "sub_b,sub_a,sub_c" {
+		t.Fatalf("unexpected order %v", got)
+	}
+	if bindings[0].SubjectID != "sub_c" {
+		t.Fatalf("expected input to be left unsorted")
+	}
+}
+
+func TestPrintSubjectResolution(t *testing.T) {
+	res := policy.ResolveBindings([]policy.Binding{
+		{PolicyID: "pol_b", PolicyName: "Fallback", Precedence: 50, Active: true},
+		{PolicyID: "pol_a", PolicyName: "Team", Precedence: 10, Active: true},
+		{PolicyID: "pol_c", PolicyName: "Old", Precedence: 1, Active: false},
+	})
+
+	var out bytes.Buffer
+	printSubjectResolution(&out, res)
+	text := out.String()
+	for _, want := range []string{
+		"1. policy_id=pol_c",
+		"binding is inactive",
+		"2. policy_id=pol_a name=Team status= precedence=10 active=true (effective)",
+		"shadowed by pol_a (precedence 10 < 50)",
+		"Effective policy: pol_a (Team)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	out.Reset()
+	printSubjectResolution(&out, policy.ResolveBindings(nil))
+	if !strings.Contains(out.String(), "No policy bindings.") {
+		t.Fatalf("unexpected output for no bindings: %s", out.String())
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"github.com/spf13/cobra"
+)
+
+func newPolicyExportCmd() *cobra.Command {
+	var file string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "export <policy-id>",
+		Short: "Export a policy as a declarative YAML or JSON file",
+		Example: strings.TrimSpace(`
+  openspend dashboard policy export <policy-id> -f policy.yaml
+  openspend dashboard policy export <policy-id> --format json
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+			outFormat, err := resolvePolicyFileFormat(cmd, file, format)
+			if err != nil {
+				return err
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			doc := policyfile.FromDetails(res)
+			if strings.TrimSpace(file) == "" {
+				return policyfile.Encode(cmd.OutOrStdout(), doc, outFormat)
+			}
+
+			f, err := os.Create(file)
+			if err != nil {
+				return err
+			}
+			if err := policyfile.Encode(f, doc, outFormat); err != nil {
+				_ = f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Policy %s exported to %s\n", res.Policy.ID, file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Output file (stdout if omitted)")
+	cmd.Flags().StringVar(&format, "format", "", "Output format (yaml|json); defaults to the file extension, or yaml")
+	return cmd
+}
+
+func newPolicyApplyCmd() *cobra.Command {
+	var file string
+	var expectedVersion int
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Reconcile a policy to a declarative YAML or JSON file",
+		Long: strings.TrimSpace(`
+Reconcile a policy to a declarative YAML or JSON file.
+
+The server policy is replaced with the file's metadata and rules. Bindings are
+replaced when the file has a bindings list and left untouched when it does not.
+A file without metadata.id creates a new policy.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy apply -f policy.yaml
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(file) == "" {
+				return fmt.Errorf("--file is required")
+			}
+			doc, err := policyfile.Load(file)
+			if err != nil {
+				return err
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			req := doc.ApplyRequest()
+			req.ExpectedVersion = expectedVersion
+			policyID := strings.TrimSpace(doc.Metadata.ID)
+			created := policyID == ""
+			res, err := applyPolicyDocument(cmd, client, policyID, req)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if created {
+				fmt.Fprintf(
+					cmd.OutOrStdout(),
+					"Policy created. Add `id: %s` under metadata in %s to manage it from this file.\n",
+					res.Policy.ID,
+					file,
+				)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), "Policy applied.")
+			}
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Policy file (.yaml, .yml or .json)")
+	cmd.Flags().IntVar(&expectedVersion, "expected-version", 0, "Fail if the server policy version differs (optimistic locking)")
+	return cmd
+}
+
+func resolvePolicyFileFormat(cmd *cobra.Command, file, format string) (policyfile.Format, error) {
+	if !cmd.Flags().Changed("format") {
+		return policyfile.FormatFromPath(file), nil
+	}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "yaml", "yml":
+		return policyfile.FormatYAML, nil
+	case "json":
+		return policyfile.FormatJSON, nil
+	default:
+		return "", fmt.Errorf("--format must be one of: yaml, json")
+	}
+}
+
+func applyPolicyDocument(
+	cmd *cobra.Command,
+	client *api.Client,
+	policyID string,
+	req api.ApplyPolicyRequest,
+) (api.PolicyDetailsResponse, error) {
+	if policyID == "" {
+		return client.CreatePolicy(cmd.Context(), req)
+	}
+	return client.ApplyPolicy(cmd.Context(), policyID, req)
+}
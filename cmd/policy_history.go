@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"github.com/spf13/cobra"
+)
+
+func newPolicyHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history <policy-id>",
+		Short: "List saved versions of a policy",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.ListPolicyVersions(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if len(res.Versions) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No versions found.")
+				return nil
+			}
+			for _, v := range res.Versions {
+				fmt.Fprintf(
+					cmd.OutOrStdout(),
+					"- version=%d created_at=%s author=%s summary=%s\n",
+					v.Version,
+					v.CreatedAt,
+					policyVersionAuthor(v),
+					optionalString(v.ChangeSummary),
+				)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Total versions: %d\n", len(res.Versions))
+			return nil
+		},
+	}
+}
+
+func newPolicyShowCmd() *cobra.Command {
+	var version int
+
+	cmd := &cobra.Command{
+		Use:   "show <policy-id>",
+		Short: "Show a policy, optionally as it was at an earlier version",
+		Example: strings.TrimSpace(`
+  openspend dashboard policy show <policy-id> --version 3
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+			if cmd.Flags().Changed("version") && version <= 0 {
+				return fmt.Errorf("--version must be positive")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			var res api.PolicyDetailsResponse
+			var err error
+			if version > 0 {
+				res, err = client.GetPolicyVersion(cmd.Context(), policyID, version)
+			} else {
+				res, err = client.GetPolicyDetails(cmd.Context(), policyID)
+			}
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&version, "version", 0, "Policy version to show (default: current)")
+	return cmd
+}
+
+func newPolicyRollbackCmd() *cobra.Command {
+	var (
+		to              int
+		includeBindings bool
+		dryRun          bool
+		yes             bool
+		noColor         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rollback <policy-id>",
+		Short: "Restore a policy to an earlier version",
+		Long: strings.TrimSpace(`
+Restore a policy to an earlier version.
+
+The policy's metadata and rules are set back to their state at --to, which is
+saved as a new version. Subject bindings are left as they are unless
+--include-bindings is given. The changes are printed and confirmed before
+they are applied; use --yes to skip the prompt or --dry-run to only print
+them.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy rollback <policy-id> --to 3 --dry-run
+  openspend dashboard policy rollback <policy-id> --to 3
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+			if to <= 0 {
+				return fmt.Errorf("--to must be a positive version")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			if to == current.Policy.Version {
+				fmt.Fprintf(cmd.OutOrStdout(), "Policy %s is already at version %d.\n", policyID, to)
+				return nil
+			}
+			target, err := client.GetPolicyVersion(cmd.Context(), policyID, to)
+			if err != nil {
+				return err
+			}
+
+			doc := rollbackDocument(current, target, includeBindings)
+			plan := policyfile.Diff(policyfile.FromDetails(current), doc, policyfile.DiffOptions{MatchRuleIDs: true})
+			title := fmt.Sprintf("Policy %s: version %d -> %d", policyID, current.Policy.Version, to)
+			p := planPrinter{w: cmd.OutOrStdout(), color: !noColor && colorEnabled(cmd.OutOrStdout())}
+			p.print(title, plan)
+
+			if !plan.HasChanges() || dryRun {
+				return persistAuthFromClient(&cfg, client)
+			}
+			if !yes {
+				confirmed, err := confirmAction(cmd, fmt.Sprintf("Roll back policy %s to version %d?", policyID, to))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+					return persistAuthFromClient(&cfg, client)
+				}
+			}
+
+			req := doc.ApplyRequest()
+			req.ExpectedVersion = current.Policy.Version
+			res, err := client.ApplyPolicy(cmd.Context(), policyID, req)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "\nPolicy rolled back to version %d.\n", to)
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&to, "to", 0, "Version to restore")
+	cmd.Flags().BoolVar(&includeBindings, "include-bindings", false, "Also restore subject bindings")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without applying them")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Roll back without asking for confirmation")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	return cmd
+}
+
+// rollbackDocument builds the desired state for a rollback from an old
+// version. Rule IDs that no longer exist are dropped so the server recreates
+// those rules, and bindings are left untouched unless requested. A version
+// without a description clears the current one rather than keeping it.
+func rollbackDocument(current, target api.PolicyDetailsResponse, includeBindings bool) policyfile.Document {
+	doc := policyfile.FromDetails(target)
+	doc.Metadata.ID = current.Policy.ID
+	doc.Metadata.Version = 0
+	if doc.Metadata.Description == nil {
+		empty := ""
+		doc.Metadata.Description = &empty
+	}
+
+	existing := make(map[string]struct{}, len(current.Rules))
+	for _, rule := range current.Rules {
+		existing[rule.ID] = struct{}{}
+	}
+	for i := range doc.Rules {
+		if _, ok := existing[doc.Rules[i].ID]; !ok {
+			doc.Rules[i].ID = ""
+		}
+	}
+	if !includeBindings {
+		doc.Bindings = nil
+	}
+	return doc
+}
+
+func policyVersionAuthor(v api.PolicyVersion) string {
+	if v.AuthorName != nil && strings.TrimSpace(*v.AuthorName) != "" {
+		return strings.TrimSpace(*v.AuthorName)
+	}
+	if strings.TrimSpace(v.AuthorUserID) != "" {
+		return v.AuthorUserID
+	}
+	return "(unknown)"
+}
+
+func optionalString(value *string) string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return "(none)"
+	}
+	return strings.TrimSpace(*value)
+}
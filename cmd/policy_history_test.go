@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+)
+
+func TestRollbackDocument(t *testing.T) {
+	var current api.PolicyDetailsResponse
+	description := "Team buyer"
+	current.Policy = api.PolicyInfo{ID: "pol_1", Name: "Buyer v5", Description: &description, Version: 5}
+	current.Rules = []api.PolicyRule{{ID: "kept", Effect: "allow", Scope: "global", Enabled: true}}
+	current.SubjectBindings = []api.PolicySubjectBinding{{SubjectID: "sub_1", Active: true}}
+
+	var target api.PolicyDetailsResponse
+	target.Policy = api.PolicyInfo{ID: "pol_1", Name: "Buyer v3", Version: 3}
+	target.Rules = []api.PolicyRule{
+		{ID: "kept", Effect: "allow", Scope: "global", Enabled: true},
+		{ID: "deleted", Effect: "deny", Scope: "host", Enabled: true},
+	}
+
+	doc := rollbackDocument(current, target, false)
+	if doc.Metadata.ID != "pol_1" || doc.Metadata.Name != "Buyer v3" || doc.Metadata.Version != 0 {
+		t.Fatalf("unexpected metadata: %+v", doc.Metadata)
+	}
+	if doc.Metadata.Description == nil || *doc.Metadata.Description != "" {
+		t.Fatalf("expected a missing description to clear the current one, got %v", doc.Metadata.Description)
+	}
+	plan := policyfile.Diff(policyfile.FromDetails(current), doc, policyfile.DiffOptions{MatchRuleIDs: true})
+	if len(plan.Metadata) != 2 || plan.Metadata[1] != (policyfile.FieldChange{Field: "description", From: "Team buyer", To: ""}) {
+		t.Fatalf("expected the plan to show the description change, got %+v", plan.Metadata)
+	}
+	if doc.Rules[0].ID != "kept" || doc.Rules[1].ID != "" {
+		t.Fatalf("expected only existing rule IDs to be kept, got %q and %q", doc.Rules[0].ID, doc.Rules[1].ID)
+	}
+	if doc.Bindings != nil {
+		t.Fatalf("expected bindings to be left untouched")
+	}
+
+	doc = rollbackDocument(current, target, true)
+	if doc.Bindings == nil || len(doc.Bindings) != 0 {
+		t.Fatalf("expected bindings restored to the empty target list, got %+v", doc.Bindings)
+	}
+}
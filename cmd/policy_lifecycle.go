@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func newPolicyCloneCmd() *cobra.Command {
+	var name string
+	var description string
+
+	cmd := &cobra.Command{
+		Use:   "clone <policy-id>",
+		Short: "Copy a policy's rules into a new policy",
+		Long: strings.TrimSpace(`
+Copy a policy's mode, status and rules into a new policy. Subject bindings are
+not copied; bind subjects to the new policy separately.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy clone <policy-id> --name "Team B Buyer Policy"
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			req := api.ClonePolicyRequest{Name: strings.TrimSpace(name)}
+			if cmd.Flags().Changed("description") {
+				value := strings.TrimSpace(description)
+				req.Description = &value
+			}
+			res, err := client.ClonePolicy(cmd.Context(), policyID, req)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Policy cloned from %s.\n", policyID)
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name of the new policy")
+	cmd.Flags().StringVar(&description, "description", "", "Description of the new policy (default: copied)")
+	return cmd
+}
+
+func newPolicyArchiveCmd() *cobra.Command {
+	var rebindTo string
+
+	cmd := &cobra.Command{
+		Use:   "archive <policy-id>",
+		Short: "Archive a policy",
+		Long: strings.TrimSpace(`
+Archive a policy. Archived policies are kept for reference but no longer
+apply. A policy with active subject bindings is only archived with
+--rebind-to, which moves those bindings to another policy first.
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			if err := checkPolicyRebind(cmd.Context(), client, current, rebindTo); err != nil {
+				return err
+			}
+
+			res, err := client.ArchivePolicy(cmd.Context(), policyID, api.PolicyRebindRequest{RebindTo: strings.TrimSpace(rebindTo)})
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Policy archived.")
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rebindTo, "rebind-to", "", "Policy ID that takes over active subject bindings")
+	return cmd
+}
+
+func newPolicyDeleteCmd() *cobra.Command {
+	var rebindTo string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <policy-id>",
+		Short: "Delete a policy",
+		Long: strings.TrimSpace(`
+Delete a policy permanently. Asks for confirmation unless --force is given. A
+policy with active subject bindings is only deleted with --rebind-to, which
+moves those bindings to another policy first.
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			if err := checkPolicyRebind(cmd.Context(), client, current, rebindTo); err != nil {
+				return err
+			}
+
+			if !force {
+				prompt := fmt.Sprintf("Delete policy %s (%s)? This cannot be undone.", current.Policy.Name, current.Policy.ID)
+				confirmed, err := confirmAction(cmd, prompt)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+					return nil
+				}
+			}
+
+			res, err := client.DeletePolicy(cmd.Context(), policyID, rebindTo)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Policy deleted: %s\n", policyID)
+			if res.ReboundSubjects > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Subjects rebound to %s: %d\n", strings.TrimSpace(rebindTo), res.ReboundSubjects)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rebindTo, "rebind-to", "", "Policy ID that takes over active subject bindings")
+	cmd.Flags().BoolVar(&force, "force", false, "Delete without asking for confirmation")
+	return cmd
+}
+
+// checkPolicyRebind refuses to retire a policy that still has active subject
+// bindings unless rebindTo names another existing, non-archived policy.
+func checkPolicyRebind(ctx context.Context, client *api.Client, current api.PolicyDetailsResponse, rebindTo string) error {
+	rebindTo = strings.TrimSpace(rebindTo)
+	active := activePolicyBindings(current)
+
+	if rebindTo == "" {
+		if len(active) == 0 {
+			return nil
+		}
+		subjects := make([]string, 0, len(active))
+		for _, binding := range active {
+			subjects = append(subjects, policyBindingLabel(binding))
+		}
+		return fmt.Errorf(
+			"policy %s has %d active subject binding(s) (%s); pass --rebind-to <policy-id> to move them",
+			current.Policy.ID,
+			len(active),
+			strings.Join(subjects, ", "),
+		)
+	}
+
+	if rebindTo == current.Policy.ID {
+		return fmt.Errorf("--rebind-to must name a different policy")
+	}
+	target, err := client.GetPolicyDetails(ctx, rebindTo)
+	if err != nil {
+		return fmt.Errorf("--rebind-to: %w", err)
+	}
+	if target.Policy.Status == "archived" {
+		return fmt.Errorf("--rebind-to policy %s is archived", rebindTo)
+	}
+	return nil
+}
+
+func activePolicyBindings(res api.PolicyDetailsResponse) []api.PolicySubjectBinding {
+	active := make([]api.PolicySubjectBinding, 0)
+	for _, binding := range res.SubjectBindings {
+		if binding.Active {
+			active = append(active, binding)
+		}
+	}
+	return active
+}
+
+func policyBindingLabel(binding api.PolicySubjectBinding) string {
+	if binding.ExternalKey != nil && strings.TrimSpace(*binding.ExternalKey) != "" {
+		return strings.TrimSpace(*binding.ExternalKey)
+	}
+	return binding.SubjectID
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func TestConfirmAction(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "y\n", want: true},
+		{input: "YES\n", want: true},
+		{input: "\n", want: false},
+		{input: "n\n", want: false},
+		{input: "", want: false},
+		{input: "maybe\ny\n", want: true},
+		{input: "maybe", want: false},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(tt.input))
+		cmd.SetOut(&bytes.Buffer{})
+		got, err := confirmAction(cmd, "Continue?")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Fatalf("input %q: expected %t, got %t", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestCheckPolicyRebind(t *testing.T) {
+	key := "agent-1"
+	var res api.PolicyDetailsResponse
+	res.Policy.ID = "pol_1"
+	res.SubjectBindings = []api.PolicySubjectBinding{
+		{SubjectID: "sub_1", ExternalKey: &key, Active: true},
+		{SubjectID: "sub_2", Active: false},
+	}
+
+	err := checkPolicyRebind(context.Background(), nil, res, "")
+	if err == nil || !strings.Contains(err.Error(), "1 active subject binding(s) (agent-1)") {
+		t.Fatalf("expected active binding error, got %v", err)
+	}
+	if err := checkPolicyRebind(context.Background(), nil, res, "pol_1"); err == nil {
+		t.Fatalf("expected error when rebinding to the same policy")
+	}
+
+	res.SubjectBindings = res.SubjectBindings[1:]
+	if err := checkPolicyRebind(context.Background(), nil, res, ""); err != nil {
+		t.Fatalf("expected no error without active bindings, got %v", err)
+	}
+}
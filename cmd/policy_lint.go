@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policy"
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"github.com/spf13/cobra"
+)
+
+func newPolicyLintCmd() *cobra.Command {
+	var failOn string
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "lint <policy-id|file>",
+		Short: "Check a policy for unreachable, conflicting or risky rules",
+		Long: strings.TrimSpace(`
+Check a policy for unreachable, conflicting or risky rules.
+
+Accepts a policy ID or a declarative policy file. Findings are printed from
+most to least severe (error, warning, info). The command exits 1 when a
+finding is at or above --fail-on (default error); use --fail-on none to
+always exit 0.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy lint <policy-id>
+  openspend dashboard policy lint policy.yaml --fail-on warning
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := strings.TrimSpace(args[0])
+			if target == "" {
+				return fmt.Errorf("policy ID or file is required")
+			}
+			var threshold policy.Severity
+			if !strings.EqualFold(strings.TrimSpace(failOn), "none") {
+				value, err := policy.ParseSeverity(failOn)
+				if err != nil {
+					return fmt.Errorf("--fail-on: %w", err)
+				}
+				threshold = value
+			}
+
+			details, label, err := loadPolicyForLint(cmd, target)
+			if err != nil {
+				return err
+			}
+			findings := policy.Lint(details)
+
+			if jsonOut {
+				payload, err := json.MarshalIndent(findings, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %d finding(s)\n", label, len(findings))
+				for _, f := range findings {
+					rule := f.RuleID
+					if rule == "" {
+						rule = "-"
+					}
+					fmt.Fprintf(
+						cmd.OutOrStdout(),
+						"- severity=%s code=%s rule=%s message=%s\n",
+						f.Severity,
+						f.Code,
+						rule,
+						f.Message,
+					)
+				}
+			}
+
+			if threshold == "" {
+				return nil
+			}
+			for _, f := range findings {
+				if f.Severity.Rank() >= threshold.Rank() {
+					cmd.SilenceErrors = true
+					cmd.SilenceUsage = true
+					return &ExitError{Code: 1}
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&failOn, "fail-on", string(policy.SeverityError), "Exit 1 on findings at or above this severity (error|warning|info|none)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Print findings as JSON")
+	return cmd
+}
+
+// loadPolicyForLint reads target as a policy file when it names one, and
+// otherwise fetches it as a policy ID. File rules without IDs are labelled
+// by position and the summary is derived from the rules.
+func loadPolicyForLint(cmd *cobra.Command, target string) (api.PolicyDetailsResponse, string, error) {
+	if isPolicyFilePath(target) {
+		doc, err := policyfile.Load(target)
+		if err != nil {
+			return api.PolicyDetailsResponse{}, "", err
+		}
+		details := doc.ToDetails()
+		for i := range details.Rules {
+			if details.Rules[i].ID == "" {
+				details.Rules[i].ID = fmt.Sprintf("rules[%d]", i)
+			}
+		}
+		summary := policyfile.Summarize(doc.Rules)
+		details.Summary.AllowAssets = summary.AllowAssets
+		details.Summary.AllowNetworks = summary.AllowNetworks
+		details.Summary.DenyHosts = summary.DenyHosts
+		details.Summary.RequireIdentifiedAgent = summary.RequireIdentifiedAgent
+		return details, fmt.Sprintf("Policy file %s", target), nil
+	}
+
+	cfg := mustLoadConfig()
+	client := clientFromConfig(cfg)
+
+	details, err := client.GetPolicyDetails(cmd.Context(), target)
+	if err != nil {
+		return api.PolicyDetailsResponse{}, "", err
+	}
+	if err := persistAuthFromClient(&cfg, client); err != nil {
+		return api.PolicyDetailsResponse{}, "", err
+	}
+	return details, fmt.Sprintf("Policy %s (%s)", details.Policy.ID, details.Policy.Name), nil
+}
+
+func isPolicyFilePath(target string) bool {
+	switch strings.ToLower(filepath.Ext(target)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	info, err := os.Stat(target)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"github.com/spf13/cobra"
+)
+
+// planChangesExitCode is returned when a plan or diff finds changes, so
+// scripts can tell "no changes" (0) from "changes" (2) and failures (1).
+const planChangesExitCode = 2
+
+func newPolicyPlanCmd() *cobra.Command {
+	var file string
+	var noColor bool
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show what policy apply would change",
+		Long: strings.TrimSpace(`
+Show what policy apply would change, without changing anything.
+
+Compares a declarative policy file with the live policy named by its
+metadata.id. Exits 0 when there are no changes, 2 when there are changes and
+1 on errors.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy plan -f policy.yaml
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(file) == "" {
+				return fmt.Errorf("--file is required")
+			}
+			desired, err := policyfile.Load(file)
+			if err != nil {
+				return err
+			}
+
+			current := policyfile.Document{}
+			title := fmt.Sprintf("Policy %q (new)", desired.Metadata.Name)
+			if policyID := strings.TrimSpace(desired.Metadata.ID); policyID != "" {
+				cfg := mustLoadConfig()
+				client := clientFromConfig(cfg)
+
+				res, err := client.GetPolicyDetails(cmd.Context(), policyID)
+				if err != nil {
+					return err
+				}
+				if err := persistAuthFromClient(&cfg, client); err != nil {
+					return err
+				}
+				current = policyfile.FromDetails(res)
+				title = fmt.Sprintf("Policy %s (version %d)", res.Policy.ID, res.Policy.Version)
+			}
+
+			plan := policyfile.Diff(current, desired, policyfile.DiffOptions{MatchRuleIDs: true})
+			return reportPlan(cmd, title, plan, noColor)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Policy file (.yaml, .yml or .json)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	return cmd
+}
+
+func newPolicyDiffCmd() *cobra.Command {
+	var noColor bool
+
+	cmd := &cobra.Command{
+		Use:   "diff <policy-id-a> <policy-id-b>",
+		Short: "Compare two policies",
+		Long: strings.TrimSpace(`
+Compare two policies, showing what would change to turn the first into the
+second. Rules are paired by effect, scope, host, asset and network. Exits 0
+when the policies match, 2 when they differ and 1 on errors.
+`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idA := strings.TrimSpace(args[0])
+			idB := strings.TrimSpace(args[1])
+			if idA == "" || idB == "" {
+				return fmt.Errorf("two policy IDs are required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			a, err := client.GetPolicyDetails(cmd.Context(), idA)
+			if err != nil {
+				return err
+			}
+			b, err := client.GetPolicyDetails(cmd.Context(), idB)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			plan := policyfile.Diff(policyfile.FromDetails(a), policyfile.FromDetails(b), policyfile.DiffOptions{})
+			title := fmt.Sprintf("Policy %s -> %s", a.Policy.ID, b.Policy.ID)
+			return reportPlan(cmd, title, plan, noColor)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	return cmd
+}
+
+// reportPlan prints the plan and returns an ExitError when it has changes.
+func reportPlan(cmd *cobra.Command, title string, plan policyfile.Plan, noColor bool) error {
+	out := cmd.OutOrStdout()
+	p := planPrinter{w: out, color: !noColor && colorEnabled(out)}
+	p.print(title, plan)
+	if !plan.HasChanges() {
+		return nil
+	}
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &ExitError{Code: planChangesExitCode}
+}
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiBold   = "\033[1m"
+)
+
+// colorEnabled reports whether w is a terminal and NO_COLOR is unset.
+func colorEnabled(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+type planPrinter struct {
+	w     io.Writer
+	color bool
+}
+
+func (p planPrinter) paint(code, text string) string {
+	if !p.color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+func (p planPrinter) symbol(kind policyfile.ChangeKind) string {
+	switch kind {
+	case policyfile.Added:
+		return p.paint(ansiGreen, "+")
+	case policyfile.Removed:
+		return p.paint(ansiRed, "-")
+	default:
+		return p.paint(ansiYellow, "~")
+	}
+}
+
+func (p planPrinter) print(title string, plan policyfile.Plan) {
+	fmt.Fprintln(p.w, p.paint(ansiBold, title))
+	if !plan.HasChanges() {
+		fmt.Fprintln(p.w, "No changes.")
+		return
+	}
+
+	for _, f := range plan.Metadata {
+		fmt.Fprintf(p.w, "  %s metadata.%s: %s\n", p.symbol(policyfile.Modified), f.Field, formatFieldChange(f))
+	}
+	for _, f := range plan.Summary {
+		fmt.Fprintf(p.w, "  %s summary.%s: %s\n", p.symbol(policyfile.Modified), f.Field, formatFieldChange(f))
+	}
+	for _, r := range plan.Rules {
+		fmt.Fprintf(p.w, "  %s rule %s\n", p.symbol(r.Kind), r.Label)
+		p.printFields(r.Kind, r.Fields)
+	}
+	for _, b := range plan.Bindings {
+		fmt.Fprintf(p.w, "  %s binding %s\n", p.symbol(b.Kind), b.Subject)
+		p.printFields(b.Kind, b.Fields)
+	}
+
+	added, changed, removed := plan.Counts()
+	fmt.Fprintf(p.w, "\nPlan: %d to add, %d to change, %d to remove.\n", added, changed, removed)
+}
+
+func (p planPrinter) printFields(kind policyfile.ChangeKind, fields []policyfile.FieldChange) {
+	for _, f := range fields {
+		switch kind {
+		case policyfile.Added:
+			fmt.Fprintf(p.w, "      %s = %s\n", f.Field, f.To)
+		case policyfile.Removed:
+			fmt.Fprintf(p.w, "      %s = %s\n", f.Field, f.From)
+		default:
+			fmt.Fprintf(p.w, "      %s: %s\n", f.Field, formatFieldChange(f))
+		}
+	}
+}
+
+func formatFieldChange(f policyfile.FieldChange) string {
+	return fmt.Sprintf("%s -> %s", displayFieldValue(f.From), displayFieldValue(f.To))
+}
+
+func displayFieldValue(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	return value
+}
@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+const defaultRulePriority = 100
+
+func newPolicyRuleCmd() *cobra.Command {
+	ruleCmd := &cobra.Command{
+		Use:   "rule",
+		Short: "Manage individual policy rules",
+		Long: strings.TrimSpace(`
+Manage individual policy rules.
+
+Rules are evaluated in ascending priority order. A rule's scope says what it
+targets: global (every purchase), host (a service host), network or asset.
+Rule IDs are listed by policy describe.
+`),
+	}
+	ruleCmd.AddCommand(newPolicyRuleAddCmd())
+	ruleCmd.AddCommand(newPolicyRuleUpdateCmd())
+	ruleCmd.AddCommand(newPolicyRuleRemoveCmd())
+	ruleCmd.AddCommand(newPolicyRuleToggleCmd("enable", true))
+	ruleCmd.AddCommand(newPolicyRuleToggleCmd("disable", false))
+	ruleCmd.AddCommand(newPolicyRuleReorderCmd())
+	return ruleCmd
+}
+
+func newPolicyRuleAddCmd() *cobra.Command {
+	var (
+		effect                 string
+		scope                  string
+		host                   string
+		asset                  string
+		network                string
+		minScore               int
+		maxPrice               string
+		requireIdentifiedAgent bool
+		priority               int
+		disabled               bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <policy-id>",
+		Short: "Add a rule to a policy",
+		Example: strings.TrimSpace(`
+  openspend dashboard policy rule add <policy-id> --effect deny --host bad.example.com --priority 10
+  openspend dashboard policy rule add <policy-id> --effect allow --network base --asset USDC --max-price 0.25USDC
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+
+			cfg := mustLoadConfig()
+
+			rule := api.PolicyRuleInput{
+				Priority: priority,
+				Enabled:  !disabled,
+			}
+			value, err := parseRuleEffect(effect)
+			if err != nil {
+				return err
+			}
+			rule.Effect = value
+
+			rule.ResourceHost = optionalTrimmed(strings.ToLower(host))
+			rule.Asset = optionalTrimmed(asset)
+			rule.Network = optionalTrimmed(network)
+
+			if cmd.Flags().Changed("min-score") {
+				if minScore < 0 {
+					return fmt.Errorf("--min-score must be non-negative")
+				}
+				rule.MinScore = &minScore
+			}
+			if strings.TrimSpace(maxPrice) != "" {
+				baseUnits, resolvedAsset, err := resolveMaxPriceFlag(cfg, maxPrice, asset, network)
+				if err != nil {
+					return err
+				}
+				value := fmt.Sprintf("%d", baseUnits)
+				rule.MaxPrice = &value
+				rule.Asset = optionalTrimmed(resolvedAsset)
+			}
+			// Inferred after --max-price, whose unit may pin the asset.
+			rule.Scope, err = resolveRuleScope(scope, rule)
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("require-identified-agent") {
+				rule.RequireIdentifiedAgent = &requireIdentifiedAgent
+			}
+
+			client := clientFromConfig(cfg)
+
+			res, err := client.CreatePolicyRule(cmd.Context(), policyID, rule)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Rule added.")
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&effect, "effect", "", "Rule effect (allow|deny)")
+	cmd.Flags().StringVar(&scope, "scope", "", "Rule scope (global|host|network|asset); inferred from --host, --network and --asset when omitted")
+	cmd.Flags().StringVar(&host, "host", "", "Service host the rule applies to")
+	cmd.Flags().StringVar(&asset, "asset", "", "Asset the rule applies to")
+	cmd.Flags().StringVar(&network, "network", "", "Network the rule applies to")
+	cmd.Flags().IntVar(&minScore, "min-score", 0, "Minimum service score")
+	cmd.Flags().StringVar(
+		&maxPrice,
+		"max-price",
+		"",
+		"Max price: base units (500000) or an amount with unit (0.5USDC, 5USD)",
+	)
+	cmd.Flags().BoolVar(&requireIdentifiedAgent, "require-identified-agent", false, "Require an identified agent")
+	cmd.Flags().IntVar(&priority, "priority", defaultRulePriority, "Rule priority (lower values are evaluated first)")
+	cmd.Flags().BoolVar(&disabled, "disabled", false, "Create the rule disabled")
+	return cmd
+}
+
+func newPolicyRuleUpdateCmd() *cobra.Command {
+	var (
+		effect                      string
+		scope                       string
+		host                        string
+		asset                       string
+		network                     string
+		minScore                    int
+		maxPrice                    string
+		requireIdentifiedAgent      bool
+		priority                    int
+		clearHost                   bool
+		clearAsset                  bool
+		clearNetwork                bool
+		clearMinScore               bool
+		clearMaxPrice               bool
+		clearRequireIdentifiedAgent bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update <policy-id> <rule-id>",
+		Short: "Update a policy rule",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			ruleID := strings.TrimSpace(args[1])
+			if policyID == "" || ruleID == "" {
+				return fmt.Errorf("policy ID and rule ID are required")
+			}
+
+			cfg := mustLoadConfig()
+			patch := map[string]any{}
+
+			if cmd.Flags().Changed("effect") {
+				value, err := parseRuleEffect(effect)
+				if err != nil {
+					return err
+				}
+				patch["effect"] = value
+			}
+			if cmd.Flags().Changed("scope") {
+				value, err := parseRuleScope(scope)
+				if err != nil {
+					return err
+				}
+				patch["scope"] = value
+			}
+			if cmd.Flags().Changed("priority") {
+				patch["priority"] = priority
+			}
+
+			clearable := []struct {
+				flag  string
+				field string
+				clear bool
+				value string
+			}{
+				{"host", "resourceHost", clearHost, strings.ToLower(host)},
+				{"asset", "asset", clearAsset, asset},
+				{"network", "network", clearNetwork, network},
+			}
+			for _, c := range clearable {
+				if c.clear && cmd.Flags().Changed(c.flag) {
+					return fmt.Errorf("use either --%s or --clear-%s", c.flag, c.flag)
+				}
+				if c.clear {
+					patch[c.field] = nil
+				}
+				if cmd.Flags().Changed(c.flag) {
+					value := strings.TrimSpace(c.value)
+					if value == "" {
+						return fmt.Errorf("--%s must not be empty", c.flag)
+					}
+					patch[c.field] = value
+				}
+			}
+
+			if clearMinScore && cmd.Flags().Changed("min-score") {
+				return fmt.Errorf("use either --min-score or --clear-min-score")
+			}
+			if clearMinScore {
+				patch["minScore"] = nil
+			}
+			if cmd.Flags().Changed("min-score") {
+				if minScore < 0 {
+					return fmt.Errorf("--min-score must be non-negative")
+				}
+				patch["minScore"] = minScore
+			}
+
+			if clearMaxPrice && cmd.Flags().Changed("max-price") {
+				return fmt.Errorf("use either --max-price or --clear-max-price")
+			}
+			if clearMaxPrice {
+				patch["maxPrice"] = nil
+			}
+			if cmd.Flags().Changed("max-price") {
+				maxPriceAsset := ""
+				if cmd.Flags().Changed("asset") {
+					maxPriceAsset = asset
+				}
+				baseUnits, resolvedAsset, err := resolveMaxPriceFlag(cfg, maxPrice, maxPriceAsset, network)
+				if err != nil {
+					return err
+				}
+				patch["maxPrice"] = fmt.Sprintf("%d", baseUnits)
+				// A unit on --max-price pins the rule asset so base units stay meaningful.
+				if resolvedAsset != "" && !cmd.Flags().Changed("asset") && !clearAsset {
+					patch["asset"] = resolvedAsset
+				}
+			}
+
+			if clearRequireIdentifiedAgent && cmd.Flags().Changed("require-identified-agent") {
+				return fmt.Errorf("use either --require-identified-agent or --clear-require-identified-agent")
+			}
+			if clearRequireIdentifiedAgent {
+				patch["requireIdentifiedAgent"] = nil
+			}
+			if cmd.Flags().Changed("require-identified-agent") {
+				patch["requireIdentifiedAgent"] = requireIdentifiedAgent
+			}
+
+			if len(patch) == 0 {
+				return fmt.Errorf("no update fields provided")
+			}
+
+			client := clientFromConfig(cfg)
+
+			res, err := client.UpdatePolicyRule(cmd.Context(), policyID, ruleID, patch)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Rule updated.")
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&effect, "effect", "", "Updated rule effect (allow|deny)")
+	cmd.Flags().StringVar(&scope, "scope", "", "Updated rule scope (global|host|network|asset)")
+	cmd.Flags().StringVar(&host, "host", "", "Updated service host")
+	cmd.Flags().BoolVar(&clearHost, "clear-host", false, "Clear the service host")
+	cmd.Flags().StringVar(&asset, "asset", "", "Updated asset")
+	cmd.Flags().BoolVar(&clearAsset, "clear-asset", false, "Clear the asset")
+	cmd.Flags().StringVar(&network, "network", "", "Updated network")
+	cmd.Flags().BoolVar(&clearNetwork, "clear-network", false, "Clear the network")
+	cmd.Flags().IntVar(&minScore, "min-score", 0, "Updated minimum service score")
+	cmd.Flags().BoolVar(&clearMinScore, "clear-min-score", false, "Clear the minimum score")
+	cmd.Flags().StringVar(
+		&maxPrice,
+		"max-price",
+		"",
+		"Updated max price: base units (500000) or an amount with unit (0.5USDC, 5USD)",
+	)
+	cmd.Flags().BoolVar(&clearMaxPrice, "clear-max-price", false, "Clear the max price")
+	cmd.Flags().BoolVar(&requireIdentifiedAgent, "require-identified-agent", false, "Set requireIdentifiedAgent")
+	cmd.Flags().BoolVar(
+		&clearRequireIdentifiedAgent,
+		"clear-require-identified-agent",
+		false,
+		"Clear requireIdentifiedAgent",
+	)
+	cmd.Flags().IntVar(&priority, "priority", 0, "Updated priority (lower values are evaluated first)")
+	return cmd
+}
+
+func newPolicyRuleRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <policy-id> <rule-id>",
+		Short: "Remove a rule from a policy",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			ruleID := strings.TrimSpace(args[1])
+			if policyID == "" || ruleID == "" {
+				return fmt.Errorf("policy ID and rule ID are required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.DeletePolicyRule(cmd.Context(), policyID, ruleID)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Rule removed.")
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+}
+
+func newPolicyRuleToggleCmd(use string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <policy-id> <rule-id>",
+		Short: strings.ToUpper(use[:1]) + use[1:] + " a policy rule",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			ruleID := strings.TrimSpace(args[1])
+			if policyID == "" || ruleID == "" {
+				return fmt.Errorf("policy ID and rule ID are required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.UpdatePolicyRule(cmd.Context(), policyID, ruleID, map[string]any{"enabled": enabled})
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Rule %sd.\n", use)
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+}
+
+func newPolicyRuleReorderCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reorder <policy-id> <rule-id>...",
+		Short: "Change rule evaluation order",
+		Long: strings.TrimSpace(`
+Change rule evaluation order.
+
+The listed rules move to the front in the given order; unlisted rules keep
+their relative order after them. Priorities are reassigned by the server.
+`),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			current, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+			order, err := reorderRuleIDs(current.Rules, args[1:])
+			if err != nil {
+				return err
+			}
+
+			res, err := client.ReorderPolicyRules(cmd.Context(), policyID, order)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Rules reordered.")
+			printPolicyDetails(cmd.OutOrStdout(), res)
+			return nil
+		},
+	}
+}
+
+// reorderRuleIDs returns every rule ID with first moved to the front, and
+// the remaining rules in their current priority order.
+func reorderRuleIDs(rules []api.PolicyRule, first []string) ([]string, error) {
+	known := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		known[rule.ID] = struct{}{}
+	}
+
+	order := make([]string, 0, len(rules))
+	placed := make(map[string]struct{}, len(first))
+	for _, raw := range first {
+		id := strings.TrimSpace(raw)
+		if _, ok := known[id]; !ok {
+			return nil, fmt.Errorf("rule %q not found in policy", id)
+		}
+		if _, dup := placed[id]; dup {
+			return nil, fmt.Errorf("rule %q listed more than once", id)
+		}
+		placed[id] = struct{}{}
+		order = append(order, id)
+	}
+
+	rest := make([]api.PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		if _, ok := placed[rule.ID]; !ok {
+			rest = append(rest, rule)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool { return rest[i].Priority < rest[j].Priority })
+	for _, rule := range rest {
+		order = append(order, rule.ID)
+	}
+	return order, nil
+}
+
+func parseRuleEffect(raw string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch value {
+	case "allow", "deny":
+		return value, nil
+	default:
+		return "", fmt.Errorf("--effect must be one of: allow, deny")
+	}
+}
+
+func parseRuleScope(raw string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch value {
+	case "global", "host", "network", "asset":
+		return value, nil
+	default:
+		return "", fmt.Errorf("--scope must be one of: global, host, network, asset")
+	}
+}
+
+// resolveRuleScope validates an explicit scope or infers one from the
+// rule's targets, preferring the most specific.
+func resolveRuleScope(raw string, rule api.PolicyRuleInput) (string, error) {
+	if strings.TrimSpace(raw) != "" {
+		scope, err := parseRuleScope(raw)
+		if err != nil {
+			return "", err
+		}
+		if scope == "host" && rule.ResourceHost == nil {
+			return "", fmt.Errorf("--scope host requires --host")
+		}
+		return scope, nil
+	}
+	switch {
+	case rule.ResourceHost != nil:
+		return "host", nil
+	case rule.Network != nil:
+		return "network", nil
+	case rule.Asset != nil:
+		return "asset", nil
+	default:
+		return "global", nil
+	}
+}
+
+func optionalTrimmed(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
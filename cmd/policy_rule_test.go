@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func TestReorderRuleIDs(t *testing.T) {
+	rules := []api.PolicyRule{
+		{ID: "a", Priority: 30},
+		{ID: "b", Priority: 10},
+		{ID: "c", Priority: 20},
+	}
+
+	t.Run("moves listed rules to the front", func(t *testing.T) {
+		got, err := reorderRuleIDs(rules, []string{"a"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("rejects unknown rules", func(t *testing.T) {
+		if _, err := reorderRuleIDs(rules, []string{"z"}); err == nil {
+			t.Fatalf("expected error for unknown rule")
+		}
+	})
+
+	t.Run("rejects duplicates", func(t *testing.T) {
+		if _, err := reorderRuleIDs(rules, []string{"c", "c"}); err == nil {
+			t.Fatalf("expected error for duplicate rule")
+		}
+	})
+}
+
+func TestResolveRuleScope(t *testing.T) {
+	host := "example.com"
+	network := "base"
+	tests := []struct {
+		name    string
+		raw     string
+		rule    api.PolicyRuleInput
+		want    string
+		wantErr bool
+	}{
+		{name: "global by default", want: "global"},
+		{name: "host inferred", rule: api.PolicyRuleInput{ResourceHost: &host, Network: &network}, want: "host"},
+		{name: "network inferred", rule: api.PolicyRuleInput{Network: &network}, want: "network"},
+		{name: "explicit scope", raw: "Asset", rule: api.PolicyRuleInput{Network: &network}, want: "asset"},
+		{name: "host scope without host", raw: "host", wantErr: true},
+		{name: "unknown scope", raw: "planet", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveRuleScope(tt.raw, tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+	"github.com/promptingcompany/openspend-cli/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+func newPolicySimulateCmd() *cobra.Command {
+	var (
+		resourceURL string
+		price       string
+		asset       string
+		network     string
+		subject     string
+		score       float64
+		jsonOut     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "simulate <policy-id>",
+		Short: "Check locally whether a purchase would be allowed",
+		Long: strings.TrimSpace(`
+Check locally whether a purchase would be allowed by a policy.
+
+Enabled rules are tried in ascending priority (deny before allow on ties) and
+the first rule whose host, asset and network match decides. A matching allow
+rule still denies when the purchase breaks its max price, min score or
+identified-agent requirement. When no rule matches, the purchase is denied.
+
+Rules targeting a host, asset or network that is not given do not match, and
+limits on a price or score that is not given are reported but not checked.
+
+With --subject, the agent's spending budget is fetched and an allowed purchase
+is denied when its price would take the daily or monthly spend past the limit.
+A price in another asset than the budget is converted through the FX table.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard policy simulate <policy-id> --resource-url https://api.example.com/v1/ocr --price 0.25USDC --network base
+  openspend dashboard policy simulate <policy-id> --resource-url api.example.com --price 250000 --asset USDC --subject buyer-agent-1
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyID := strings.TrimSpace(args[0])
+			if policyID == "" {
+				return fmt.Errorf("policy ID is required")
+			}
+
+			cfg := mustLoadConfig()
+
+			candidate := policy.Candidate{
+				ResourceURL: strings.TrimSpace(resourceURL),
+				Asset:       money.NormalizeSymbol(asset),
+				Network:     strings.TrimSpace(network),
+				Subject:     strings.TrimSpace(subject),
+				Identified:  strings.TrimSpace(subject) != "",
+			}
+			fx, err := fxTableFromConfig(cfg)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(price) != "" {
+				base, resolvedAsset, err := money.ResolvePriceBaseUnits(price, candidate.Asset, candidate.Network, fx)
+				if err != nil {
+					return fmt.Errorf("--price: %w", err)
+				}
+				candidate.Price = base
+				candidate.Asset = resolvedAsset
+			}
+			if cmd.Flags().Changed("score") {
+				if score < 0 || score > 100 {
+					return fmt.Errorf("--score must be between 0 and 100")
+				}
+				candidate.Score = &score
+			}
+
+			client := clientFromConfig(cfg)
+
+			res, err := client.GetPolicyDetails(cmd.Context(), policyID)
+			if err != nil {
+				return err
+			}
+
+			decision := policy.Evaluate(res, candidate)
+			if candidate.Subject != "" {
+				budget, err := client.GetAgentBudget(cmd.Context(), candidate.Subject)
+				if err != nil && !api.IsStatus(err, http.StatusNotFound) {
+					return err
+				}
+				decision = policy.ApplyBudget(decision, budget.Budget, candidate, fx)
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			if jsonOut {
+				payload, err := json.MarshalIndent(decision, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+				return nil
+			}
+			printPolicyDecision(cmd.OutOrStdout(), decision)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&resourceURL, "resource-url", "", "Resource URL (or host) being purchased")
+	cmd.Flags().StringVar(
+		&price,
+		"price",
+		"",
+		"Price: base units (250000) or an amount with unit (0.25USDC, 1USD converted into --asset)",
+	)
+	cmd.Flags().StringVar(&asset, "asset", "", "Payment asset (for example USDC)")
+	cmd.Flags().StringVar(&network, "network", "", "Payment network (for example base)")
+	cmd.Flags().StringVar(&subject, "subject", "", "Buying agent's external key; omit to simulate an unidentified agent")
+	cmd.Flags().Float64Var(&score, "score", 0, "Service score on the policy's 0-100 scale")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Print the decision and trace as JSON")
+	return cmd
+}
+
+func printPolicyDecision(out io.Writer, d policy.Decision) {
+	decision := "deny"
+	if d.Allowed {
+		decision = "allow"
+	}
+	matched := "(none)"
+	if d.Rule != nil {
+		matched = d.Rule.ID
+	}
+
+	fmt.Fprintf(out, "Decision: %s\n", decision)
+	fmt.Fprintf(out, "Matched rule: %s\n", matched)
+	fmt.Fprintf(out, "Reason: %s\n", d.Reason)
+	fmt.Fprintf(out, "Trace: %d\n", len(d.Trace))
+	for i, step := range d.Trace {
+		fmt.Fprintf(
+			out,
+			"%d. rule=%s priority=%d effect=%s scope=%s outcome=%s detail=%s\n",
+			i+1,
+			step.RuleID,
+			step.Priority,
+			step.Effect,
+			step.Scope,
+			step.Outcome,
+			step.Detail,
+		)
+	}
+	if d.Budget != nil {
+		for _, p := range d.Budget.Periods {
+			fmt.Fprintf(
+				out,
+				"Budget: period=%s limit=%s spent=%s remaining=%s asset=%s exceeded=%t\n",
+				p.Period,
+				p.Limit,
+				p.Spent,
+				p.Remaining,
+				d.Budget.Asset,
+				p.Exceeded,
+			)
+		}
+	}
+	for _, note := range d.Notes {
+		fmt.Fprintf(out, "Note: %s\n", note)
+	}
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/config"
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"github.com/promptingcompany/openspend-cli/internal/policytemplate"
+	"github.com/spf13/cobra"
+)
+
+const templateDenyRulePriority = 10
+
+func newPolicyTemplatesCmd() *cobra.Command {
+	templatesCmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Policy templates for policy init",
+		Long: strings.TrimSpace(`
+Policy templates for policy init --template.
+
+Built-in templates ship with the CLI. User templates are read from
+~/.config/openspend/policy-templates (or OPENSPEND_POLICY_TEMPLATES_DIR) and
+replace a built-in template with the same name. A template file looks like:
+
+  apiVersion: openspend.ai/v1
+  kind: PolicyTemplate
+  template:
+    name: team-buyer
+    version: 1
+    description: Team default
+  policy:
+    metadata:
+      name: Team Buyer Policy
+      mode: buy
+    rules:
+      - effect: allow
+        scope: global
+        maxPrice: "250000"
+        priority: 100
+`),
+	}
+	templatesCmd.AddCommand(newPolicyTemplatesListCmd())
+	templatesCmd.AddCommand(newPolicyTemplatesShowCmd())
+	return templatesCmd
+}
+
+func newPolicyTemplatesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List available policy templates",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			dir, err := config.PolicyTemplatesDir()
+			if err != nil {
+				return err
+			}
+			templates, err := policytemplate.List(dir)
+			if err != nil {
+				return err
+			}
+
+			for _, tmpl := range templates {
+				fmt.Fprintf(
+					cmd.OutOrStdout(),
+					"- name=%s version=%d mode=%s source=%s description=%s\n",
+					tmpl.Name,
+					tmpl.Version,
+					tmpl.Policy.Metadata.Mode,
+					tmpl.Source,
+					tmpl.Description,
+				)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Total templates: %d\n", len(templates))
+			return nil
+		},
+	}
+}
+
+func newPolicyTemplatesShowCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Print the policy a template creates",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outFormat, err := resolvePolicyFileFormat(cmd, "", format)
+			if err != nil {
+				return err
+			}
+			dir, err := config.PolicyTemplatesDir()
+			if err != nil {
+				return err
+			}
+			tmpl, err := policytemplate.Find(dir, args[0])
+			if err != nil {
+				return err
+			}
+			return policyfile.Encode(cmd.OutOrStdout(), tmpl.Policy, outFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "yaml", "Output format (yaml|json)")
+	return cmd
+}
+
+type policyTemplateOverrides struct {
+	mode        string
+	name        string
+	description string
+	asset       string
+	network     string
+	maxPrice    string
+	denyHosts   []string
+}
+
+func initPolicyFromTemplate(
+	cmd *cobra.Command,
+	cfg config.Config,
+	templateName string,
+	overrides policyTemplateOverrides,
+) error {
+	dir, err := config.PolicyTemplatesDir()
+	if err != nil {
+		return err
+	}
+	tmpl, err := policytemplate.Find(dir, templateName)
+	if err != nil {
+		return err
+	}
+	doc := tmpl.Policy
+	if err := applyPolicyTemplateOverrides(cfg, &doc, overrides); err != nil {
+		return err
+	}
+	if err := doc.Validate(); err != nil {
+		return err
+	}
+
+	client := clientFromConfig(cfg)
+
+	res, err := client.CreatePolicy(cmd.Context(), doc.ApplyRequest())
+	if err != nil {
+		return err
+	}
+	if err := persistAuthFromClient(&cfg, client); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(
+		cmd.OutOrStdout(),
+		"%s policy created from template %s v%d: %s (%s)\n",
+		policyModeLabel(res.Policy.Mode),
+		tmpl.Name,
+		tmpl.Version,
+		res.Policy.Name,
+		res.Policy.ID,
+	)
+	return nil
+}
+
+// applyPolicyTemplateOverrides applies policy init flags to a template.
+// Asset, network and max price change the first global allow rule.
+func applyPolicyTemplateOverrides(cfg config.Config, doc *policyfile.Document, o policyTemplateOverrides) error {
+	if o.mode != "" {
+		doc.Metadata.Mode = o.mode
+	}
+	if value := strings.TrimSpace(o.name); value != "" {
+		doc.Metadata.Name = value
+	}
+	if value := strings.TrimSpace(o.description); value != "" {
+		doc.Metadata.Description = &value
+	}
+
+	rules := make([]policyfile.Rule, len(doc.Rules))
+	copy(rules, doc.Rules)
+	doc.Rules = rules
+
+	if strings.TrimSpace(o.asset) != "" || strings.TrimSpace(o.network) != "" || strings.TrimSpace(o.maxPrice) != "" {
+		global := -1
+		for i, rule := range doc.Rules {
+			if rule.Effect == "allow" && rule.Scope == "global" && rule.IsEnabled() {
+				global = i
+				break
+			}
+		}
+		if global < 0 {
+			return fmt.Errorf("template has no global allow rule for --asset, --network or --max-price")
+		}
+		rule := &doc.Rules[global]
+		if value := strings.TrimSpace(o.asset); value != "" {
+			rule.Asset = &value
+		}
+		if value := strings.TrimSpace(o.network); value != "" {
+			rule.Network = &value
+		}
+		if strings.TrimSpace(o.maxPrice) != "" {
+			asset := ""
+			if rule.Asset != nil {
+				asset = *rule.Asset
+			}
+			network := ""
+			if rule.Network != nil {
+				network = *rule.Network
+			}
+			baseUnits, resolvedAsset, err := resolveMaxPriceFlag(cfg, o.maxPrice, asset, network)
+			if err != nil {
+				return err
+			}
+			value := fmt.Sprintf("%d", baseUnits)
+			rule.MaxPrice = &value
+			if resolvedAsset != "" {
+				rule.Asset = &resolvedAsset
+			}
+		}
+	}
+
+	for _, host := range o.denyHosts {
+		doc.Rules = append(doc.Rules, policyfile.Rule{
+			Effect:       "deny",
+			Scope:        "host",
+			ResourceHost: &host,
+			Priority:     templateDenyRulePriority,
+		})
+	}
+	return nil
+}
+
+// splitHosts splits a comma-separated host list, dropping blanks and
+// duplicates.
+func splitHosts(raw string) []string {
+	items := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, part := range strings.Split(raw, ",") {
+		host := strings.ToLower(strings.TrimSpace(part))
+		if host == "" {
+			continue
+		}
+		if _, exists := seen[host]; exists {
+			continue
+		}
+		seen[host] = struct{}{}
+		items = append(items, host)
+	}
+	return items
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/config"
+	"github.com/promptingcompany/openspend-cli/internal/policytemplate"
+)
+
+func TestResolveInitMode(t *testing.T) {
+	tests := []struct {
+		buyer   bool
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{buyer: true, want: "buy"},
+		{mode: "SELL", want: "sell"},
+		{mode: "both", want: "both"},
+		{buyer: true, mode: "buy", want: "buy"},
+		{buyer: true, mode: "sell", wantErr: true},
+		{mode: "trade", wantErr: true},
+		{want: ""},
+	}
+	for _, tt := range tests {
+		got, err := resolveInitMode(tt.buyer, tt.mode)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("expected error for buyer=%t mode=%q", tt.buyer, tt.mode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Fatalf("buyer=%t mode=%q: expected %q, got %q", tt.buyer, tt.mode, tt.want, got)
+		}
+	}
+}
+
+func TestApplyPolicyTemplateOverrides(t *testing.T) {
+	tmpl, err := policytemplate.Find("", "strict-buyer")
+	if err != nil {
+		t.Fatalf("find failed: %v", err)
+	}
+	doc := tmpl.Policy
+	err = applyPolicyTemplateOverrides(config.Config{}, &doc, policyTemplateOverrides{
+		name:      "Team",
+		maxPrice:  "0.05USDC",
+		network:   "base",
+		denyHosts: splitHosts("Bad.example.com, bad.example.com,,other.io"),
+	})
+	if err != nil {
+		t.Fatalf("overrides failed: %v", err)
+	}
+
+	if doc.Metadata.Name != "Team" || doc.Metadata.Mode != "buy" {
+		t.Fatalf("unexpected metadata: %+v", doc.Metadata)
+	}
+	global := doc.Rules[0]
+	if *global.MaxPrice != "50000" || *global.Network != "base" || *global.Asset != "USDC" {
+		t.Fatalf("unexpected global rule: max=%s network=%s asset=%s", *global.MaxPrice, *global.Network, *global.Asset)
+	}
+	if len(doc.Rules) != 3 || *doc.Rules[1].ResourceHost != "bad.example.com" || *doc.Rules[2].ResourceHost != "other.io" {
+		t.Fatalf("expected two deny rules, got %+v", doc.Rules)
+	}
+
+	// The template itself is left unchanged.
+	if *tmpl.Policy.Rules[0].MaxPrice != "100000" || len(tmpl.Policy.Rules) != 1 {
+		t.Fatalf("template was modified")
+	}
+}
@@ -36,6 +36,7 @@ func NewRootCmd() *cobra.Command {
 
 	root.AddCommand(newAuthCmd())
 	root.AddCommand(newSearchCmd())
+	root.AddCommand(newCatalogCmd())
 	root.AddCommand(newWhoAmICmd())
 	root.AddCommand(newUpdateCmd())
 	root.AddCommand(newVersionCmd())
@@ -75,6 +76,8 @@ func clientFromConfig(cfg config.Config) *api.Client {
 		PolicyDetailsPath:   cfg.Marketplace.PolicyDetailsPath,
 		AgentPath:           cfg.Marketplace.AgentPath,
 		SearchPath:          cfg.Marketplace.SearchPath,
+		SpendPath:           cfg.Marketplace.SpendPath,
+		AuditPath:           cfg.Marketplace.AuditPath,
 		BrowserAuthPath:     cfg.Auth.BrowserLoginPath,
 		CliAuthStartPath:    cfg.Auth.CliAuthStartPath,
 		CliAuthPollPath:     cfg.Auth.CliAuthPollPath,
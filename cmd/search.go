@@ -6,24 +6,87 @@ import (
 	"strings"
 
 	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/config"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+	"github.com/promptingcompany/openspend-cli/internal/search"
 	"github.com/spf13/cobra"
 )
 
 func newSearchCmd() *cobra.Command {
 	var networks []string
 	var limit int
-	var budgetMax float64
+	var budgetMax string
 	var budgetAsset string
 	var minServiceScore float64
 	var minProviderScore float64
 	var minPaymentScore float64
 	var jsonOut bool
+	var sortKey string
+	var rank bool
+	var explain bool
+	var reportPath string
+	var reportTemplate string
+	var reportPolicyID string
+	var offline bool
+	var catalogFile string
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search marketplace services",
-		Args:  cobra.MinimumNArgs(1),
+		Long: strings.TrimSpace(`
+Search marketplace services.
+
+The query accepts inline qualifiers alongside free text:
+
+  network:base,polygon   network filter (repeatable, comma-separated)
+  price:<0.5             maximum price budget (<, <= or a plain value);
+                         a unit such as price:<5USD is converted into the
+                         budget asset using the [money] FX table
+  asset:USDC             budget asset
+  provider:>=0.8         minimum provider score (>, >= or a plain value)
+  service:>=0.8          minimum service score
+  payment:>=0.8          minimum payment score
+  type:http              result type filter (applied client-side)
+  limit:20               maximum number of results
+
+Explicit flags override the matching inline qualifiers.
+
+Results arrive in server order. Use --sort to order them locally, or --rank
+to combine the server score with price and the preferred networks and assets
+from the [search.rank] section of the config file:
+
+  [search.rank]
+  score_weight = 0.5
+  price_weight = 0.3
+  network_weight = 0.1
+  asset_weight = 0.1
+  preferred_networks = ["base"]
+  preferred_assets = ["USDC"]
+`),
+		Example: strings.TrimSpace(`
+  openspend search "image generation network:base,polygon price:<0.5 asset:USDC"
+  openspend search "speech to text provider:>=0.8 type:http" --limit 5
+  openspend search "ocr" --sort price
+  openspend search "ocr" --rank --explain
+  openspend search "ocr" --rank --report report.md --report-policy <policy-id>
+  openspend search "ocr network:base" --offline
+`),
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if rank && strings.TrimSpace(sortKey) != "" {
+				return fmt.Errorf("use either --sort or --rank")
+			}
+			if explain && !rank {
+				return fmt.Errorf("--explain requires --rank")
+			}
+			if strings.TrimSpace(reportPath) == "" &&
+				(cmd.Flags().Changed("report-template") || cmd.Flags().Changed("report-policy")) {
+				return fmt.Errorf("--report-template and --report-policy require --report")
+			}
+			if cmd.Flags().Changed("catalog-file") && !offline {
+				return fmt.Errorf("--catalog-file requires --offline")
+			}
+
 			cfg := mustLoadConfig()
 			client := clientFromConfig(cfg)
 
@@ -32,29 +95,105 @@ func newSearchCmd() *cobra.Command {
 				return fmt.Errorf("query is required")
 			}
 
-			req := api.SearchRequest{
-				Query:            query,
-				Networks:         networks,
-				Limit:            limit,
-				BudgetAsset:      strings.TrimSpace(budgetAsset),
-				MinServiceScore:  optionalFloat(minServiceScore),
-				MinProviderScore: optionalFloat(minProviderScore),
-				MinPaymentScore:  optionalFloat(minPaymentScore),
+			parsed, err := search.ParseQuery(query)
+			if err != nil {
+				return err
 			}
-			if budgetMax > 0 {
-				req.BudgetMax = &budgetMax
+			req := parsed.Request
+			if req.Limit == 0 || cmd.Flags().Changed("limit") {
+				req.Limit = limit
 			}
-
-			res, err := client.Search(cmd.Context(), req)
+			// Explicit flags override inline qualifiers.
+			if cmd.Flags().Changed("network") {
+				req.Networks = networks
+			}
+			budgetUnit := parsed.BudgetUnit
+			if cmd.Flags().Changed("budget-max") {
+				amount, err := money.ParseAmount(budgetMax)
+				if err != nil {
+					return fmt.Errorf("--budget-max: %w", err)
+				}
+				req.BudgetMax = optionalFloat(amount.Float())
+				budgetUnit = amount.Unit
+			}
+			if cmd.Flags().Changed("budget-asset") {
+				req.BudgetAsset = strings.TrimSpace(budgetAsset)
+			}
+			if cmd.Flags().Changed("min-service-score") {
+				req.MinServiceScore = optionalFloat(minServiceScore)
+			}
+			if cmd.Flags().Changed("min-provider-score") {
+				req.MinProviderScore = optionalFloat(minProviderScore)
+			}
+			if cmd.Flags().Changed("min-payment-score") {
+				req.MinPaymentScore = optionalFloat(minPaymentScore)
+			}
+			fx, err := fxTableFromConfig(cfg)
 			if err != nil {
 				return err
 			}
-			if err := persistAuthFromClient(&cfg, client); err != nil {
+			if err := resolveSearchBudget(&req, budgetUnit, fx); err != nil {
 				return err
 			}
 
+			var res api.SearchResponse
+			if offline {
+				res, err = searchOffline(catalogFile, req)
+				if err != nil {
+					return err
+				}
+			} else {
+				res, err = client.Search(cmd.Context(), req)
+				if err != nil {
+					return err
+				}
+				if err := persistAuthFromClient(&cfg, client); err != nil {
+					return err
+				}
+			}
+			res.Items = search.FilterTypes(res.Items, parsed.Types)
+
+			var ranked []search.RankedItem
+			switch {
+			case rank:
+				ranked = search.Rank(res.Items, rankOptionsFromConfig(cfg, req, fx))
+				res.Items = make([]api.SearchResultItem, 0, len(ranked))
+				for _, r := range ranked {
+					res.Items = append(res.Items, r.Item)
+				}
+			case strings.TrimSpace(sortKey) != "":
+				res.Items, err = search.SortItems(res.Items, sortKey, fx)
+				if err != nil {
+					return err
+				}
+			}
+
+			if strings.TrimSpace(reportPath) != "" {
+				opts := searchReportOptions{path: reportPath, templatePath: reportTemplate}
+				if strings.TrimSpace(reportPolicyID) != "" {
+					policy, err := client.GetPolicyDetails(cmd.Context(), reportPolicyID)
+					if err != nil {
+						return err
+					}
+					if err := persistAuthFromClient(&cfg, client); err != nil {
+						return err
+					}
+					opts.policy = &policy
+				}
+				filters := searchReportFilters(req, parsed.Types, sortKey, rank)
+				if err := writeSearchReport(opts, req, filters, res.Items, ranked); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Report written to %s\n", reportPath)
+			}
+
 			if jsonOut {
-				payload, err := json.MarshalIndent(res, "", "  ")
+				var payload []byte
+				if explain {
+					payload, err = json.MarshalIndent(ranked, "", "  ")
+				} else {
+					payload, err = json.MarshalIndent(res, "", "  ")
+				}
 				if err != nil {
 					return err
 				}
@@ -72,10 +211,9 @@ func newSearchCmd() *cobra.Command {
 				)
 				fmt.Fprintf(
 					cmd.OutOrStdout(),
-					"   score=%.3f min_price=%v %s networks=%s\n",
+					"   score=%.3f min_price=%s networks=%s\n",
 					item.Score,
-					item.MinPrice,
-					item.Asset,
+					formatItemPrice(item),
 					strings.Join(item.Networks, ","),
 				)
 				if strings.TrimSpace(item.Description) != "" {
@@ -84,6 +222,18 @@ func newSearchCmd() *cobra.Command {
 				if strings.TrimSpace(item.Origin.URL) != "" {
 					fmt.Fprintf(cmd.OutOrStdout(), "   origin=%s\n", item.Origin.URL)
 				}
+				if explain {
+					b := ranked[i].Breakdown
+					fmt.Fprintf(
+						cmd.OutOrStdout(),
+						"   rank=%.3f (score=%.3f price=%.3f network=%.3f asset=%.3f)\n",
+						b.Total,
+						b.Score,
+						b.Price,
+						b.Network,
+						b.Asset,
+					)
+				}
 			}
 
 			return nil
@@ -92,16 +242,60 @@ func newSearchCmd() *cobra.Command {
 
 	cmd.Flags().StringSliceVar(&networks, "network", nil, "Network filter (repeatable)")
 	cmd.Flags().IntVar(&limit, "limit", 9, "Maximum number of results")
-	cmd.Flags().Float64Var(&budgetMax, "budget-max", 0, "Optional maximum price budget filter")
+	cmd.Flags().StringVar(
+		&budgetMax,
+		"budget-max",
+		"",
+		"Optional maximum price budget filter (for example 0.5, or 5USD converted into --budget-asset via the FX table)",
+	)
 	cmd.Flags().StringVar(&budgetAsset, "budget-asset", "", "Optional budget asset filter (for example USDC)")
 	cmd.Flags().Float64Var(&minServiceScore, "min-service-score", 0, "Optional minimum service score filter")
 	cmd.Flags().Float64Var(&minProviderScore, "min-provider-score", 0, "Optional minimum provider score filter")
 	cmd.Flags().Float64Var(&minPaymentScore, "min-payment-score", 0, "Optional minimum payment score filter")
 	cmd.Flags().BoolVar(&jsonOut, "json", false, "Print raw JSON response")
+	cmd.Flags().StringVar(
+		&sortKey,
+		"sort",
+		"",
+		"Sort results locally ("+strings.Join(search.SortKeys, "|")+"); a leading - reverses the order",
+	)
+	cmd.Flags().BoolVar(&rank, "rank", false, "Re-rank results by score, price and preferred networks/assets ([search.rank] config)")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Print the per-item rank breakdown (requires --rank)")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Write a procurement report (.md or .html)")
+	cmd.Flags().StringVar(&reportTemplate, "report-template", "", "Template file overriding the built-in report template")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Search a local catalog snapshot instead of the marketplace (see catalog sync)")
+	cmd.Flags().StringVar(&catalogFile, "catalog-file", "", "Catalog snapshot file for --offline (default ~/.config/openspend/catalog.db)")
+	cmd.Flags().StringVar(&reportPolicyID, "report-policy", "", "Policy ID used for the report's policy-compliance column")
 
 	return cmd
 }
 
+// rankOptionsFromConfig builds rank options from the [search.rank] config
+// section. Without configured preferences, the request's network and budget
+// asset filters act as the preferences. Prices in different assets are
+// compared through fx.
+func rankOptionsFromConfig(cfg config.Config, req api.SearchRequest, fx money.FXTable) search.RankOptions {
+	rankCfg := cfg.Search.Rank
+	opts := search.RankOptions{
+		Weights: search.Weights{
+			Score:   rankCfg.ScoreWeight,
+			Price:   rankCfg.PriceWeight,
+			Network: rankCfg.NetworkWeight,
+			Asset:   rankCfg.AssetWeight,
+		},
+		PreferredNetworks: rankCfg.PreferredNetworks,
+		PreferredAssets:   rankCfg.PreferredAssets,
+		FX:                fx,
+	}
+	if len(opts.PreferredNetworks) == 0 {
+		opts.PreferredNetworks = req.Networks
+	}
+	if len(opts.PreferredAssets) == 0 && strings.TrimSpace(req.BudgetAsset) != "" {
+		opts.PreferredAssets = []string{strings.TrimSpace(req.BudgetAsset)}
+	}
+	return opts
+}
+
 func optionalFloat(value float64) *float64 {
 	if value == 0 {
 		return nil
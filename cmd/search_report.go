@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+	"github.com/promptingcompany/openspend-cli/internal/policy"
+	"github.com/promptingcompany/openspend-cli/internal/report"
+	"github.com/promptingcompany/openspend-cli/internal/search"
+)
+
+type searchReportOptions struct {
+	path         string
+	templatePath string
+	policy       *api.PolicyDetailsResponse
+}
+
+func writeSearchReport(
+	opts searchReportOptions,
+	req api.SearchRequest,
+	filters []report.Filter,
+	items []api.SearchResultItem,
+	ranked []search.RankedItem,
+) error {
+	format, err := report.FormatFromPath(opts.path)
+	if err != nil {
+		return err
+	}
+
+	data := report.Data{
+		Query:       req.Query,
+		Filters:     filters,
+		GeneratedAt: time.Now().UTC(),
+		Rows:        make([]report.Row, 0, len(items)),
+	}
+	if opts.policy != nil {
+		data.Policy = &report.PolicyRef{ID: opts.policy.Policy.ID, Name: opts.policy.Policy.Name}
+	}
+	for i, item := range items {
+		row := report.Row{Rank: i + 1, Item: item}
+		if i < len(ranked) {
+			total := ranked[i].Breakdown.Total
+			row.RankScore = &total
+		}
+		if opts.policy != nil {
+			compliance := checkPolicyCompliance(item, *opts.policy)
+			row.Compliance = &compliance
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	// Render fully before touching the target so a failed render leaves an
+	// existing report in place.
+	var buf bytes.Buffer
+	if err := report.Render(&buf, format, opts.templatePath, data); err != nil {
+		return err
+	}
+	return os.WriteFile(opts.path, buf.Bytes(), 0o644)
+}
+
+func searchReportFilters(req api.SearchRequest, types []string, sortKey string, rank bool) []report.Filter {
+	filters := make([]report.Filter, 0)
+	add := func(name, value string) {
+		if strings.TrimSpace(value) != "" {
+			filters = append(filters, report.Filter{Name: name, Value: value})
+		}
+	}
+	formatOptional := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+
+	add("networks", strings.Join(req.Networks, ", "))
+	add("budget_max", formatOptional(req.BudgetMax))
+	add("budget_asset", req.BudgetAsset)
+	add("min_service_score", formatOptional(req.MinServiceScore))
+	add("min_provider_score", formatOptional(req.MinProviderScore))
+	add("min_payment_score", formatOptional(req.MinPaymentScore))
+	add("types", strings.Join(types, ", "))
+	if req.Limit > 0 {
+		add("limit", strconv.Itoa(req.Limit))
+	}
+	add("sort", sortKey)
+	if rank {
+		add("rank", "weighted")
+	}
+	return filters
+}
+
+// checkPolicyCompliance evaluates a search result against the policy rules
+// once per advertised network; the result complies when any network is
+// allowed. Reports assess services rather than agents, so the buyer is
+// treated as identified. Search scores (0-1) are scaled to the policy's
+// 0-100 range.
+func checkPolicyCompliance(item api.SearchResultItem, details api.PolicyDetailsResponse) report.Compliance {
+	networks := item.Networks
+	if len(networks) == 0 {
+		networks = []string{""}
+	}
+	score := item.Score * 100
+
+	reasons := make([]string, 0)
+	for _, network := range networks {
+		candidate := policy.Candidate{
+			ResourceURL: item.ResourceURL,
+			Asset:       item.Asset,
+			Network:     network,
+			Score:       &score,
+			Identified:  true,
+		}
+		if amount, err := money.ParseAmount(money.FormatFloat(item.MinPrice)); err == nil {
+			if base, err := money.CeilToBaseUnits(amount.Value, item.Asset, network); err == nil {
+				candidate.Price = base
+			}
+		}
+		decision := policy.Evaluate(details, candidate)
+		if decision.Allowed {
+			return report.Compliance{Allowed: true}
+		}
+		reason := decision.Reason
+		if network != "" && len(networks) > 1 {
+			reason = network + ": " + reason
+		}
+		reasons = append(reasons, reason)
+	}
+	return report.Compliance{Allowed: false, Reasons: reasons}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func TestWriteSearchReport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.md")
+	if err := os.WriteFile(path, []byte("previous report"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	badTemplate := filepath.Join(dir, "bad.tmpl")
+	if err := os.WriteFile(badTemplate, []byte("{{.Missing"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := api.SearchRequest{Query: "ocr"}
+	items := []api.SearchResultItem{{ID: "ocr", ResourceURL: "https://docs.example/ocr", MinPrice: 0.01, Asset: "USDC"}}
+
+	err := writeSearchReport(searchReportOptions{path: path, templatePath: badTemplate}, req, nil, items, nil)
+	if err == nil {
+		t.Fatalf("expected a template error")
+	}
+	got, err := os.ReadFile(path)
+	if err != nil || string(got) != "previous report" {
+		t.Fatalf("expected the existing report to be kept, got %q (%v)", got, err)
+	}
+
+	if err := writeSearchReport(searchReportOptions{path: path}, req, nil, items, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err = os.ReadFile(path)
+	if err != nil || !strings.Contains(string(got), "https://docs.example/ocr") {
+		t.Fatalf("expected the report to be rewritten, got %q (%v)", got, err)
+	}
+}
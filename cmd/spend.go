@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/spend"
+	"github.com/spf13/cobra"
+)
+
+const (
+	spendOutputTable     = "table"
+	spendOutputSparkline = "sparkline"
+	spendOutputJSON      = "json"
+)
+
+func newSpendCmd() *cobra.Command {
+	var (
+		sinceRaw string
+		untilRaw string
+		groupBy  string
+		interval string
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "spend",
+		Short: "Summarise agent spend over a time window",
+		Long: strings.TrimSpace(`
+Summarise allowed spend by agent, policy, provider host, network or asset over
+a time window. Amounts are totalled per asset, and agents with a budget show
+how much of it is used.
+
+--output table (the default) prints one row per group, sparkline prints the
+trend per --interval for each group, and json prints the full report.
+--since and --until take a duration back from now (24h, 7d) or an RFC 3339
+time.
+`),
+		Example: strings.TrimSpace(`
+  openspend dashboard spend --since 30d
+  openspend dashboard spend --group-by host --since 7d --output sparkline
+  openspend dashboard spend --group-by policy --since 2026-09-01T00:00:00Z --until 2026-10-01T00:00:00Z --output json
+`),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			now := time.Now()
+			since, err := parseSince(sinceRaw, now)
+			if err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+			until, err := parseSince(untilRaw, now)
+			if err != nil {
+				return fmt.Errorf("--until: %w", err)
+			}
+			if !since.IsZero() && !until.IsZero() && !until.After(since) {
+				return fmt.Errorf("--until must be after --since")
+			}
+			req := api.SpendRequest{Since: since, Until: until}
+			if req.GroupBy, err = spend.ParseGroupBy(groupBy); err != nil {
+				return err
+			}
+			if req.Interval, err = spend.ParseInterval(interval); err != nil {
+				return err
+			}
+			output = strings.ToLower(strings.TrimSpace(output))
+			switch output {
+			case spendOutputTable, spendOutputSparkline, spendOutputJSON:
+			default:
+				return fmt.Errorf("--output must be one of: table, sparkline, json")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			res, err := client.GetSpend(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			report := spend.Build(res)
+			if report.GroupBy == "" {
+				report.GroupBy = req.GroupBy
+			}
+			if report.Interval == "" {
+				report.Interval = req.Interval
+			}
+			switch output {
+			case spendOutputJSON:
+				payload, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(payload))
+			case spendOutputSparkline:
+				printSpendSparklines(cmd.OutOrStdout(), report)
+			default:
+				printSpendTable(cmd.OutOrStdout(), report)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sinceRaw, "since", "30d", "Start of the window: duration ago (24h, 30d) or RFC 3339 time")
+	cmd.Flags().StringVar(&untilRaw, "until", "", "End of the window: duration ago or RFC 3339 time; empty for now")
+	cmd.Flags().StringVar(&groupBy, "group-by", "agent", "Group spend by agent, policy, host, network or asset")
+	cmd.Flags().StringVar(&interval, "interval", "day", "Sparkline bucket size (hour|day|week)")
+	cmd.Flags().StringVar(&output, "output", spendOutputTable, "Output format (table|sparkline|json)")
+	return cmd
+}
+
+func printSpendHeader(out io.Writer, report spend.Report) {
+	window := "all time"
+	switch {
+	case report.Since != "" && report.Until != "":
+		window = report.Since + " to " + report.Until
+	case report.Since != "":
+		window = "since " + report.Since
+	}
+	fmt.Fprintf(out, "Spend by %s, %s\n", report.GroupBy, window)
+}
+
+func printSpendTable(out io.Writer, report spend.Report) {
+	printSpendHeader(out, report)
+	if len(report.Rows) == 0 {
+		fmt.Fprintln(out, "No spend in this window.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tASSET\tTOTAL\tEVENTS\tSHARE\tBUDGET")
+	for _, row := range report.Rows {
+		budget := spend.FormatBudget(row.Budget, row.Asset)
+		if budget == "" {
+			budget = "-"
+		}
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%d\t%.1f%%\t%s\n",
+			spendRowLabel(row),
+			row.Asset,
+			spendRowAmount(row),
+			row.Count,
+			row.Share*100,
+			budget,
+		)
+	}
+	tw.Flush()
+	printSpendTotals(out, report)
+}
+
+func printSpendSparklines(out io.Writer, report spend.Report) {
+	printSpendHeader(out, report)
+	if len(report.Rows) == 0 {
+		fmt.Fprintln(out, "No spend in this window.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, row := range report.Rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s %s\n", spendRowLabel(row), row.Sparkline, spendRowAmount(row), row.Asset)
+	}
+	tw.Flush()
+	fmt.Fprintf(out, "One block per %s, oldest first.\n", report.Interval)
+	printSpendTotals(out, report)
+}
+
+func printSpendTotals(out io.Writer, report spend.Report) {
+	for _, total := range report.Totals {
+		amount := total.Amount
+		if amount == "" {
+			amount = total.Total
+		}
+		fmt.Fprintf(out, "Total: %s %s (%d events)\n", amount, total.Asset, total.Count)
+	}
+}
+
+func spendRowLabel(row spend.Row) string {
+	if row.Label != "" && row.Label != row.Key {
+		return fmt.Sprintf("%s (%s)", row.Label, row.Key)
+	}
+	return row.Key
+}
+
+// spendRowAmount falls back to base units when the asset's decimals are
+// unknown.
+func spendRowAmount(row spend.Row) string {
+	if row.Amount != "" {
+		return row.Amount
+	}
+	return row.Total
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/spend"
+)
+
+func TestPrintSpend(t *testing.T) {
+	label := "Buyer Agent"
+	limit, spent := "2000000", "500000"
+	report := spend.Build(api.SpendResponse{
+		Since:    "2026-09-18T00:00:00Z",
+		Until:    "2026-10-18T00:00:00Z",
+		GroupBy:  "agent",
+		Interval: "day",
+		Groups: []api.SpendGroup{
+			{
+				Key:    "buyer-agent-1",
+				Label:  &label,
+				Asset:  "USDC",
+				Total:  "500000",
+				Count:  2,
+				Series: []string{"0", "500000"},
+				Budget: &api.AgentBudget{Asset: "USDC", MonthlyLimit: &limit, MonthlySpent: &spent},
+			},
+		},
+	})
+
+	var table bytes.Buffer
+	printSpendTable(&table, report)
+	for _, want := range []string{
+		"Spend by agent, 2026-09-18T00:00:00Z to 2026-10-18T00:00:00Z",
+		"GROUP                        ASSET  TOTAL  EVENTS  SHARE   BUDGET",
+		"Buyer Agent (buyer-agent-1)  USDC   0.5    2       100.0%  25% of 2 USDC monthly",
+		"Total: 0.5 USDC (2 events)",
+	} {
+		if !strings.Contains(table.String(), want) {
+			t.Fatalf("expected table to contain %q, got:\n%s", want, table.String())
+		}
+	}
+
+	var lines bytes.Buffer
+	printSpendSparklines(&lines, report)
+	if !strings.Contains(lines.String(), "Buyer Agent (buyer-agent-1)  ▁█  0.5 USDC") {
+		t.Fatalf("unexpected sparklines:\n%s", lines.String())
+	}
+
+	var empty bytes.Buffer
+	printSpendTable(&empty, spend.Build(api.SpendResponse{GroupBy: "host"}))
+	if empty.String() != "Spend by host, all time\nNo spend in this window.\n" {
+		t.Fatalf("unexpected empty output: %q", empty.String())
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+func newSubjectCmd() *cobra.Command {
+	subjectCmd := &cobra.Command{
+		Use:   "subject",
+		Short: "Subject policy resolution commands",
+	}
+	subjectCmd.AddCommand(newSubjectPoliciesCmd())
+	return subjectCmd
+}
+
+func newSubjectPoliciesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "policies <subject-key>",
+		Short: "Show which policy applies to a subject and why",
+		Long: strings.TrimSpace(`
+Show the policies a subject is bound to in resolution order.
+
+The active binding with the lowest precedence on a policy that is not
+archived decides. Inactive bindings and archived policies are listed but
+skipped. Equal precedences are ordered by policy ID and flagged, since the
+server may break the tie differently.
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subjectKey := strings.TrimSpace(args[0])
+			if subjectKey == "" {
+				return fmt.Errorf("subject key is required")
+			}
+
+			cfg := mustLoadConfig()
+			client := clientFromConfig(cfg)
+
+			subject, bindings, err := subjectPolicyBindings(cmd.Context(), client, subjectKey)
+			if err != nil {
+				return err
+			}
+			if err := persistAuthFromClient(&cfg, client); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Subject: id=%s key=%s kind=%s status=%s\n",
+				subject.id,
+				subject.key,
+				subject.kind,
+				subject.status,
+			)
+			printSubjectResolution(cmd.OutOrStdout(), policy.ResolveBindings(bindings))
+			return nil
+		},
+	}
+}
+
+type subjectRef struct {
+	id     string
+	key    string
+	kind   string
+	status string
+}
+
+// subjectPolicyBindings finds the policies bound to subjectKey (an external
+// key or subject ID) from whoami, then reads each policy for the binding's
+// precedence, active flag and the policy status.
+func subjectPolicyBindings(
+	ctx context.Context,
+	client *api.Client,
+	subjectKey string,
+) (subjectRef, []policy.Binding, error) {
+	res, err := client.WhoAmI(ctx)
+	if err != nil {
+		return subjectRef{}, nil, err
+	}
+
+	var subject subjectRef
+	var policyIDs []string
+	precedences := make(map[string]int)
+	for _, s := range res.Subjects {
+		key := ""
+		if s.ExternalKey != nil {
+			key = strings.TrimSpace(*s.ExternalKey)
+		}
+		if s.ID != subjectKey && key != subjectKey {
+			continue
+		}
+		subject = subjectRef{id: s.ID, key: key, kind: s.Kind, status: s.Status}
+		if s.PolicyID == nil || strings.TrimSpace(*s.PolicyID) == "" {
+			continue
+		}
+		id := strings.TrimSpace(*s.PolicyID)
+		if _, ok := precedences[id]; !ok {
+			policyIDs = append(policyIDs, id)
+		}
+		precedences[id] = 0
+		if s.Precedence != nil {
+			precedences[id] = *s.Precedence
+		}
+	}
+	if subject.id == "" {
+		return subjectRef{}, nil, fmt.Errorf("subject %q not found", subjectKey)
+	}
+
+	bindings := make([]policy.Binding, 0, len(policyIDs))
+	for _, id := range policyIDs {
+		details, err := client.GetPolicyDetails(ctx, id)
+		if err != nil {
+			return subjectRef{}, nil, err
+		}
+		// Fall back to what whoami reported when the policy details omit
+		// the subject.
+		binding, ok := findPolicyBinding(details.SubjectBindings, subject.id)
+		if !ok {
+			binding = api.PolicySubjectBinding{Precedence: precedences[id], Active: true}
+		}
+		bindings = append(bindings, policy.Binding{
+			PolicyID:     details.Policy.ID,
+			PolicyName:   details.Policy.Name,
+			PolicyStatus: details.Policy.Status,
+			Precedence:   binding.Precedence,
+			Active:       binding.Active,
+		})
+	}
+	return subject, bindings, nil
+}
+
+func printSubjectResolution(out io.Writer, res policy.Resolution) {
+	if len(res.Order) == 0 {
+		fmt.Fprintln(out, "No policy bindings.")
+		return
+	}
+
+	fmt.Fprintf(out, "Resolution order: %d binding(s)\n", len(res.Order))
+	for i, b := range res.Order {
+		marker := ""
+		if i == res.Winner {
+			marker = " (effective)"
+		}
+		fmt.Fprintf(
+			out,
+			"%d. policy_id=%s name=%s status=%s precedence=%d active=%t%s\n",
+			i+1,
+			b.PolicyID,
+			b.PolicyName,
+			b.PolicyStatus,
+			b.Precedence,
+			b.Active,
+			marker,
+		)
+		fmt.Fprintf(out, "   %s\n", b.Reason)
+	}
+
+	winner, ok := res.WinningBinding()
+	if !ok {
+		fmt.Fprintln(out, "Effective policy: none (no active binding on a live policy)")
+		return
+	}
+	fmt.Fprintf(out, "Effective policy: %s (%s)\n", winner.PolicyID, winner.PolicyName)
+	if res.Tied() {
+		fmt.Fprintf(
+			out,
+			"Warning: several bindings share precedence %d; give one a lower precedence to make the winner explicit.\n",
+			winner.Precedence,
+		)
+	}
+}
@@ -0,0 +1,199 @@
+// Package agentfile reads agent manifests (YAML, JSON or CSV) and plans how
+// to reconcile them with the agent subjects that exist on the server.
+package agentfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Kind        = "AgentList"
+	DefaultKind = "agent"
+)
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+// Manifest is the declarative list of agents for an environment.
+type Manifest struct {
+	APIVersion string  `yaml:"apiVersion" json:"apiVersion"`
+	Kind       string  `yaml:"kind" json:"kind"`
+	Agents     []Agent `yaml:"agents" json:"agents"`
+}
+
+// Agent is one manifest row. Empty DisplayName and PolicyID leave the server
+// values untouched; an empty Kind means agent.
+type Agent struct {
+	ExternalKey string `yaml:"externalKey" json:"externalKey"`
+	DisplayName string `yaml:"displayName,omitempty" json:"displayName,omitempty"`
+	Kind        string `yaml:"kind,omitempty" json:"kind,omitempty"`
+	PolicyID    string `yaml:"policyId,omitempty" json:"policyId,omitempty"`
+}
+
+// EffectiveKind returns the agent kind, defaulting to agent.
+func (a Agent) EffectiveKind() string {
+	if kind := strings.TrimSpace(a.Kind); kind != "" {
+		return kind
+	}
+	return DefaultKind
+}
+
+// csvColumns maps accepted CSV header names to manifest fields.
+var csvColumns = map[string]string{
+	"externalkey":  "externalKey",
+	"external_key": "externalKey",
+	"key":          "externalKey",
+	"displayname":  "displayName",
+	"display_name": "displayName",
+	"name":         "displayName",
+	"kind":         "kind",
+	"policyid":     "policyId",
+	"policy_id":    "policyId",
+}
+
+// Validate checks for rows the server would reject or that conflict.
+func (m Manifest) Validate() error {
+	if m.APIVersion != "" && m.APIVersion != policyfile.APIVersion {
+		return fmt.Errorf("unsupported apiVersion %q (expected %s)", m.APIVersion, policyfile.APIVersion)
+	}
+	if m.Kind != "" && m.Kind != Kind {
+		return fmt.Errorf("unsupported kind %q (expected %s)", m.Kind, Kind)
+	}
+	keys := make(map[string]int, len(m.Agents))
+	for i, agent := range m.Agents {
+		key := strings.TrimSpace(agent.ExternalKey)
+		if key == "" {
+			return fmt.Errorf("agents[%d].externalKey is required", i)
+		}
+		if first, dup := keys[key]; dup {
+			return fmt.Errorf("agents[%d].externalKey %q duplicates agents[%d]", i, key, first)
+		}
+		keys[key] = i
+	}
+	return nil
+}
+
+// FormatFromPath picks the file format from its extension (YAML by default).
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".csv":
+		return FormatCSV
+	default:
+		return FormatYAML
+	}
+}
+
+// Load reads and validates an agent manifest.
+func Load(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	m, err := Decode(data, FormatFromPath(path))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Decode parses and validates a manifest. Values are trimmed.
+func Decode(data []byte, format Format) (Manifest, error) {
+	var m Manifest
+	switch format {
+	case FormatCSV:
+		agents, err := decodeCSV(data)
+		if err != nil {
+			return Manifest{}, err
+		}
+		m.Agents = agents
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&m); err != nil {
+			return Manifest{}, err
+		}
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+			return Manifest{}, err
+		}
+	}
+	for i := range m.Agents {
+		a := &m.Agents[i]
+		a.ExternalKey = strings.TrimSpace(a.ExternalKey)
+		a.DisplayName = strings.TrimSpace(a.DisplayName)
+		a.Kind = strings.TrimSpace(a.Kind)
+		a.PolicyID = strings.TrimSpace(a.PolicyID)
+	}
+	if err := m.Validate(); err != nil {
+		return Manifest{}, err
+	}
+	return m, nil
+}
+
+// decodeCSV reads rows under a header line naming the columns, for example
+// external_key,display_name,kind,policy_id.
+func decodeCSV(data []byte) ([]Agent, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	fields := make([]string, len(records[0]))
+	hasKey := false
+	for i, name := range records[0] {
+		field, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column %q (expected external_key, display_name, kind, policy_id)", name)
+		}
+		fields[i] = field
+		hasKey = hasKey || field == "externalKey"
+	}
+	if !hasKey {
+		return nil, errors.New("CSV header must include an external_key column")
+	}
+
+	agents := make([]Agent, 0, len(records)-1)
+	for _, record := range records[1:] {
+		var a Agent
+		for i, value := range record {
+			switch fields[i] {
+			case "externalKey":
+				a.ExternalKey = value
+			case "displayName":
+				a.DisplayName = value
+			case "kind":
+				a.Kind = value
+			case "policyId":
+				a.PolicyID = value
+			}
+		}
+		agents = append(agents, a)
+	}
+	return agents, nil
+}
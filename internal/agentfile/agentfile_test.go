@@ -0,0 +1,154 @@
+package agentfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	want := []Agent{
+		{ExternalKey: "buyer-1", DisplayName: "Buyer One", PolicyID: "pol_1"},
+		{ExternalKey: "buyer-2", Kind: "agent"},
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		format Format
+	}{
+		{
+			name:   "yaml",
+			format: FormatYAML,
+			data: `apiVersion: openspend.ai/v1
+kind: AgentList
+agents:
+  - externalKey: buyer-1
+    displayName: Buyer One
+    policyId: pol_1
+  - externalKey: " buyer-2 "
+    kind: agent
+`,
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			data:   `{"agents":[{"externalKey":"buyer-1","displayName":"Buyer One","policyId":"pol_1"},{"externalKey":"buyer-2","kind":"agent"}]}`,
+		},
+		{
+			name:   "csv",
+			format: FormatCSV,
+			data: `# environment: staging
+external_key,display_name,kind,policy_id
+buyer-1,Buyer One,,pol_1
+buyer-2,,agent,
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Decode([]byte(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(m.Agents, want) {
+				t.Fatalf("unexpected agents: %+v", m.Agents)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		data   string
+		format Format
+		want   string
+	}{
+		{data: "agents:\n  - displayName: x\n", format: FormatYAML, want: "agents[0].externalKey is required"},
+		{data: "agents:\n  - externalKey: a\n  - externalKey: a\n", format: FormatYAML, want: "duplicates agents[0]"},
+		{data: "kind: Policy\n", format: FormatYAML, want: "unsupported kind"},
+		{data: "agents:\n  - externalKey: a\n    policy: p\n", format: FormatYAML, want: "field policy not found"},
+		{data: "key,owner\na,b\n", format: FormatCSV, want: "unknown CSV column"},
+		{data: "name\nBuyer\n", format: FormatCSV, want: "external_key column"},
+		{data: "key,name\na\n", format: FormatCSV, want: "wrong number of fields"},
+	}
+	for _, tt := range tests {
+		_, err := Decode([]byte(tt.data), tt.format)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"agents.yaml": FormatYAML,
+		"agents.yml":  FormatYAML,
+		"agents.JSON": FormatJSON,
+		"agents.csv":  FormatCSV,
+		"agents":      FormatYAML,
+	}
+	for path, want := range tests {
+		if got := FormatFromPath(path); got != want {
+			t.Fatalf("%s: expected %s, got %s", path, want, got)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	m := Manifest{Agents: []Agent{
+		{ExternalKey: "new"},
+		{ExternalKey: "same", DisplayName: "Same", PolicyID: "pol_1"},
+		{ExternalKey: "renamed", DisplayName: "Renamed v2"},
+		{ExternalKey: "rebound", PolicyID: "pol_2"},
+		{ExternalKey: "keep-name"},
+	}}
+	existing := []Existing{
+		{ID: "s1", ExternalKey: "same", DisplayName: "Same", Kind: "agent", PolicyIDs: []string{"pol_9", "pol_1"}},
+		{ID: "s2", ExternalKey: "renamed", DisplayName: "Renamed", Kind: "agent"},
+		{ID: "s3", ExternalKey: "rebound", Kind: "agent", PolicyIDs: []string{"pol_1"}},
+		{ID: "s4", ExternalKey: "keep-name", DisplayName: "Server Name", Kind: "agent"},
+		{ID: "s5", ExternalKey: "stale-b", Kind: "agent"},
+		{ID: "s6", ExternalKey: "stale-a", Kind: "agent"},
+		{ID: "s7", ExternalKey: "anon", Kind: "anonymous_agent"},
+		{ID: "s8", Kind: "agent"},
+	}
+
+	type row struct {
+		key    string
+		action Action
+		fields string
+	}
+	summarize := func(changes []Change) []row {
+		rows := make([]row, 0, len(changes))
+		for _, c := range changes {
+			rows = append(rows, row{key: c.Key(), action: c.Action, fields: strings.Join(c.Fields, ",")})
+		}
+		return rows
+	}
+
+	base := []row{
+		{key: "new", action: ActionCreate},
+		{key: "same", action: ActionUnchanged},
+		{key: "renamed", action: ActionUpdate, fields: "displayName"},
+		{key: "rebound", action: ActionUpdate, fields: "policyId"},
+		{key: "keep-name", action: ActionUnchanged},
+	}
+	if got := summarize(Plan(m, existing, false)); !reflect.DeepEqual(got, base) {
+		t.Fatalf("unexpected plan without prune: %+v", got)
+	}
+
+	withPrune := append(append([]row(nil), base...),
+		row{key: "stale-a", action: ActionPrune},
+		row{key: "stale-b", action: ActionPrune},
+	)
+	changes := Plan(m, existing, true)
+	if got := summarize(changes); !reflect.DeepEqual(got, withPrune) {
+		t.Fatalf("unexpected plan with prune: %+v", got)
+	}
+
+	counts := Counts(changes)
+	if counts[ActionCreate] != 1 || counts[ActionUpdate] != 2 || counts[ActionUnchanged] != 2 || counts[ActionPrune] != 2 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+}
@@ -0,0 +1,121 @@
+package agentfile
+
+import (
+	"sort"
+	"strings"
+)
+
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionUnchanged Action = "unchanged"
+	ActionPrune     Action = "prune"
+)
+
+// Existing is an agent subject as reported by the server. PolicyIDs lists
+// every policy the subject is bound to.
+type Existing struct {
+	ID          string
+	ExternalKey string
+	DisplayName string
+	Kind        string
+	Status      string
+	PolicyIDs   []string
+}
+
+// Change is one planned step. Fields names what an update changes.
+type Change struct {
+	Action   Action
+	Agent    Agent
+	Existing *Existing
+	Fields   []string
+}
+
+// Key returns the external key the change applies to.
+func (c Change) Key() string {
+	if c.Existing != nil && c.Action == ActionPrune {
+		return c.Existing.ExternalKey
+	}
+	return c.Agent.ExternalKey
+}
+
+// Plan reconciles the manifest with existing subjects, matched by external
+// key. Changes follow manifest order. With prune, existing subjects of kind
+// agent that are missing from the manifest are pruned, sorted by key;
+// anonymous agents and subjects without an external key are never pruned.
+func Plan(m Manifest, existing []Existing, prune bool) []Change {
+	byKey := make(map[string]*Existing, len(existing))
+	for i := range existing {
+		key := strings.TrimSpace(existing[i].ExternalKey)
+		if key == "" {
+			continue
+		}
+		byKey[key] = &existing[i]
+	}
+
+	changes := make([]Change, 0, len(m.Agents))
+	inManifest := make(map[string]bool, len(m.Agents))
+	for _, agent := range m.Agents {
+		inManifest[agent.ExternalKey] = true
+		current, ok := byKey[agent.ExternalKey]
+		if !ok {
+			changes = append(changes, Change{Action: ActionCreate, Agent: agent})
+			continue
+		}
+		fields := changedFields(agent, *current)
+		action := ActionUpdate
+		if len(fields) == 0 {
+			action = ActionUnchanged
+		}
+		changes = append(changes, Change{Action: action, Agent: agent, Existing: current, Fields: fields})
+	}
+
+	if !prune {
+		return changes
+	}
+	pruned := make([]Change, 0)
+	for key, current := range byKey {
+		if inManifest[key] || current.Kind != DefaultKind {
+			continue
+		}
+		pruned = append(pruned, Change{Action: ActionPrune, Existing: current})
+	}
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].Existing.ExternalKey < pruned[j].Existing.ExternalKey
+	})
+	return append(changes, pruned...)
+}
+
+// Counts tallies changes by action.
+func Counts(changes []Change) map[Action]int {
+	counts := make(map[Action]int)
+	for _, change := range changes {
+		counts[change.Action]++
+	}
+	return counts
+}
+
+func changedFields(agent Agent, current Existing) []string {
+	var fields []string
+	if agent.DisplayName != "" && agent.DisplayName != current.DisplayName {
+		fields = append(fields, "displayName")
+	}
+	if agent.EffectiveKind() != current.Kind {
+		fields = append(fields, "kind")
+	}
+	if agent.PolicyID != "" && !containsString(current.PolicyIDs, agent.PolicyID) {
+		fields = append(fields, "policyId")
+	}
+	return fields
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+// Package agentkey generates external keys for new agent subjects. Keys come
+// from a ULID, a text/template or a hash of attributes, and are checked
+// against the keys already in use.
+package agentkey
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+type Strategy string
+
+const (
+	StrategyULID     Strategy = "ulid"
+	StrategyTemplate Strategy = "template"
+	StrategyHash     Strategy = "hash"
+)
+
+// Strategies lists the accepted strategy names.
+var Strategies = []Strategy{StrategyULID, StrategyTemplate, StrategyHash}
+
+const (
+	DefaultPrefix = "buyer-agent"
+	// maxSeq bounds the {{.Seq}} search for a free templated key.
+	maxSeq = 100000
+	// hashLength is the number of hex digits kept from the attribute hash.
+	hashLength = 12
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+// ErrTaken is returned when a deterministic key is already in use.
+var ErrTaken = errors.New("key already in use")
+
+// Options selects and configures a strategy. Attrs feed templates (with the
+// first letter of each name upper-cased, so team becomes {{.Team}}) and the
+// hash strategy.
+type Options struct {
+	Strategy Strategy
+	Prefix   string
+	Template string
+	Attrs    map[string]string
+
+	// Now and Rand default to time.Now and crypto/rand.
+	Now  func() time.Time
+	Rand io.Reader
+}
+
+// ParseStrategy accepts ulid, template or hash.
+func ParseStrategy(raw string) (Strategy, error) {
+	s := Strategy(strings.ToLower(strings.TrimSpace(raw)))
+	for _, known := range Strategies {
+		if s == known {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown key strategy %q (expected ulid, template or hash)", raw)
+}
+
+// Generate returns a key that taken reports as unused.
+//
+// ulid keys are <prefix>-<ulid> and are retried on the rare collision.
+// template keys render Template with Seq counting up from 1 until a free key
+// is found; a template without {{.Seq}} must render to a free key. hash keys
+// are <prefix>-<digest of Attrs> and are the same for the same attributes,
+// so a taken hash key returns ErrTaken.
+func Generate(opts Options, taken func(string) bool) (string, error) {
+	if taken == nil {
+		taken = func(string) bool { return false }
+	}
+	prefix := strings.TrimSpace(opts.Prefix)
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	switch opts.Strategy {
+	case "", StrategyULID:
+		now, random := opts.Now, opts.Rand
+		if now == nil {
+			now = time.Now
+		}
+		if random == nil {
+			random = rand.Reader
+		}
+		for attempt := 0; attempt < 5; attempt++ {
+			id, err := NewULID(now(), random)
+			if err != nil {
+				return "", err
+			}
+			key := prefix + "-" + strings.ToLower(id)
+			if !taken(key) {
+				return key, nil
+			}
+		}
+		return "", errors.New("could not generate an unused ulid key")
+
+	case StrategyTemplate:
+		return generateFromTemplate(opts, taken)
+
+	case StrategyHash:
+		if len(opts.Attrs) == 0 {
+			return "", errors.New("the hash strategy needs at least one attribute")
+		}
+		key := prefix + "-" + hashAttrs(opts.Attrs)
+		if taken(key) {
+			return "", fmt.Errorf("%w: %s", ErrTaken, key)
+		}
+		return key, nil
+
+	default:
+		return "", fmt.Errorf("unknown key strategy %q", opts.Strategy)
+	}
+}
+
+func generateFromTemplate(opts Options, taken func(string) bool) (string, error) {
+	if strings.TrimSpace(opts.Template) == "" {
+		return "", errors.New("the template strategy needs a key template")
+	}
+	tmpl, err := template.New("key").Option("missingkey=error").Parse(opts.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid key template: %w", err)
+	}
+
+	data := make(map[string]any, len(opts.Attrs)+1)
+	for name, value := range opts.Attrs {
+		data[exportedName(name)] = value
+	}
+	render := func(seq int) (string, error) {
+		data["Seq"] = seq
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("key template: %w", err)
+		}
+		key := strings.TrimSpace(buf.String())
+		if !keyPattern.MatchString(key) {
+			return "", fmt.Errorf("key template rendered %q, which is not a valid key", key)
+		}
+		return key, nil
+	}
+
+	first, err := render(1)
+	if err != nil {
+		return "", err
+	}
+	if !taken(first) {
+		return first, nil
+	}
+	for seq := 2; seq <= maxSeq; seq++ {
+		key, err := render(seq)
+		if err != nil {
+			return "", err
+		}
+		if key == first {
+			return "", fmt.Errorf("%w: %s (add {{.Seq}} to the template)", ErrTaken, first)
+		}
+		if !taken(key) {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("no free key for the template after %d attempts", maxSeq)
+}
+
+// hashAttrs digests attributes in name order, so the result does not depend
+// on map iteration or flag order.
+func hashAttrs(attrs map[string]string) string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s=%s\n", strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(attrs[name]))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:hashLength]
+}
+
+func exportedName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// crockford is the ULID alphabet.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a 26-character ULID: 48 bits of milliseconds since the
+// Unix epoch followed by 80 random bits, in Crockford base32.
+func NewULID(t time.Time, random io.Reader) (string, error) {
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := io.ReadFull(random, id[6:]); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+
+	// 128 bits become 26 base32 digits; the first digit holds 3 bits.
+	var out [26]byte
+	var acc uint64
+	bits := 0
+	pos := 25
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint64(id[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			out[pos] = crockford[acc&0x1f]
+			pos--
+			acc >>= 5
+			bits -= 5
+		}
+	}
+	out[0] = crockford[acc&0x1f]
+	return string(out[:]), nil
+}
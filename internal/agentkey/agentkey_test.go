@@ -0,0 +1,168 @@
+package agentkey
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func takenSet(keys ...string) func(string) bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return func(key string) bool { return set[key] }
+}
+
+func TestNewULID(t *testing.T) {
+	// The time part matches the ULID spec example 01ARZ3NDEKTSV4RRFFQ69G5FAV.
+	ts := time.UnixMilli(1469922850259)
+	id, err := NewULID(ts, bytes.NewReader(make([]byte, 10)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "01ARZ3NDEK0000000000000000" {
+		t.Fatalf("unexpected ULID %s", id)
+	}
+
+	max, err := NewULID(time.UnixMilli(1<<48-1), bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if max != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Fatalf("unexpected max ULID %s", max)
+	}
+
+	if _, err := NewULID(ts, bytes.NewReader(nil)); err == nil {
+		t.Fatalf("expected error when randomness runs out")
+	}
+}
+
+func TestGenerateULID(t *testing.T) {
+	opts := Options{
+		Now:  func() time.Time { return time.UnixMilli(1469922850259) },
+		Rand: bytes.NewReader(append(make([]byte, 10), bytes.Repeat([]byte{1}, 10)...)),
+	}
+	key, err := Generate(opts, takenSet("buyer-agent-01arz3ndek0000000000000000"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != "buyer-agent-01arz3ndek040g2081040g2081" {
+		t.Fatalf("expected retry past the taken key, got %s", key)
+	}
+
+	key, err = Generate(Options{Prefix: "ops"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^ops-[0-9a-hjkmnp-tv-z]{26}$`).MatchString(key) {
+		t.Fatalf("unexpected key %s", key)
+	}
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	attrs := map[string]string{"team": "payments", "env": "prod"}
+	tests := []struct {
+		name     string
+		template string
+		taken    []string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "first free sequence",
+			template: "{{.Team}}-{{.Env}}-{{.Seq}}",
+			want:     "payments-prod-1",
+		},
+		{
+			name:     "skips taken sequences",
+			template: "{{.Team}}-{{.Env}}-{{printf \"%03d\" .Seq}}",
+			taken:    []string{"payments-prod-001", "payments-prod-002"},
+			want:     "payments-prod-003",
+		},
+		{
+			name:     "without seq",
+			template: "{{.Team}}-{{.Env}}",
+			want:     "payments-prod",
+		},
+		{
+			name:     "without seq and taken",
+			template: "{{.Team}}-{{.Env}}",
+			taken:    []string{"payments-prod"},
+			wantErr:  "add {{.Seq}}",
+		},
+		{
+			name:     "missing attribute",
+			template: "{{.Team}}-{{.Region}}",
+			wantErr:  "Region",
+		},
+		{
+			name:     "invalid key",
+			template: "{{.Team}} {{.Env}}",
+			wantErr:  "not a valid key",
+		},
+		{
+			name:     "parse error",
+			template: "{{.Team",
+			wantErr:  "invalid key template",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := Generate(Options{Strategy: StrategyTemplate, Template: tt.template, Attrs: attrs}, takenSet(tt.taken...))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got key=%q err=%v", tt.wantErr, key, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, key)
+			}
+		})
+	}
+}
+
+func TestGenerateHash(t *testing.T) {
+	a, err := Generate(Options{Strategy: StrategyHash, Attrs: map[string]string{"team": "payments", "env": "prod"}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := Generate(Options{Strategy: StrategyHash, Attrs: map[string]string{"Env": " prod ", "team": "payments"}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a != b {
+		t.Fatalf("expected the same key for the same attributes, got %s and %s", a, b)
+	}
+	if !regexp.MustCompile(`^buyer-agent-[0-9a-f]{12}$`).MatchString(a) {
+		t.Fatalf("unexpected key %s", a)
+	}
+
+	c, _ := Generate(Options{Strategy: StrategyHash, Attrs: map[string]string{"team": "payments", "env": "staging"}}, nil)
+	if c == a {
+		t.Fatalf("expected different attributes to give a different key")
+	}
+
+	if _, err := Generate(Options{Strategy: StrategyHash, Attrs: map[string]string{"team": "payments", "env": "prod"}}, takenSet(a)); !errors.Is(err, ErrTaken) {
+		t.Fatalf("expected ErrTaken, got %v", err)
+	}
+	if _, err := Generate(Options{Strategy: StrategyHash}, nil); err == nil {
+		t.Fatalf("expected error without attributes")
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	if s, err := ParseStrategy(" Hash "); err != nil || s != StrategyHash {
+		t.Fatalf("expected hash, got %q (%v)", s, err)
+	}
+	if _, err := ParseStrategy("uuid"); err == nil {
+		t.Fatalf("expected error for unknown strategy")
+	}
+}
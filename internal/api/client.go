@@ -11,11 +11,37 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 var errSessionExpired = errors.New("session expired; run openspend auth login")
 
+// StatusError is returned when the server answers with a non-2xx status.
+type StatusError struct {
+	Operation  string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s failed: status=%d body=%s", e.Operation, e.StatusCode, e.Body)
+}
+
+// IsStatus reports whether err is a StatusError with one of the given codes.
+func IsStatus(err error, codes ...int) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	for _, code := range codes {
+		if statusErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
 type Options struct {
 	BaseURL             string
 	SessionToken        string
@@ -27,6 +53,8 @@ type Options struct {
 	PolicyDetailsPath   string
 	AgentPath           string
 	SearchPath          string
+	SpendPath           string
+	AuditPath           string
 	BrowserAuthPath     string
 	CliAuthStartPath    string
 	CliAuthPollPath     string
@@ -35,8 +63,11 @@ type Options struct {
 }
 
 type Client struct {
-	baseURL             string
-	httpClient          *http.Client
+	baseURL    string
+	httpClient *http.Client
+	// mu guards the session fields, which responses may rotate, so a
+	// Client can be shared by concurrent requests.
+	mu                  sync.Mutex
 	sessionToken        string
 	authTokenType       string
 	sessionCookie       string
@@ -46,6 +77,8 @@ type Client struct {
 	policyDetailsPath   string
 	agentPath           string
 	searchPath          string
+	spendPath           string
+	auditPath           string
 	authPath            string
 	cliAuthStartPath    string
 	cliAuthPollPath     string
@@ -69,12 +102,16 @@ type WhoAmIResponse struct {
 		PolicyName  *string `json:"policyName"`
 		PolicyMode  *string `json:"policyMode"`
 		Precedence  *int    `json:"precedence"`
+		// CreatedAt and LastSeenAt are omitted by older servers.
+		CreatedAt  *string `json:"createdAt"`
+		LastSeenAt *string `json:"lastSeenAt"`
 	} `json:"subjects"`
 }
 
 type InitPolicyRequest struct {
 	Name        string   `json:"name,omitempty"`
 	Description string   `json:"description,omitempty"`
+	Mode        string   `json:"mode,omitempty"`
 	MaxPrice    *int64   `json:"maxPrice,omitempty"`
 	Asset       string   `json:"asset,omitempty"`
 	Network     string   `json:"network,omitempty"`
@@ -85,6 +122,7 @@ type InitPolicyResponse struct {
 	Policy struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
+		Mode string `json:"mode"`
 	} `json:"policy"`
 	Created bool `json:"created"`
 }
@@ -94,6 +132,9 @@ type CreateAgentRequest struct {
 	DisplayName string `json:"displayName,omitempty"`
 	Kind        string `json:"kind,omitempty"`
 	PolicyID    string `json:"policyId,omitempty"`
+	// CreateOnly makes the request fail with 409 Conflict when the external
+	// key is already in use, instead of updating that agent.
+	CreateOnly bool `json:"createOnly,omitempty"`
 }
 
 type CreateAgentResponse struct {
@@ -107,10 +148,197 @@ type CreateAgentResponse struct {
 	Bound    bool   `json:"bound"`
 }
 
+// ClaimAgentRequest gives an anonymous agent an external key, turning it into
+// an identified agent.
+type ClaimAgentRequest struct {
+	ExternalKey string `json:"externalKey"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// AgentSubject is an agent subject as returned by the agent endpoints.
+type AgentSubject struct {
+	ID          string  `json:"id"`
+	ExternalKey string  `json:"externalKey"`
+	DisplayName *string `json:"displayName"`
+	Kind        string  `json:"kind"`
+	Status      string  `json:"status"`
+	CreatedAt   string  `json:"createdAt"`
+	LastSeenAt  *string `json:"lastSeenAt"`
+}
+
+// AgentPolicyBinding is one policy bound to an agent subject.
+type AgentPolicyBinding struct {
+	PolicyID     string `json:"policyId"`
+	PolicyName   string `json:"policyName"`
+	PolicyMode   string `json:"policyMode"`
+	PolicyStatus string `json:"policyStatus"`
+	Precedence   int    `json:"precedence"`
+	Active       bool   `json:"active"`
+}
+
+// ListAgentsRequest filters and pages the agent list. Sort is name, created
+// or last-seen (most recently seen first).
+type ListAgentsRequest struct {
+	Status   string
+	Kind     string
+	PolicyID string
+	Search   string
+	Sort     string
+	Cursor   string
+	Limit    int
+}
+
+// AgentListItem is an agent subject with its policy bindings.
+type AgentListItem struct {
+	AgentSubject
+	Bindings []AgentPolicyBinding `json:"bindings"`
+}
+
+type ListAgentsResponse struct {
+	Agents     []AgentListItem `json:"agents"`
+	NextCursor *string         `json:"nextCursor"`
+	HasMore    bool            `json:"hasMore"`
+}
+
+type AgentDetailsResponse struct {
+	Subject  AgentSubject         `json:"subject"`
+	Bindings []AgentPolicyBinding `json:"bindings"`
+	// Budget is nil when the agent has no spending budget.
+	Budget *AgentBudget `json:"budget,omitempty"`
+}
+
+// AgentBudget caps what an agent may spend per day and per calendar month.
+// Limits and spend are base-unit integer strings of Asset; a nil limit is
+// unlimited. Spent values are read-only and reported by the server.
+type AgentBudget struct {
+	Asset        string  `json:"asset"`
+	DailyLimit   *string `json:"dailyLimit"`
+	MonthlyLimit *string `json:"monthlyLimit"`
+	DailySpent   *string `json:"dailySpent,omitempty"`
+	MonthlySpent *string `json:"monthlySpent,omitempty"`
+	UpdatedAt    *string `json:"updatedAt,omitempty"`
+}
+
+// SetAgentBudgetRequest replaces an agent's budget. A nil limit removes it.
+type SetAgentBudgetRequest struct {
+	Asset        string  `json:"asset"`
+	DailyLimit   *string `json:"dailyLimit"`
+	MonthlyLimit *string `json:"monthlyLimit"`
+}
+
+type AgentBudgetResponse struct {
+	Budget *AgentBudget `json:"budget"`
+}
+
+// AgentActivityEvent is one purchase or invocation made by an agent, with the
+// policy decision the marketplace took. Price is in base units of Asset.
+type AgentActivityEvent struct {
+	ID          string  `json:"id"`
+	OccurredAt  string  `json:"occurredAt"`
+	Type        string  `json:"type"`
+	ResourceURL string  `json:"resourceUrl"`
+	Price       *string `json:"price"`
+	Asset       *string `json:"asset"`
+	Network     *string `json:"network"`
+	Decision    string  `json:"decision"`
+	PolicyID    *string `json:"policyId"`
+	RuleID      *string `json:"ruleId"`
+}
+
+// AgentActivityRequest selects a page of agent activity, oldest first.
+// Cursor, when set, continues after a previous page and takes precedence
+// over Since.
+type AgentActivityRequest struct {
+	Since  time.Time
+	Cursor string
+	Limit  int
+}
+
+type AgentActivityResponse struct {
+	Events []AgentActivityEvent `json:"events"`
+	// NextCursor continues after the last event. It is set on the last page
+	// too, so new events can be polled for later.
+	NextCursor *string `json:"nextCursor"`
+	HasMore    bool    `json:"hasMore"`
+}
+
+// SpendRequest asks for spend between Since and Until grouped by one
+// dimension (agent, policy, host, network or asset), with a series of
+// Interval-sized buckets (hour, day or week) per group.
+type SpendRequest struct {
+	Since    time.Time
+	Until    time.Time
+	GroupBy  string
+	Interval string
+}
+
+// SpendGroup is the spend of one group in one asset. Total and Series are
+// base units of Asset; Series holds one value per interval, oldest first.
+type SpendGroup struct {
+	Key    string   `json:"key"`
+	Label  *string  `json:"label"`
+	Asset  string   `json:"asset"`
+	Total  string   `json:"total"`
+	Count  int      `json:"count"`
+	Series []string `json:"series"`
+	// Budget is the agent's budget when grouping by agent.
+	Budget *AgentBudget `json:"budget,omitempty"`
+}
+
+type SpendResponse struct {
+	Since    string       `json:"since"`
+	Until    string       `json:"until"`
+	GroupBy  string       `json:"groupBy"`
+	Interval string       `json:"interval"`
+	Groups   []SpendGroup `json:"groups"`
+}
+
+// AuditActor is who made a change: a user, or an agent acting through a
+// CLI token.
+type AuditActor struct {
+	Type    string  `json:"type"`
+	ID      string  `json:"id"`
+	Name    *string `json:"name"`
+	TokenID *string `json:"tokenId"`
+}
+
+// AuditEntry is one administrative change. Before and After are the target's
+// state around the change, or null when it was created or deleted.
+type AuditEntry struct {
+	ID         string          `json:"id"`
+	OccurredAt string          `json:"occurredAt"`
+	Actor      AuditActor      `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   string          `json:"targetId"`
+	TargetName *string         `json:"targetName"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditRequest selects audit entries, oldest first. Actor matches an actor
+// ID, name or token ID; Target matches a policy ID or subject key or ID.
+type AuditRequest struct {
+	Actor  string
+	Target string
+	Action string
+	Since  time.Time
+	Until  time.Time
+	Cursor string
+	Limit  int
+}
+
+type AuditResponse struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor *string      `json:"nextCursor"`
+	HasMore    bool         `json:"hasMore"`
+}
+
 type SearchRequest struct {
 	Query            string
 	Networks         []string
 	Limit            int
+	Offset           int
 	BudgetMax        *float64
 	BudgetAsset      string
 	MinServiceScore  *float64
@@ -143,56 +371,175 @@ type SearchResultItem struct {
 	Score float64 `json:"score"`
 }
 
+type PolicyInfo struct {
+	ID          string  `json:"id"`
+	OwnerUserID string  `json:"ownerUserId"`
+	Mode        string  `json:"mode"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Status      string  `json:"status"`
+	Version     int     `json:"version"`
+	CreatedAt   string  `json:"createdAt"`
+	UpdatedAt   string  `json:"updatedAt"`
+}
+
+type PolicyRule struct {
+	ID                     string  `json:"id"`
+	Effect                 string  `json:"effect"`
+	Scope                  string  `json:"scope"`
+	ResourceHost           *string `json:"resourceHost"`
+	Asset                  *string `json:"asset"`
+	Network                *string `json:"network"`
+	MinScore               *int    `json:"minScore"`
+	MaxPrice               *string `json:"maxPrice"`
+	RequireIdentifiedAgent *bool   `json:"requireIdentifiedAgent"`
+	Priority               int     `json:"priority"`
+	Enabled                bool    `json:"enabled"`
+	ConditionJSON          any     `json:"conditionJson"`
+	CreatedAt              string  `json:"createdAt"`
+	UpdatedAt              string  `json:"updatedAt"`
+}
+
+type PolicySubjectBinding struct {
+	SubjectID   string  `json:"subjectId"`
+	ExternalKey *string `json:"externalKey"`
+	DisplayName *string `json:"displayName"`
+	Kind        string  `json:"kind"`
+	Status      string  `json:"status"`
+	Precedence  int     `json:"precedence"`
+	Active      bool    `json:"active"`
+}
+
+type PolicySummary struct {
+	MinScore               *int     `json:"minScore"`
+	BudgetMax              *string  `json:"budgetMax"`
+	AllowAssets            []string `json:"allowAssets"`
+	AllowNetworks          []string `json:"allowNetworks"`
+	DenyHosts              []string `json:"denyHosts"`
+	RequireIdentifiedAgent bool     `json:"requireIdentifiedAgent"`
+}
+
 type PolicyDetailsResponse struct {
-	Policy struct {
-		ID          string  `json:"id"`
-		OwnerUserID string  `json:"ownerUserId"`
-		Mode        string  `json:"mode"`
-		Name        string  `json:"name"`
-		Description *string `json:"description"`
-		Status      string  `json:"status"`
-		Version     int     `json:"version"`
-		CreatedAt   string  `json:"createdAt"`
-		UpdatedAt   string  `json:"updatedAt"`
-	} `json:"policy"`
-	Rules []struct {
-		ID                     string  `json:"id"`
-		Effect                 string  `json:"effect"`
-		Scope                  string  `json:"scope"`
-		ResourceHost           *string `json:"resourceHost"`
-		Asset                  *string `json:"asset"`
-		Network                *string `json:"network"`
-		MinScore               *int    `json:"minScore"`
-		MaxPrice               *string `json:"maxPrice"`
-		RequireIdentifiedAgent *bool   `json:"requireIdentifiedAgent"`
-		Priority               int     `json:"priority"`
-		Enabled                bool    `json:"enabled"`
-		ConditionJSON          any     `json:"conditionJson"`
-		CreatedAt              string  `json:"createdAt"`
-		UpdatedAt              string  `json:"updatedAt"`
-	} `json:"rules"`
-	SubjectBindings []struct {
-		SubjectID   string  `json:"subjectId"`
-		ExternalKey *string `json:"externalKey"`
-		DisplayName *string `json:"displayName"`
-		Kind        string  `json:"kind"`
-		Status      string  `json:"status"`
-		Precedence  int     `json:"precedence"`
-		Active      bool    `json:"active"`
-	} `json:"subjectBindings"`
-	Summary struct {
-		MinScore               *int     `json:"minScore"`
-		BudgetMax              *string  `json:"budgetMax"`
-		AllowAssets            []string `json:"allowAssets"`
-		AllowNetworks          []string `json:"allowNetworks"`
-		DenyHosts              []string `json:"denyHosts"`
-		RequireIdentifiedAgent bool     `json:"requireIdentifiedAgent"`
-	} `json:"summary"`
+	Policy          PolicyInfo             `json:"policy"`
+	Rules           []PolicyRule           `json:"rules"`
+	SubjectBindings []PolicySubjectBinding `json:"subjectBindings"`
+	Summary         PolicySummary          `json:"summary"`
+}
+
+type ListPoliciesRequest struct {
+	Mode         string
+	Status       string
+	NameContains string
+	Limit        int
+	Offset       int
+}
+
+type PolicyListItem struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Description  *string `json:"description"`
+	Mode         string  `json:"mode"`
+	Status       string  `json:"status"`
+	Version      int     `json:"version"`
+	UpdatedAt    string  `json:"updatedAt"`
+	BindingCount int     `json:"bindingCount"`
+}
+
+type ListPoliciesResponse struct {
+	Items      []PolicyListItem `json:"items"`
+	Pagination struct {
+		Total  int `json:"total"`
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+	} `json:"pagination"`
+}
+
+// NextOffset returns the offset of the page after res, which was fetched at
+// offset with limit, and whether that page may have items. Without a total
+// from the server, a full page may be followed by more.
+func (res ListPoliciesResponse) NextOffset(offset, limit int) (int, bool) {
+	next := offset + len(res.Items)
+	if res.Pagination.Total > 0 {
+		return next, next < res.Pagination.Total
+	}
+	if limit <= 0 {
+		limit = res.Pagination.Limit
+	}
+	return next, len(res.Items) > 0 && len(res.Items) >= limit
+}
+
+// PolicyVersion describes one saved version of a policy.
+type PolicyVersion struct {
+	Version       int     `json:"version"`
+	CreatedAt     string  `json:"createdAt"`
+	AuthorUserID  string  `json:"authorUserId"`
+	AuthorName    *string `json:"authorName"`
+	ChangeSummary *string `json:"changeSummary"`
+}
+
+type PolicyHistoryResponse struct {
+	PolicyID string          `json:"policyId"`
+	Versions []PolicyVersion `json:"versions"`
+}
+
+type ClonePolicyRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// PolicyRebindRequest moves a policy's subject bindings to another policy
+// before it is archived.
+type PolicyRebindRequest struct {
+	RebindTo string `json:"rebindTo,omitempty"`
+}
+
+type DeletePolicyResponse struct {
+	PolicyID        string `json:"policyId"`
+	Deleted         bool   `json:"deleted"`
+	ReboundSubjects int    `json:"reboundSubjects"`
+}
+
+// PolicyRuleInput is the writable form of PolicyRule.
+type PolicyRuleInput struct {
+	ID                     string  `json:"id,omitempty"`
+	Effect                 string  `json:"effect"`
+	Scope                  string  `json:"scope"`
+	ResourceHost           *string `json:"resourceHost"`
+	Asset                  *string `json:"asset"`
+	Network                *string `json:"network"`
+	MinScore               *int    `json:"minScore"`
+	MaxPrice               *string `json:"maxPrice"`
+	RequireIdentifiedAgent *bool   `json:"requireIdentifiedAgent"`
+	Priority               int     `json:"priority"`
+	Enabled                bool    `json:"enabled"`
+	ConditionJSON          any     `json:"conditionJson,omitempty"`
+}
+
+type PolicyBindingInput struct {
+	SubjectID          string `json:"subjectId,omitempty"`
+	SubjectExternalKey string `json:"subjectExternalKey,omitempty"`
+	Precedence         int    `json:"precedence"`
+	Active             bool   `json:"active"`
+}
+
+// ApplyPolicyRequest replaces a policy's metadata and rules. A nil
+// SubjectBindings leaves bindings untouched; an empty slice removes them all.
+type ApplyPolicyRequest struct {
+	Name            string                `json:"name"`
+	Description     *string               `json:"description,omitempty"`
+	Mode            string                `json:"mode,omitempty"`
+	Status          string                `json:"status,omitempty"`
+	Rules           []PolicyRuleInput     `json:"rules"`
+	SubjectBindings *[]PolicyBindingInput `json:"subjectBindings,omitempty"`
+	// ExpectedVersion rejects the write when the server version differs.
+	ExpectedVersion int `json:"expectedVersion,omitempty"`
 }
 
 type ExchangeCliAuthRequest struct {
 	LoginAs            string `json:"loginAs"`
 	SubjectExternalKey string `json:"subjectExternalKey,omitempty"`
+	// TTLSeconds asks for a shorter-lived token; zero uses the server default.
+	TTLSeconds int64 `json:"ttlSeconds,omitempty"`
 }
 
 type ExchangeCliAuthResponse struct {
@@ -201,6 +548,22 @@ type ExchangeCliAuthResponse struct {
 	LoginAs            string     `json:"loginAs"`
 	SubjectExternalKey *string    `json:"subjectExternalKey"`
 	SubjectDisplayName *string    `json:"subjectDisplayName"`
+	TokenID            *string    `json:"tokenId"`
+}
+
+// AgentToken describes a CLI token minted for an agent. The token value
+// itself is only returned when it is minted.
+type AgentToken struct {
+	ID         string  `json:"id"`
+	CreatedAt  string  `json:"createdAt"`
+	ExpiresAt  string  `json:"expiresAt"`
+	LastUsedAt *string `json:"lastUsedAt"`
+	CreatedBy  *string `json:"createdBy"`
+	Revoked    bool    `json:"revoked"`
+}
+
+type AgentTokensResponse struct {
+	Tokens []AgentToken `json:"tokens"`
 }
 
 type CliDeviceAuthStartResponse struct {
@@ -239,6 +602,8 @@ func New(opts Options) *Client {
 		policyDetailsPath:   fallback(opts.PolicyDetailsPath, "/api/policy"),
 		agentPath:           fallback(opts.AgentPath, "/api/cli/agent"),
 		searchPath:          fallback(opts.SearchPath, "/api/search"),
+		spendPath:           fallback(opts.SpendPath, "/api/cli/spend"),
+		auditPath:           fallback(opts.AuditPath, "/api/cli/audit"),
 		authPath:            fallback(opts.BrowserAuthPath, "/api/cli/auth/login"),
 		cliAuthStartPath:    fallback(opts.CliAuthStartPath, "/api/cli/auth/start"),
 		cliAuthPollPath:     fallback(opts.CliAuthPollPath, "/api/cli/auth/poll"),
@@ -248,26 +613,38 @@ func New(opts Options) *Client {
 }
 
 func (c *Client) SetSessionToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.sessionToken = token
 }
 
 func (c *Client) SessionToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.sessionToken
 }
 
 func (c *Client) SessionCookie() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.sessionCookie
 }
 
 func (c *Client) AuthTokenType() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.authTokenType
 }
 
 func (c *Client) SessionExpiresAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.sessionExpiresAt
 }
 
 func (c *Client) SetAuthToken(token, tokenType string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.sessionToken = token
 	c.authTokenType = fallback(strings.TrimSpace(tokenType), "cookie")
 }
@@ -421,23 +798,345 @@ func (c *Client) InitPolicy(ctx context.Context, req InitPolicyRequest) (InitPol
 	return out, nil
 }
 
+// CreateAgent creates or updates the agent with req.ExternalKey; see
+// CreateAgentRequest.CreateOnly.
 func (c *Client) CreateAgent(ctx context.Context, req CreateAgentRequest) (CreateAgentResponse, error) {
-	res, err := c.do(ctx, http.MethodPost, c.agentPath, req, true)
-	if err != nil {
-		return CreateAgentResponse{}, err
+	var out CreateAgentResponse
+	err := c.doJSON(ctx, http.MethodPost, c.agentPath, req, "agent create", &out)
+	return out, err
+}
+
+// ListAgents returns one page of the caller's agent subjects. Older servers
+// answer 404 or 405; see IsStatus.
+func (c *Client) ListAgents(ctx context.Context, req ListAgentsRequest) (ListAgentsResponse, error) {
+	params := url.Values{}
+	if value := strings.TrimSpace(req.Status); value != "" {
+		params.Set("status", value)
+	}
+	if value := strings.TrimSpace(req.Kind); value != "" {
+		params.Set("kind", value)
+	}
+	if value := strings.TrimSpace(req.PolicyID); value != "" {
+		params.Set("policyId", value)
+	}
+	if value := strings.TrimSpace(req.Search); value != "" {
+		params.Set("search", value)
+	}
+	if value := strings.TrimSpace(req.Sort); value != "" {
+		params.Set("sort", value)
+	}
+	if cursor := strings.TrimSpace(req.Cursor); cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	if req.Limit > 0 {
+		params.Set("limit", strconv.Itoa(req.Limit))
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode >= 300 {
-		body, _ := io.ReadAll(res.Body)
-		return CreateAgentResponse{}, fmt.Errorf("agent create failed: status=%d body=%s", res.StatusCode, strings.TrimSpace(string(body)))
+	path := c.agentPath
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
 	}
+	var out ListAgentsResponse
+	err := c.doJSON(ctx, http.MethodGet, path, nil, "agent list", &out)
+	return out, err
+}
 
-	var out CreateAgentResponse
-	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
-		return CreateAgentResponse{}, err
+// Agents returns an iterator over the agent subjects matching req.
+func (c *Client) Agents(req ListAgentsRequest) *PageIterator[AgentListItem] {
+	return newPageIterator(req.Cursor, func(ctx context.Context, cursor string) (page[AgentListItem], error) {
+		req.Cursor = cursor
+		res, err := c.ListAgents(ctx, req)
+		return page[AgentListItem]{items: res.Agents, nextCursor: res.NextCursor, hasMore: res.HasMore}, err
+	})
+}
+
+// GetAgent returns an agent subject by external key or ID.
+func (c *Client) GetAgent(ctx context.Context, key string) (AgentDetailsResponse, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AgentDetailsResponse{}, errors.New("agent key is required")
 	}
-	return out, nil
+
+	var out AgentDetailsResponse
+	err := c.doJSON(ctx, http.MethodGet, c.agentItemPath(key), nil, "agent describe", &out)
+	return out, err
+}
+
+// ClaimAgent promotes an anonymous agent to an identified agent. The subject
+// keeps its ID, history and policy bindings.
+func (c *Client) ClaimAgent(ctx context.Context, subjectID string, req ClaimAgentRequest) (AgentDetailsResponse, error) {
+	subjectID = strings.TrimSpace(subjectID)
+	if subjectID == "" {
+		return AgentDetailsResponse{}, errors.New("subject ID is required")
+	}
+
+	var out AgentDetailsResponse
+	err := c.doJSON(ctx, http.MethodPost, c.agentItemPath(subjectID, "claim"), req, "agent claim", &out)
+	return out, err
+}
+
+// SetAgentStatus changes an agent subject's status, for example to disabled.
+func (c *Client) SetAgentStatus(ctx context.Context, key, status string) (AgentDetailsResponse, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AgentDetailsResponse{}, errors.New("agent key is required")
+	}
+
+	payload := map[string]any{"status": status}
+	var out AgentDetailsResponse
+	err := c.doJSON(ctx, http.MethodPatch, c.agentItemPath(key), payload, "agent status update", &out)
+	return out, err
+}
+
+// ListAgentTokens lists the CLI tokens minted for an agent.
+func (c *Client) ListAgentTokens(ctx context.Context, key string) (AgentTokensResponse, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AgentTokensResponse{}, errors.New("agent key is required")
+	}
+
+	var out AgentTokensResponse
+	err := c.doJSON(ctx, http.MethodGet, c.agentItemPath(key, "tokens"), nil, "agent token list", &out)
+	return out, err
+}
+
+// RevokeAgentToken revokes one of an agent's CLI tokens.
+func (c *Client) RevokeAgentToken(ctx context.Context, key, tokenID string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errors.New("agent key is required")
+	}
+	tokenID = strings.TrimSpace(tokenID)
+	if tokenID == "" {
+		return errors.New("token ID is required")
+	}
+	return c.doJSON(ctx, http.MethodDelete, c.agentItemPath(key, "tokens", tokenID), nil, "agent token revoke", nil)
+}
+
+// GetAgentBudget returns an agent's spending budget. Budget is nil when the
+// agent has none.
+func (c *Client) GetAgentBudget(ctx context.Context, key string) (AgentBudgetResponse, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AgentBudgetResponse{}, errors.New("agent key is required")
+	}
+
+	var out AgentBudgetResponse
+	err := c.doJSON(ctx, http.MethodGet, c.agentItemPath(key, "budget"), nil, "agent budget get", &out)
+	return out, err
+}
+
+// SetAgentBudget replaces an agent's spending budget.
+func (c *Client) SetAgentBudget(ctx context.Context, key string, req SetAgentBudgetRequest) (AgentBudgetResponse, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AgentBudgetResponse{}, errors.New("agent key is required")
+	}
+
+	var out AgentBudgetResponse
+	err := c.doJSON(ctx, http.MethodPut, c.agentItemPath(key, "budget"), req, "agent budget set", &out)
+	return out, err
+}
+
+// ClearAgentBudget removes an agent's spending budget.
+func (c *Client) ClearAgentBudget(ctx context.Context, key string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errors.New("agent key is required")
+	}
+	return c.doJSON(ctx, http.MethodDelete, c.agentItemPath(key, "budget"), nil, "agent budget clear", nil)
+}
+
+// ListAgentActivity returns one page of an agent's activity.
+func (c *Client) ListAgentActivity(ctx context.Context, key string, req AgentActivityRequest) (AgentActivityResponse, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AgentActivityResponse{}, errors.New("agent key is required")
+	}
+
+	params := url.Values{}
+	if cursor := strings.TrimSpace(req.Cursor); cursor != "" {
+		params.Set("cursor", cursor)
+	} else if !req.Since.IsZero() {
+		params.Set("since", req.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if req.Limit > 0 {
+		params.Set("limit", strconv.Itoa(req.Limit))
+	}
+
+	path := c.agentItemPath(key, "activity")
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	var out AgentActivityResponse
+	err := c.doJSON(ctx, http.MethodGet, path, nil, "agent activity", &out)
+	return out, err
+}
+
+// AgentActivity returns an iterator over the agent's activity from req.
+func (c *Client) AgentActivity(key string, req AgentActivityRequest) *PageIterator[AgentActivityEvent] {
+	return newPageIterator(req.Cursor, func(ctx context.Context, cursor string) (page[AgentActivityEvent], error) {
+		req.Cursor = cursor
+		res, err := c.ListAgentActivity(ctx, key, req)
+		return page[AgentActivityEvent]{items: res.Events, nextCursor: res.NextCursor, hasMore: res.HasMore}, err
+	})
+}
+
+// PageIterator walks cursor-paginated results:
+//
+//	it := client.AgentActivity(key, req)
+//	for it.Next(ctx) {
+//		event := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type PageIterator[T any] struct {
+	fetch  func(ctx context.Context, cursor string) (page[T], error)
+	cursor string
+
+	items   []T
+	pos     int
+	fetched bool
+	hasMore bool
+	item    T
+	err     error
+}
+
+type page[T any] struct {
+	items      []T
+	nextCursor *string
+	hasMore    bool
+}
+
+func newPageIterator[T any](cursor string, fetch func(context.Context, string) (page[T], error)) *PageIterator[T] {
+	return &PageIterator[T]{fetch: fetch, cursor: strings.TrimSpace(cursor)}
+}
+
+// Next advances to the next item, fetching pages as needed. It returns false
+// when the items run out or a request fails; see Err.
+func (it *PageIterator[T]) Next(ctx context.Context) bool {
+	for {
+		if it.pos < len(it.items) {
+			it.item = it.items[it.pos]
+			it.pos++
+			return true
+		}
+		if it.err != nil || (it.fetched && !it.hasMore) {
+			return false
+		}
+
+		res, err := it.fetch(ctx, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.fetched = true
+		it.items, it.pos = res.items, 0
+		it.hasMore = res.hasMore
+
+		previous := it.cursor
+		if res.nextCursor != nil && strings.TrimSpace(*res.nextCursor) != "" {
+			it.cursor = strings.TrimSpace(*res.nextCursor)
+		} else if !it.hasMore {
+			// The last page's own cursor would fetch it again.
+			it.cursor = ""
+		}
+		if it.hasMore && (it.cursor == "" || (len(res.items) == 0 && it.cursor == previous)) {
+			it.err = errors.New("server reported more results without advancing the cursor")
+		}
+	}
+}
+
+// Item returns the item Next advanced to.
+func (it *PageIterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *PageIterator[T]) Err() error {
+	return it.err
+}
+
+// Cursor returns the position after the last fetched page, or "" when the
+// server gave none for it. Starting a new iterator from a non-empty cursor
+// returns only newer items.
+func (it *PageIterator[T]) Cursor() string {
+	return it.cursor
+}
+
+// DeleteAgent deletes an agent subject and its policy bindings.
+func (c *Client) DeleteAgent(ctx context.Context, key string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errors.New("agent key is required")
+	}
+	return c.doJSON(ctx, http.MethodDelete, c.agentItemPath(key), nil, "agent delete", nil)
+}
+
+// ListAudit returns one page of audit entries.
+func (c *Client) ListAudit(ctx context.Context, req AuditRequest) (AuditResponse, error) {
+	params := url.Values{}
+	if value := strings.TrimSpace(req.Actor); value != "" {
+		params.Set("actor", value)
+	}
+	if value := strings.TrimSpace(req.Target); value != "" {
+		params.Set("target", value)
+	}
+	if value := strings.TrimSpace(req.Action); value != "" {
+		params.Set("action", value)
+	}
+	if cursor := strings.TrimSpace(req.Cursor); cursor != "" {
+		params.Set("cursor", cursor)
+	} else if !req.Since.IsZero() {
+		params.Set("since", req.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !req.Until.IsZero() {
+		params.Set("until", req.Until.UTC().Format(time.RFC3339))
+	}
+	if req.Limit > 0 {
+		params.Set("limit", strconv.Itoa(req.Limit))
+	}
+
+	path := c.auditPath
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	var out AuditResponse
+	err := c.doJSON(ctx, http.MethodGet, path, nil, "audit list", &out)
+	return out, err
+}
+
+// Audit returns an iterator over the audit entries matching req.
+func (c *Client) Audit(req AuditRequest) *PageIterator[AuditEntry] {
+	return newPageIterator(req.Cursor, func(ctx context.Context, cursor string) (page[AuditEntry], error) {
+		req.Cursor = cursor
+		res, err := c.ListAudit(ctx, req)
+		return page[AuditEntry]{items: res.Entries, nextCursor: res.NextCursor, hasMore: res.HasMore}, err
+	})
+}
+
+// GetSpend summarises allowed spend for the caller's agents.
+func (c *Client) GetSpend(ctx context.Context, req SpendRequest) (SpendResponse, error) {
+	params := url.Values{}
+	if !req.Since.IsZero() {
+		params.Set("since", req.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if !req.Until.IsZero() {
+		params.Set("until", req.Until.UTC().Format(time.RFC3339))
+	}
+	if value := strings.TrimSpace(req.GroupBy); value != "" {
+		params.Set("groupBy", value)
+	}
+	if value := strings.TrimSpace(req.Interval); value != "" {
+		params.Set("interval", value)
+	}
+
+	path := c.spendPath
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	var out SpendResponse
+	err := c.doJSON(ctx, http.MethodGet, path, nil, "spend summary", &out)
+	return out, err
 }
 
 func (c *Client) Search(ctx context.Context, req SearchRequest) (SearchResponse, error) {
@@ -451,6 +1150,9 @@ func (c *Client) Search(ctx context.Context, req SearchRequest) (SearchResponse,
 	if req.Limit > 0 {
 		params.Set("limit", strconv.Itoa(req.Limit))
 	}
+	if req.Offset > 0 {
+		params.Set("offset", strconv.Itoa(req.Offset))
+	}
 	for _, network := range req.Networks {
 		network = strings.TrimSpace(network)
 		if network == "" {
@@ -479,7 +1181,7 @@ func (c *Client) Search(ctx context.Context, req SearchRequest) (SearchResponse,
 		searchPath += "?" + encoded
 	}
 
-	withSession := strings.TrimSpace(c.sessionToken) != ""
+	withSession := strings.TrimSpace(c.SessionToken()) != ""
 	res, err := c.do(ctx, http.MethodGet, searchPath, nil, withSession)
 	if err != nil {
 		return SearchResponse{}, err
@@ -502,6 +1204,65 @@ func (c *Client) Search(ctx context.Context, req SearchRequest) (SearchResponse,
 	return out, nil
 }
 
+// ListPolicies lists the caller's policies, including policies without
+// bound subjects. Older servers answer 404; see IsStatus.
+func (c *Client) ListPolicies(ctx context.Context, req ListPoliciesRequest) (ListPoliciesResponse, error) {
+	params := url.Values{}
+	if value := strings.TrimSpace(req.Mode); value != "" {
+		params.Set("mode", value)
+	}
+	if value := strings.TrimSpace(req.Status); value != "" {
+		params.Set("status", value)
+	}
+	if value := strings.TrimSpace(req.NameContains); value != "" {
+		params.Set("nameContains", value)
+	}
+	if req.Limit > 0 {
+		params.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Offset > 0 {
+		params.Set("offset", strconv.Itoa(req.Offset))
+	}
+
+	path := strings.TrimRight(c.policyDetailsPath, "/")
+	if encoded := params.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	var out ListPoliciesResponse
+	err := c.doJSON(ctx, http.MethodGet, path, nil, "policy list", &out)
+	return out, err
+}
+
+// Policies iterates policies from req.Offset. The policy list endpoint pages
+// by offset, so the iterator's cursor is the offset of the next page.
+func (c *Client) Policies(req ListPoliciesRequest) *PageIterator[PolicyListItem] {
+	cursor := ""
+	if req.Offset > 0 {
+		cursor = strconv.Itoa(req.Offset)
+	}
+	return newPageIterator(cursor, func(ctx context.Context, cursor string) (page[PolicyListItem], error) {
+		offset := 0
+		if cursor != "" {
+			var err error
+			if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+				return page[PolicyListItem]{}, fmt.Errorf("invalid policy list cursor %q", cursor)
+			}
+		}
+		req.Offset = offset
+		res, err := c.ListPolicies(ctx, req)
+		if err != nil {
+			return page[PolicyListItem]{}, err
+		}
+		next, more := res.NextOffset(offset, req.Limit)
+		out := page[PolicyListItem]{items: res.Items, hasMore: more}
+		if more {
+			nextCursor := strconv.Itoa(next)
+			out.nextCursor = &nextCursor
+		}
+		return out, nil
+	})
+}
+
 func (c *Client) GetPolicyDetails(ctx context.Context, policyID string) (PolicyDetailsResponse, error) {
 	policyID = strings.TrimSpace(policyID)
 	if policyID == "" {
@@ -567,6 +1328,268 @@ func (c *Client) UpdatePolicy(
 	return out, nil
 }
 
+// ApplyPolicy replaces a policy's metadata, rules and optionally bindings.
+func (c *Client) ApplyPolicy(
+	ctx context.Context,
+	policyID string,
+	req ApplyPolicyRequest,
+) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPut, c.policyItemPath(policyID), req, "policy apply", &out)
+	return out, err
+}
+
+// CreatePolicy creates a policy from a full declarative definition.
+func (c *Client) CreatePolicy(ctx context.Context, req ApplyPolicyRequest) (PolicyDetailsResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return PolicyDetailsResponse{}, errors.New("policy name is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPost, strings.TrimRight(c.policyDetailsPath, "/"), req, "policy create", &out)
+	return out, err
+}
+
+// ListPolicyVersions returns a policy's saved versions, newest first.
+func (c *Client) ListPolicyVersions(ctx context.Context, policyID string) (PolicyHistoryResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyHistoryResponse{}, errors.New("policy ID is required")
+	}
+
+	var out PolicyHistoryResponse
+	err := c.doJSON(ctx, http.MethodGet, c.policyItemPath(policyID, "versions"), nil, "policy history", &out)
+	return out, err
+}
+
+// GetPolicyVersion returns a policy as it was at the given version.
+func (c *Client) GetPolicyVersion(ctx context.Context, policyID string, version int) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+	if version <= 0 {
+		return PolicyDetailsResponse{}, errors.New("policy version must be positive")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(
+		ctx,
+		http.MethodGet,
+		c.policyItemPath(policyID, "versions", strconv.Itoa(version)),
+		nil,
+		"policy version",
+		&out,
+	)
+	return out, err
+}
+
+// ClonePolicy copies a policy's metadata and rules into a new policy.
+// Subject bindings are not copied.
+func (c *Client) ClonePolicy(ctx context.Context, policyID string, req ClonePolicyRequest) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return PolicyDetailsResponse{}, errors.New("policy name is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPost, c.policyItemPath(policyID, "clone"), req, "policy clone", &out)
+	return out, err
+}
+
+// ArchivePolicy marks a policy archived, optionally moving its bindings.
+func (c *Client) ArchivePolicy(ctx context.Context, policyID string, req PolicyRebindRequest) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPost, c.policyItemPath(policyID, "archive"), req, "policy archive", &out)
+	return out, err
+}
+
+// DeletePolicy deletes a policy. When rebindTo is set its subject bindings
+// are moved to that policy first.
+func (c *Client) DeletePolicy(ctx context.Context, policyID, rebindTo string) (DeletePolicyResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return DeletePolicyResponse{}, errors.New("policy ID is required")
+	}
+
+	path := c.policyItemPath(policyID)
+	if rebindTo = strings.TrimSpace(rebindTo); rebindTo != "" {
+		path += "?" + url.Values{"rebindTo": {rebindTo}}.Encode()
+	}
+	var out DeletePolicyResponse
+	err := c.doJSON(ctx, http.MethodDelete, path, nil, "policy delete", &out)
+	return out, err
+}
+
+// BindPolicySubject binds a subject to a policy, or updates the precedence
+// and active flag of an existing binding.
+func (c *Client) BindPolicySubject(
+	ctx context.Context,
+	policyID string,
+	binding PolicyBindingInput,
+) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+	if strings.TrimSpace(binding.SubjectID) == "" && strings.TrimSpace(binding.SubjectExternalKey) == "" {
+		return PolicyDetailsResponse{}, errors.New("subject is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPost, c.policyItemPath(policyID, "bindings"), binding, "policy bind", &out)
+	return out, err
+}
+
+// UnbindPolicySubject removes a subject's binding from a policy. subject is
+// a subject external key or ID.
+func (c *Client) UnbindPolicySubject(ctx context.Context, policyID, subject string) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return PolicyDetailsResponse{}, errors.New("subject is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodDelete, c.policyItemPath(policyID, "bindings", subject), nil, "policy unbind", &out)
+	return out, err
+}
+
+// CreatePolicyRule adds a rule to a policy.
+func (c *Client) CreatePolicyRule(
+	ctx context.Context,
+	policyID string,
+	rule PolicyRuleInput,
+) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPost, c.policyItemPath(policyID, "rules"), rule, "policy rule create", &out)
+	return out, err
+}
+
+// UpdatePolicyRule patches a single rule. A nil patch value clears the field.
+func (c *Client) UpdatePolicyRule(
+	ctx context.Context,
+	policyID string,
+	ruleID string,
+	patch map[string]any,
+) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	ruleID = strings.TrimSpace(ruleID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+	if ruleID == "" {
+		return PolicyDetailsResponse{}, errors.New("rule ID is required")
+	}
+	if len(patch) == 0 {
+		return PolicyDetailsResponse{}, errors.New("policy rule update payload is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPatch, c.policyItemPath(policyID, "rules", ruleID), patch, "policy rule update", &out)
+	return out, err
+}
+
+// DeletePolicyRule removes a rule from a policy.
+func (c *Client) DeletePolicyRule(ctx context.Context, policyID, ruleID string) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	ruleID = strings.TrimSpace(ruleID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+	if ruleID == "" {
+		return PolicyDetailsResponse{}, errors.New("rule ID is required")
+	}
+
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodDelete, c.policyItemPath(policyID, "rules", ruleID), nil, "policy rule delete", &out)
+	return out, err
+}
+
+// ReorderPolicyRules sets rule evaluation order. The server reassigns
+// priorities so ruleIDs[0] is evaluated first; every rule must be listed.
+func (c *Client) ReorderPolicyRules(
+	ctx context.Context,
+	policyID string,
+	ruleIDs []string,
+) (PolicyDetailsResponse, error) {
+	policyID = strings.TrimSpace(policyID)
+	if policyID == "" {
+		return PolicyDetailsResponse{}, errors.New("policy ID is required")
+	}
+	if len(ruleIDs) == 0 {
+		return PolicyDetailsResponse{}, errors.New("rule IDs are required")
+	}
+
+	payload := map[string]any{"ruleIds": ruleIDs}
+	var out PolicyDetailsResponse
+	err := c.doJSON(ctx, http.MethodPost, c.policyItemPath(policyID, "rules", "reorder"), payload, "policy rule reorder", &out)
+	return out, err
+}
+
+func (c *Client) policyItemPath(policyID string, segments ...string) string {
+	path := strings.TrimRight(c.policyDetailsPath, "/") + "/" + url.PathEscape(policyID)
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+	return path
+}
+
+func (c *Client) agentItemPath(key string, segments ...string) string {
+	path := strings.TrimRight(c.agentPath, "/") + "/" + url.PathEscape(key)
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+	return path
+}
+
+// doJSON sends an authenticated request and decodes a JSON response into out
+// (when non-nil). Non-2xx responses become a *StatusError labelled operation.
+func (c *Client) doJSON(ctx context.Context, method, path string, body any, operation string, out any) error {
+	res, err := c.do(ctx, method, path, body, true)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return &StatusError{
+			Operation:  operation,
+			StatusCode: res.StatusCode,
+			Body:       strings.TrimSpace(string(raw)),
+		}
+	}
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
 func (c *Client) do(ctx context.Context, method, path string, body any, withSession bool) (*http.Response, error) {
 	var payload []byte
 	if body != nil {
@@ -591,7 +1614,7 @@ func (c *Client) do(ctx context.Context, method, path string, body any, withSess
 		c.captureSessionCookie(res)
 	}
 
-	if withSession && res.StatusCode == http.StatusUnauthorized && c.authTokenType != "bearer" {
+	if withSession && res.StatusCode == http.StatusUnauthorized && c.AuthTokenType() != "bearer" {
 		_ = res.Body.Close()
 		if err := c.refreshSession(ctx, true); err != nil {
 			return nil, err
@@ -620,6 +1643,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, payload []b
 	req.Header.Set("Content-Type", "application/json")
 
 	if withSession {
+		c.mu.Lock()
 		if c.authTokenType == "bearer" {
 			req.Header.Set("Authorization", "Bearer "+c.sessionToken)
 		} else {
@@ -627,29 +1651,34 @@ func (c *Client) doRequest(ctx context.Context, method, path string, payload []b
 				req.AddCookie(&http.Cookie{Name: cookieName, Value: c.sessionToken})
 			}
 		}
+		c.mu.Unlock()
 	}
 
 	return c.httpClient.Do(req)
 }
 
 func (c *Client) ensureSession(ctx context.Context) error {
-	if c.sessionToken == "" {
+	c.mu.Lock()
+	token, tokenType, expiresAt := c.sessionToken, c.authTokenType, c.sessionExpiresAt
+	c.mu.Unlock()
+
+	if token == "" {
 		return errors.New("not authenticated; run openspend auth login")
 	}
-	if c.authTokenType == "bearer" {
-		if !c.sessionExpiresAt.IsZero() && !time.Now().Before(c.sessionExpiresAt) {
+	if tokenType == "bearer" {
+		if !expiresAt.IsZero() && !time.Now().Before(expiresAt) {
 			return errSessionExpired
 		}
 		return nil
 	}
 
 	now := time.Now()
-	if !c.sessionExpiresAt.IsZero() {
-		if !now.Before(c.sessionExpiresAt) {
+	if !expiresAt.IsZero() {
+		if !now.Before(expiresAt) {
 			return errSessionExpired
 		}
 		// Proactively refresh shortly before expiry so long-lived CLI sessions stay valid.
-		if now.Add(2 * time.Minute).After(c.sessionExpiresAt) {
+		if now.Add(2 * time.Minute).After(expiresAt) {
 			if err := c.refreshSession(ctx, false); err != nil {
 				return err
 			}
@@ -667,7 +1696,7 @@ func (c *Client) refreshSession(ctx context.Context, force bool) error {
 	defer res.Body.Close()
 
 	c.captureSessionCookie(res)
-	if c.sessionToken == "" {
+	if c.SessionToken() == "" {
 		return errSessionExpired
 	}
 
@@ -699,12 +1728,16 @@ func (c *Client) refreshSession(ctx context.Context, force bool) error {
 		return errSessionExpired
 	}
 	if payload.Session.ExpiresAt != nil {
+		c.mu.Lock()
 		c.sessionExpiresAt = payload.Session.ExpiresAt.UTC()
+		c.mu.Unlock()
 	}
 	return nil
 }
 
 func (c *Client) captureSessionCookie(res *http.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for _, cookie := range res.Cookies() {
 		if !isSessionCookieName(cookie.Name) {
 			continue
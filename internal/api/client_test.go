@@ -0,0 +1,243 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatusError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &StatusError{Operation: "agent list", StatusCode: http.StatusNotFound, Body: "nf"})
+	if got := err.Error(); got != "wrapped: agent list failed: status=404 body=nf" {
+		t.Fatalf("unexpected message: %s", got)
+	}
+	if !IsStatus(err, http.StatusMethodNotAllowed, http.StatusNotFound) {
+		t.Fatalf("expected a wrapped 404 to match")
+	}
+	if IsStatus(err, http.StatusConflict) {
+		t.Fatalf("expected 404 not to match 409")
+	}
+	if IsStatus(errors.New("boom"), http.StatusNotFound) || IsStatus(nil, http.StatusNotFound) {
+		t.Fatalf("expected non-status errors not to match")
+	}
+}
+
+func TestDoJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("unexpected authorization header %q", got)
+		}
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprint(w, `{"name":"buyer"}`)
+		case "/empty":
+			w.WriteHeader(http.StatusOK)
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/bad-json":
+			fmt.Fprint(w, `{"name":`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "  not found\n")
+		}
+	}))
+	defer server.Close()
+	client := New(Options{BaseURL: server.URL, SessionToken: "tok", AuthTokenType: "bearer"})
+	ctx := context.Background()
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	if err := client.doJSON(ctx, http.MethodGet, "/ok", nil, "op", &out); err != nil || out.Name != "buyer" {
+		t.Fatalf("expected a decoded body, got %+v, %v", out, err)
+	}
+	for _, path := range []string{"/empty", "/no-content"} {
+		if err := client.doJSON(ctx, http.MethodGet, path, nil, "op", &out); err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+	}
+	if err := client.doJSON(ctx, http.MethodGet, "/bad-json", nil, "op", &out); err == nil {
+		t.Fatalf("expected a decode error")
+	}
+
+	err := client.doJSON(ctx, http.MethodDelete, "/missing", nil, "agent delete", nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected a StatusError, got %v", err)
+	}
+	if statusErr.Operation != "agent delete" || statusErr.StatusCode != http.StatusNotFound || statusErr.Body != "not found" {
+		t.Fatalf("unexpected status error: %+v", statusErr)
+	}
+}
+
+func TestPageIterator(t *testing.T) {
+	strPtr := func(v string) *string { return &v }
+
+	tests := []struct {
+		name       string
+		pages      map[string]page[int]
+		want       string
+		wantCursor string
+		wantErr    string
+	}{
+		{
+			name: "last page without cursor",
+			pages: map[string]page[int]{
+				"":   {items: []int{1, 2}, nextCursor: strPtr("c1"), hasMore: true},
+				"c1": {items: []int{3}},
+			},
+			want: "1,2,3",
+			// Resuming from c1 would deliver 3 again.
+			wantCursor: "",
+		},
+		{
+			name: "last page with cursor",
+			pages: map[string]page[int]{
+				"":   {items: []int{1}, nextCursor: strPtr("c1"), hasMore: true},
+				"c1": {items: []int{2}, nextCursor: strPtr(" c2 ")},
+			},
+			want:       "1,2",
+			wantCursor: "c2",
+		},
+		{
+			name:    "more without cursor",
+			pages:   map[string]page[int]{"": {items: []int{1}, hasMore: true}},
+			want:    "1",
+			wantErr: "without advancing the cursor",
+		},
+		{
+			name: "repeated empty page",
+			pages: map[string]page[int]{
+				"":   {items: []int{1}, nextCursor: strPtr("c1"), hasMore: true},
+				"c1": {nextCursor: strPtr("c1"), hasMore: true},
+			},
+			want:    "1",
+			wantErr: "without advancing the cursor",
+		},
+		{
+			name:    "fetch error",
+			pages:   map[string]page[int]{"": {items: []int{1}, nextCursor: strPtr("c1"), hasMore: true}},
+			want:    "1",
+			wantErr: "no page c1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := newPageIterator("", func(_ context.Context, cursor string) (page[int], error) {
+				p, ok := tt.pages[cursor]
+				if !ok {
+					return page[int]{}, fmt.Errorf("no page %s", cursor)
+				}
+				return p, nil
+			})
+			got := make([]string, 0)
+			for it.Next(context.Background()) {
+				got = append(got, fmt.Sprint(it.Item()))
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Fatalf("expected items %s, got %s", tt.want, strings.Join(got, ","))
+			}
+			if tt.wantErr != "" {
+				if it.Err() == nil || !strings.Contains(it.Err().Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, it.Err())
+				}
+				return
+			}
+			if it.Err() != nil {
+				t.Fatalf("unexpected error: %v", it.Err())
+			}
+			if it.Cursor() != tt.wantCursor {
+				t.Fatalf("expected cursor %q, got %q", tt.wantCursor, it.Cursor())
+			}
+			if it.Next(context.Background()) {
+				t.Fatalf("expected the iterator to stay exhausted")
+			}
+		})
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	const count = 5
+
+	tests := []struct {
+		name      string
+		withTotal bool
+		wantPages int
+	}{
+		{name: "with total", withTotal: true, wantPages: 3},
+		// Without a total, paging continues until a short page.
+		{name: "without total", wantPages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pages++
+				offset, limit := 0, 0
+				fmt.Sscan(r.URL.Query().Get("offset"), &offset)
+				fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+				ids := make([]string, 0)
+				for i := offset; i < count && i < offset+limit; i++ {
+					ids = append(ids, fmt.Sprintf(`{"id":"p%d"}`, i))
+				}
+				total := 0
+				if tt.withTotal {
+					total = count
+				}
+				fmt.Fprintf(w, `{"items":[%s],"pagination":{"total":%d}}`, strings.Join(ids, ","), total)
+			}))
+			defer server.Close()
+			client := New(Options{BaseURL: server.URL, SessionToken: "tok", AuthTokenType: "bearer"})
+
+			it := client.Policies(ListPoliciesRequest{Limit: 2})
+			got := make([]string, 0)
+			for it.Next(context.Background()) {
+				got = append(got, it.Item().ID)
+			}
+			if it.Err() != nil {
+				t.Fatalf("unexpected error: %v", it.Err())
+			}
+			if strings.Join(got, ",") != "p0,p1,p2,p3,p4" {
+				t.Fatalf("expected every policy, got %s", strings.Join(got, ","))
+			}
+			if pages != tt.wantPages {
+				t.Fatalf("expected %d pages, got %d", tt.wantPages, pages)
+			}
+		})
+	}
+}
+
+func TestListPoliciesNextOffset(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    int
+		total    int
+		offset   int
+		wantNext int
+		wantMore bool
+	}{
+		{name: "total left", items: 2, total: 5, offset: 2, wantNext: 4, wantMore: true},
+		{name: "total reached", items: 1, total: 5, offset: 4, wantNext: 5},
+		{name: "no total full page", items: 2, offset: 2, wantNext: 4, wantMore: true},
+		{name: "no total short page", items: 1, offset: 4, wantNext: 5},
+		{name: "no total empty page", offset: 6, wantNext: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res ListPoliciesResponse
+			res.Items = make([]PolicyListItem, tt.items)
+			res.Pagination.Total = tt.total
+			next, more := res.NextOffset(tt.offset, 2)
+			if next != tt.wantNext || more != tt.wantMore {
+				t.Fatalf("expected (%d, %v), got (%d, %v)", tt.wantNext, tt.wantMore, next, more)
+			}
+		})
+	}
+}
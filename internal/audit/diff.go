@@ -0,0 +1,109 @@
+// Package audit turns the before and after state recorded with an audited
+// change into field-level differences.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+)
+
+// Diff returns the fields that differ between before and after, sorted by
+// field. Nested objects become dotted paths (summary.maxPrice). Array items
+// that are objects with an "id" are keyed by it (rules[rule_1].maxPrice), so
+// reordering alone does not show as a change; other items are keyed by
+// index. Strings are shown as-is and other values as JSON; a missing or null
+// value is "".
+func Diff(before, after json.RawMessage) ([]policyfile.FieldChange, error) {
+	from, err := flatten(before)
+	if err != nil {
+		return nil, fmt.Errorf("before: %w", err)
+	}
+	to, err := flatten(after)
+	if err != nil {
+		return nil, fmt.Errorf("after: %w", err)
+	}
+
+	fields := make(map[string]struct{}, len(from)+len(to))
+	for field := range from {
+		fields[field] = struct{}{}
+	}
+	for field := range to {
+		fields[field] = struct{}{}
+	}
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	changes := make([]policyfile.FieldChange, 0)
+	for _, field := range names {
+		if from[field] != to[field] {
+			changes = append(changes, policyfile.FieldChange{Field: field, From: from[field], To: to[field]})
+		}
+	}
+	return changes, nil
+}
+
+func flatten(raw json.RawMessage) (map[string]string, error) {
+	out := make(map[string]string)
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return out, nil
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	flattenValue("", value, out)
+	return out, nil
+}
+
+func flattenValue(path string, value any, out map[string]string) {
+	switch v := value.(type) {
+	case nil:
+		return
+	case map[string]any:
+		if len(v) == 0 {
+			out[path] = "{}"
+			return
+		}
+		for key, item := range v {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			flattenValue(child, item, out)
+		}
+	case []any:
+		if len(v) == 0 {
+			out[path] = "[]"
+			return
+		}
+		for i, item := range v {
+			key := strconv.Itoa(i)
+			if obj, ok := item.(map[string]any); ok {
+				if id, ok := obj["id"].(string); ok && id != "" {
+					key = id
+				}
+			}
+			flattenValue(fmt.Sprintf("%s[%s]", path, key), item, out)
+		}
+	case string:
+		out[path] = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			out[path] = fmt.Sprint(v)
+			return
+		}
+		out[path] = string(encoded)
+	}
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  string
+		after   string
+		want    []policyfile.FieldChange
+		wantErr bool
+	}{
+		{
+			name:   "nested and keyed rules",
+			before: `{"name":"Buyer","status":"active","rules":[{"id":"r1","maxPrice":"500000","enabled":true},{"id":"r2","priority":10}]}`,
+			after:  `{"name":"Buyer","status":"inactive","rules":[{"id":"r2","priority":10},{"id":"r1","maxPrice":"250000","enabled":false}]}`,
+			want: []policyfile.FieldChange{
+				{Field: "rules[r1].enabled", From: "true", To: "false"},
+				{Field: "rules[r1].maxPrice", From: "500000", To: "250000"},
+				{Field: "status", From: "active", To: "inactive"},
+			},
+		},
+		{
+			name:  "created",
+			after: `{"externalKey":"buyer-1","tags":["a"],"meta":{},"count":3}`,
+			want: []policyfile.FieldChange{
+				{Field: "count", To: "3"},
+				{Field: "externalKey", To: "buyer-1"},
+				{Field: "meta", To: "{}"},
+				{Field: "tags[0]", To: "a"},
+			},
+		},
+		{
+			name:   "deleted with null after",
+			before: `{"externalKey":"buyer-1","displayName":null}`,
+			after:  `null`,
+			want:   []policyfile.FieldChange{{Field: "externalKey", From: "buyer-1"}},
+		},
+		{
+			name:   "unchanged",
+			before: `{"a":1.50}`,
+			after:  `{"a":1.50}`,
+			want:   []policyfile.FieldChange{},
+		},
+		{
+			name:    "invalid",
+			before:  `{"a":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(json.RawMessage(tt.before), json.RawMessage(tt.after))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("change %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func catalogFixture() []api.SearchResultItem {
+	return []api.SearchResultItem{
+		{ID: "img", ResourceURL: "https://img.example/generate", Type: "http", Description: "Stable diffusion image generation", MinPrice: 0.2, Asset: "USDC", Networks: []string{"base"}, Score: 0.9},
+		{ID: "stt", ResourceURL: "https://voice.example/transcribe", Type: "http", Description: "Speech to text transcription", MinPrice: 0.05, Asset: "USDC", Networks: []string{"polygon"}, Score: 0.7},
+		{ID: "ocr", ResourceURL: "https://docs.example/ocr", Type: "mcp", Description: "OCR for scanned documents and images", MinPrice: 0.01, Asset: "ETH", Networks: []string{"base"}, Score: 0.5},
+	}
+}
+
+func TestSnapshot_WriteReadVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.db")
+	items := append(catalogFixture(), catalogFixture()[0])
+	snapshot := NewSnapshot("https://openspend.ai", []string{"*"}, Dedupe(items), time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC))
+	if err := snapshot.Sign([]byte("secret")); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if err := Write(path, snapshot); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if got.Manifest.Version != "20260304T050607Z" {
+		t.Fatalf("unexpected version %q", got.Manifest.Version)
+	}
+	if len(got.Items) != 3 || got.Manifest.ItemCount != 3 {
+		t.Fatalf("expected 3 deduplicated items, got %d (manifest %d)", len(got.Items), got.Manifest.ItemCount)
+	}
+	if err := got.Verify([]byte("secret")); err != nil {
+		t.Fatalf("expected signature to verify: %v", err)
+	}
+	if err := got.Verify(nil); err != nil {
+		t.Fatalf("expected digest to verify without key: %v", err)
+	}
+	if err := got.Verify([]byte("other")); !errors.Is(err, ErrSignatureMismatch) {
+		t.Fatalf("expected signature mismatch with wrong key, got %v", err)
+	}
+
+	tampered := got
+	tampered.Manifest.Queries = []string{"image"}
+	if err := tampered.Verify(nil); !errors.Is(err, ErrSignatureMismatch) {
+		t.Fatalf("expected digest mismatch after editing queries, got %v", err)
+	}
+
+	got.Items[0].MinPrice = 100
+	if err := got.Verify(nil); !errors.Is(err, ErrSignatureMismatch) {
+		t.Fatalf("expected digest mismatch after tampering, got %v", err)
+	}
+}
+
+func TestSnapshot_UnsignedRejectsKey(t *testing.T) {
+	snapshot := NewSnapshot("https://openspend.ai", nil, catalogFixture(), time.Now())
+	if err := snapshot.Sign(nil); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if err := snapshot.Verify([]byte("secret")); !errors.Is(err, ErrSignatureMismatch) {
+		t.Fatalf("expected unsigned snapshot to fail keyed verification, got %v", err)
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing.db"))
+	if err == nil || !strings.Contains(err.Error(), "catalog sync") {
+		t.Fatalf("expected hint to run catalog sync, got %v", err)
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	budget := 0.1
+	minScore := 0.6
+	tests := []struct {
+		name string
+		req  api.SearchRequest
+		want []string
+	}{
+		{name: "bm25 text match", req: api.SearchRequest{Query: "image generation"}, want: []string{"img"}},
+		{name: "exact terms rank above fuzzy matches", req: api.SearchRequest{Query: "images"}, want: []string{"ocr", "img"}},
+		{name: "trigram fallback matches typos", req: api.SearchRequest{Query: "transcripton"}, want: []string{"stt"}},
+		{name: "network filter", req: api.SearchRequest{Query: "images", Networks: []string{"polygon"}}, want: []string{}},
+		{name: "budget filter", req: api.SearchRequest{Query: "image", BudgetMax: &budget}, want: []string{"ocr"}},
+		{name: "asset filter", req: api.SearchRequest{Query: "image", BudgetAsset: "usdc"}, want: []string{"img"}},
+		{name: "min service score", req: api.SearchRequest{Query: "example", MinServiceScore: &minScore}, want: []string{"img", "stt"}},
+		{name: "limit", req: api.SearchRequest{Query: "example", Limit: 1}, want: []string{"img"}},
+	}
+
+	idx := NewIndex(catalogFixture())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := idx.Search(tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make([]string, 0, len(hits))
+			for _, hit := range hits {
+				got = append(got, hit.Item.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIndexSearch_RejectsUnavailableFilters(t *testing.T) {
+	score := 0.5
+	_, err := NewIndex(catalogFixture()).Search(api.SearchRequest{Query: "ocr", MinProviderScore: &score})
+	if err == nil {
+		t.Fatalf("expected provider score filter to be rejected offline")
+	}
+}
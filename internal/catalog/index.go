@@ -0,0 +1,198 @@
+package catalog
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// trigramWeight scales fuzzy trigram similarity relative to BM25.
+	trigramWeight = 0.5
+	// minTrigramSimilarity is the share of query trigrams a document must
+	// contain to match on trigrams alone (for typos and partial words).
+	minTrigramSimilarity = 0.4
+)
+
+// Index is an in-memory BM25 index over snapshot items with a trigram
+// fallback for fuzzy matches.
+type Index struct {
+	docs   []document
+	df     map[string]int
+	avgLen float64
+}
+
+type document struct {
+	item     api.SearchResultItem
+	terms    map[string]int
+	length   int
+	trigrams map[string]struct{}
+}
+
+// Hit is a matched item and its relevance.
+type Hit struct {
+	Item      api.SearchResultItem
+	Relevance float64
+}
+
+func NewIndex(items []api.SearchResultItem) *Index {
+	idx := &Index{df: make(map[string]int)}
+	total := 0
+	for _, item := range items {
+		text := documentText(item)
+		tokens := tokenize(text)
+		doc := document{
+			item:     item,
+			terms:    make(map[string]int, len(tokens)),
+			length:   len(tokens),
+			trigrams: trigrams(strings.Join(tokens, " ")),
+		}
+		for _, tok := range tokens {
+			if doc.terms[tok] == 0 {
+				idx.df[tok]++
+			}
+			doc.terms[tok]++
+		}
+		total += len(tokens)
+		idx.docs = append(idx.docs, doc)
+	}
+	if len(idx.docs) > 0 {
+		idx.avgLen = float64(total) / float64(len(idx.docs))
+	}
+	return idx
+}
+
+// Search runs req against the index with the same filter semantics as the
+// marketplace search API. Provider and payment scores are not part of
+// snapshots, so those filters are rejected.
+func (idx *Index) Search(req api.SearchRequest) ([]Hit, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, errors.New("query is required")
+	}
+	if req.MinProviderScore != nil || req.MinPaymentScore != nil {
+		return nil, errors.New("provider and payment score filters are not available offline")
+	}
+
+	queryTerms := tokenize(req.Query)
+	queryTrigrams := trigrams(strings.Join(queryTerms, " "))
+
+	hits := make([]Hit, 0)
+	for _, doc := range idx.docs {
+		if !matchesFilters(doc.item, req) {
+			continue
+		}
+		bm25 := idx.bm25(doc, queryTerms)
+		similarity := trigramSimilarity(queryTrigrams, doc.trigrams)
+		if bm25 <= 0 && similarity < minTrigramSimilarity {
+			continue
+		}
+		hits = append(hits, Hit{Item: doc.item, Relevance: bm25 + trigramWeight*similarity})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Relevance != hits[j].Relevance {
+			return hits[i].Relevance > hits[j].Relevance
+		}
+		if hits[i].Item.Score != hits[j].Item.Score {
+			return hits[i].Item.Score > hits[j].Item.Score
+		}
+		return hits[i].Item.ResourceURL < hits[j].Item.ResourceURL
+	})
+	start := req.Offset
+	if start > len(hits) {
+		start = len(hits)
+	}
+	hits = hits[start:]
+	if req.Limit > 0 && len(hits) > req.Limit {
+		hits = hits[:req.Limit]
+	}
+	return hits, nil
+}
+
+func (idx *Index) bm25(doc document, queryTerms []string) float64 {
+	n := float64(len(idx.docs))
+	score := 0.0
+	for _, term := range queryTerms {
+		tf := float64(doc.terms[term])
+		if tf == 0 {
+			continue
+		}
+		df := float64(idx.df[term])
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		norm := 1 - bm25B + bm25B*float64(doc.length)/idx.avgLen
+		score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+	}
+	return score
+}
+
+func matchesFilters(item api.SearchResultItem, req api.SearchRequest) bool {
+	if len(req.Networks) > 0 {
+		matched := false
+		for _, want := range req.Networks {
+			for _, have := range item.Networks {
+				if strings.EqualFold(strings.TrimSpace(want), strings.TrimSpace(have)) {
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if asset := strings.TrimSpace(req.BudgetAsset); asset != "" && !strings.EqualFold(asset, item.Asset) {
+		return false
+	}
+	if req.BudgetMax != nil && item.MinPrice > *req.BudgetMax {
+		return false
+	}
+	if req.MinServiceScore != nil && item.Score < *req.MinServiceScore {
+		return false
+	}
+	return true
+}
+
+func documentText(item api.SearchResultItem) string {
+	parts := []string{item.ResourceURL, item.Description, item.Type, item.Origin.URL}
+	if item.Origin.Title != nil {
+		parts = append(parts, *item.Origin.Title)
+	}
+	parts = append(parts, item.Networks...)
+	return strings.Join(parts, " ")
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func trigrams(text string) map[string]struct{} {
+	out := make(map[string]struct{})
+	for _, word := range strings.Fields(text) {
+		padded := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			out[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return out
+}
+
+// trigramSimilarity is the share of query trigrams present in the document.
+func trigramSimilarity(query, doc map[string]struct{}) float64 {
+	if len(query) == 0 {
+		return 0
+	}
+	shared := 0
+	for tri := range query {
+		if _, ok := doc[tri]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(query))
+}
@@ -0,0 +1,271 @@
+// Package catalog stores offline marketplace snapshots in a bbolt file and
+// searches them locally.
+package catalog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SchemaVersion is the snapshot file layout version written by this CLI.
+const SchemaVersion = 1
+
+const signatureAlgorithm = "hmac-sha256"
+
+var (
+	manifestBucket = []byte("manifest")
+	itemsBucket    = []byte("items")
+	manifestKey    = []byte("manifest")
+)
+
+// ErrSignatureMismatch is returned when a snapshot's signature or digest does
+// not match its contents.
+var ErrSignatureMismatch = errors.New("catalog snapshot signature mismatch")
+
+// Manifest describes a snapshot. Version is derived from CreatedAt so copies
+// of the same snapshot can be compared at a glance.
+type Manifest struct {
+	SchemaVersion      int       `json:"schemaVersion"`
+	Version            string    `json:"version"`
+	CreatedAt          time.Time `json:"createdAt"`
+	Source             string    `json:"source"`
+	Queries            []string  `json:"queries"`
+	ItemCount          int       `json:"itemCount"`
+	Digest             string    `json:"digest"`
+	Signature          string    `json:"signature,omitempty"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm,omitempty"`
+}
+
+type Snapshot struct {
+	Manifest Manifest
+	Items    []api.SearchResultItem
+}
+
+// NewSnapshot builds an unsigned snapshot stamped with createdAt.
+func NewSnapshot(source string, queries []string, items []api.SearchResultItem, createdAt time.Time) Snapshot {
+	createdAt = createdAt.UTC().Truncate(time.Second)
+	sorted := make([]api.SearchResultItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool { return itemKey(sorted[i]) < itemKey(sorted[j]) })
+	return Snapshot{
+		Manifest: Manifest{
+			SchemaVersion: SchemaVersion,
+			Version:       createdAt.Format("20060102T150405Z"),
+			CreatedAt:     createdAt,
+			Source:        source,
+			Queries:       queries,
+			ItemCount:     len(sorted),
+		},
+		Items: sorted,
+	}
+}
+
+// Sign computes the snapshot digest and, when key is non-empty, an HMAC
+// signature over the digest.
+func (s *Snapshot) Sign(key []byte) error {
+	digest, err := s.digest()
+	if err != nil {
+		return err
+	}
+	s.Manifest.Digest = hex.EncodeToString(digest)
+	s.Manifest.Signature = ""
+	s.Manifest.SignatureAlgorithm = ""
+	if len(key) > 0 {
+		s.Manifest.Signature = hex.EncodeToString(sign(key, digest))
+		s.Manifest.SignatureAlgorithm = signatureAlgorithm
+	}
+	return nil
+}
+
+// Verify checks the digest and, when key is non-empty, the signature. A
+// signed snapshot cannot be verified without a key, and a key cannot
+// verify an unsigned snapshot.
+func (s Snapshot) Verify(key []byte) error {
+	digest, err := s.digest()
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(digest) != s.Manifest.Digest {
+		return fmt.Errorf("%w: digest does not match contents", ErrSignatureMismatch)
+	}
+	if len(key) == 0 {
+		return nil
+	}
+	if s.Manifest.Signature == "" {
+		return fmt.Errorf("%w: snapshot is not signed", ErrSignatureMismatch)
+	}
+	got, err := hex.DecodeString(s.Manifest.Signature)
+	if err != nil || !hmac.Equal(got, sign(key, digest)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func (s Snapshot) digest() ([]byte, error) {
+	h := sha256.New()
+	header := []string{
+		strconv.Itoa(s.Manifest.SchemaVersion),
+		s.Manifest.Version,
+		s.Manifest.CreatedAt.UTC().Format(time.RFC3339Nano),
+		s.Manifest.Source,
+		strconv.Itoa(s.Manifest.ItemCount),
+	}
+	for _, field := range header {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	// The queries record what the sync fetched, so they are covered too.
+	queries, err := json.Marshal(s.Manifest.Queries)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(queries)
+	h.Write([]byte{0})
+	for _, item := range s.Items {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(encoded)
+		h.Write([]byte{0})
+	}
+	return h.Sum(nil), nil
+}
+
+func sign(key, digest []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(digest)
+	return mac.Sum(nil)
+}
+
+// Write stores the snapshot at path, replacing any existing file atomically.
+func Write(path string, s Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	_ = os.Remove(tmpPath)
+
+	db, err := bolt.Open(tmpPath, 0o644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		manifest, err := tx.CreateBucket(manifestBucket)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(s.Manifest)
+		if err != nil {
+			return err
+		}
+		if err := manifest.Put(manifestKey, encoded); err != nil {
+			return err
+		}
+
+		items, err := tx.CreateBucket(itemsBucket)
+		if err != nil {
+			return err
+		}
+		for i, item := range s.Items {
+			encoded, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			// Zero-padded positions keep bbolt's key order equal to the signed order.
+			if err := items.Put([]byte(fmt.Sprintf("%08d", i)), encoded); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Read loads a snapshot from path.
+func Read(path string) (Snapshot, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Snapshot{}, fmt.Errorf("catalog snapshot not found at %s; run openspend catalog sync", path)
+		}
+		return Snapshot{}, err
+	}
+	db, err := bolt.Open(path, 0o444, &bolt.Options{ReadOnly: true, Timeout: 5 * time.Second})
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer db.Close()
+
+	var s Snapshot
+	err = db.View(func(tx *bolt.Tx) error {
+		manifest := tx.Bucket(manifestBucket)
+		items := tx.Bucket(itemsBucket)
+		if manifest == nil || items == nil {
+			return errors.New("not an openspend catalog snapshot")
+		}
+		if err := json.Unmarshal(manifest.Get(manifestKey), &s.Manifest); err != nil {
+			return fmt.Errorf("invalid catalog manifest: %w", err)
+		}
+		if s.Manifest.SchemaVersion > SchemaVersion {
+			return fmt.Errorf(
+				"catalog snapshot schema version %d is newer than supported version %d; update openspend",
+				s.Manifest.SchemaVersion,
+				SchemaVersion,
+			)
+		}
+		s.Items = make([]api.SearchResultItem, 0, s.Manifest.ItemCount)
+		return items.ForEach(func(_, v []byte) error {
+			var item api.SearchResultItem
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			s.Items = append(s.Items, item)
+			return nil
+		})
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return s, nil
+}
+
+func itemKey(item api.SearchResultItem) string {
+	if item.ID != "" {
+		return item.ID
+	}
+	return item.ResourceURL
+}
+
+// Dedupe drops repeated items, keyed by ID or resource URL, keeping the first.
+func Dedupe(items []api.SearchResultItem) []api.SearchResultItem {
+	seen := make(map[string]struct{}, len(items))
+	out := make([]api.SearchResultItem, 0, len(items))
+	for _, item := range items {
+		key := itemKey(item)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, item)
+	}
+	return out
+}
@@ -30,6 +30,8 @@ type MarketplaceConfig struct {
 	PolicyDetailsPath string `toml:"policy_details_path"`
 	AgentPath         string `toml:"agent_path"`
 	SearchPath        string `toml:"search_path"`
+	SpendPath         string `toml:"spend_path"`
+	AuditPath         string `toml:"audit_path"`
 }
 
 type AuthConfig struct {
@@ -44,9 +46,36 @@ type AuthConfig struct {
 	SessionRefreshPath  string    `toml:"session_refresh_path"`
 }
 
+// SearchRankConfig holds weights and preferences for `openspend search --rank`.
+type SearchRankConfig struct {
+	ScoreWeight       float64  `toml:"score_weight"`
+	PriceWeight       float64  `toml:"price_weight"`
+	NetworkWeight     float64  `toml:"network_weight"`
+	AssetWeight       float64  `toml:"asset_weight"`
+	PreferredNetworks []string `toml:"preferred_networks,omitempty"`
+	PreferredAssets   []string `toml:"preferred_assets,omitempty"`
+}
+
+type SearchConfig struct {
+	Rank SearchRankConfig `toml:"rank"`
+}
+
+// MoneyConfig holds the optional FX table used to convert amounts between
+// assets, as USD per unit. FXFile rates are layered over FXRates.
+type MoneyConfig struct {
+	FXFile  string             `toml:"fx_file,omitempty"`
+	FXRates map[string]float64 `toml:"fx_rates,omitempty"`
+}
+
 type Config struct {
 	Marketplace MarketplaceConfig `toml:"marketplace"`
 	Auth        AuthConfig        `toml:"auth"`
+	Search      SearchConfig      `toml:"search"`
+	Money       MoneyConfig       `toml:"money"`
+
+	// stored holds the values replaced by ApplyEnvOverrides, so Save does
+	// not persist environment overrides such as an injected agent token.
+	stored *Config
 }
 
 func defaults() Config {
@@ -58,6 +87,8 @@ func defaults() Config {
 			PolicyDetailsPath: "/api/policy",
 			AgentPath:         "/api/cli/agent",
 			SearchPath:        "/api/search",
+			SpendPath:         "/api/cli/spend",
+			AuditPath:         "/api/cli/audit",
 		},
 		Auth: AuthConfig{
 			BrowserLoginPath:    "/api/cli/auth/login",
@@ -68,6 +99,14 @@ func defaults() Config {
 			SessionCookie:       "better-auth.session_token",
 			SessionRefreshPath:  "/api/auth/get-session",
 		},
+		Search: SearchConfig{
+			Rank: SearchRankConfig{
+				ScoreWeight:   0.5,
+				PriceWeight:   0.3,
+				NetworkWeight: 0.1,
+				AssetWeight:   0.1,
+			},
+		},
 	}
 }
 
@@ -79,6 +118,33 @@ func configPath() (string, error) {
 	return filepath.Join(home, ".config", "openspend", "config.toml"), nil
 }
 
+// CatalogPath returns the offline catalog snapshot location. It honours
+// OPENSPEND_CATALOG_PATH and otherwise sits next to the config file.
+func CatalogPath() (string, error) {
+	if v := os.Getenv("OPENSPEND_CATALOG_PATH"); v != "" {
+		return v, nil
+	}
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "catalog.db"), nil
+}
+
+// PolicyTemplatesDir returns the directory holding user policy templates. It
+// honours OPENSPEND_POLICY_TEMPLATES_DIR and otherwise sits next to the config
+// file.
+func PolicyTemplatesDir() (string, error) {
+	if v := os.Getenv("OPENSPEND_POLICY_TEMPLATES_DIR"); v != "" {
+		return v, nil
+	}
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "policy-templates"), nil
+}
+
 func Load() (Config, error) {
 	path, err := configPath()
 	if err != nil {
@@ -118,11 +184,13 @@ func Load() (Config, error) {
 	return cfg, nil
 }
 
+// Save writes cfg to the config file, without environment overrides.
 func Save(cfg Config) error {
 	path, err := configPath()
 	if err != nil {
 		return err
 	}
+	cfg = withoutEnvOverrides(cfg)
 	applyDefaults(&cfg)
 
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -136,49 +204,84 @@ func Save(cfg Config) error {
 	return os.WriteFile(path, data, 0o600)
 }
 
+// envOverrides maps environment variables to the config fields they
+// override. The first variable set wins.
+var envOverrides = []struct {
+	names []string
+	field func(*Config) *string
+}{
+	{[]string{"OPENSPEND_MARKETPLACE_BASE_URL", "OPENSPEND_BASE_URL"}, func(c *Config) *string { return &c.Marketplace.BaseURL }},
+	{[]string{"OPENSPEND_MARKETPLACE_WHOAMI_PATH"}, func(c *Config) *string { return &c.Marketplace.WhoAmIPath }},
+	{[]string{"OPENSPEND_MARKETPLACE_POLICY_INIT_PATH"}, func(c *Config) *string { return &c.Marketplace.PolicyInitPath }},
+	{[]string{"OPENSPEND_MARKETPLACE_POLICY_DETAILS_PATH"}, func(c *Config) *string { return &c.Marketplace.PolicyDetailsPath }},
+	{[]string{"OPENSPEND_MARKETPLACE_AGENT_PATH"}, func(c *Config) *string { return &c.Marketplace.AgentPath }},
+	{[]string{"OPENSPEND_MARKETPLACE_SEARCH_PATH"}, func(c *Config) *string { return &c.Marketplace.SearchPath }},
+	{[]string{"OPENSPEND_MARKETPLACE_SPEND_PATH"}, func(c *Config) *string { return &c.Marketplace.SpendPath }},
+	{[]string{"OPENSPEND_MARKETPLACE_AUDIT_PATH"}, func(c *Config) *string { return &c.Marketplace.AuditPath }},
+	{[]string{"OPENSPEND_AUTH_BROWSER_LOGIN_PATH"}, func(c *Config) *string { return &c.Auth.BrowserLoginPath }},
+	{[]string{"OPENSPEND_AUTH_CLI_AUTH_START_PATH"}, func(c *Config) *string { return &c.Auth.CliAuthStartPath }},
+	{[]string{"OPENSPEND_AUTH_CLI_AUTH_POLL_PATH"}, func(c *Config) *string { return &c.Auth.CliAuthPollPath }},
+	{[]string{"OPENSPEND_AUTH_CLI_AUTH_EXCHANGE_PATH"}, func(c *Config) *string { return &c.Auth.CliAuthExchangePath }},
+	{[]string{"OPENSPEND_AUTH_SESSION_COOKIE"}, func(c *Config) *string { return &c.Auth.SessionCookie }},
+	{[]string{"OPENSPEND_AUTH_SESSION_REFRESH_PATH"}, func(c *Config) *string { return &c.Auth.SessionRefreshPath }},
+	{[]string{"OPENSPEND_AUTH_TOKEN_TYPE"}, func(c *Config) *string { return &c.Auth.AuthTokenType }},
+	{[]string{"OPENSPEND_FX_FILE"}, func(c *Config) *string { return &c.Money.FXFile }},
+}
+
 // ApplyEnvOverrides applies runtime environment overrides to loaded config.
+// Overrides are not persisted: Save writes the values they replaced.
 func ApplyEnvOverrides(cfg *Config) {
 	if cfg == nil {
 		return
 	}
-	if v := os.Getenv("OPENSPEND_MARKETPLACE_BASE_URL"); v != "" {
-		cfg.Marketplace.BaseURL = v
-	} else if v := os.Getenv("OPENSPEND_BASE_URL"); v != "" {
-		cfg.Marketplace.BaseURL = v
-	}
-	if v := os.Getenv("OPENSPEND_MARKETPLACE_WHOAMI_PATH"); v != "" {
-		cfg.Marketplace.WhoAmIPath = v
-	}
-	if v := os.Getenv("OPENSPEND_MARKETPLACE_POLICY_INIT_PATH"); v != "" {
-		cfg.Marketplace.PolicyInitPath = v
-	}
-	if v := os.Getenv("OPENSPEND_MARKETPLACE_POLICY_DETAILS_PATH"); v != "" {
-		cfg.Marketplace.PolicyDetailsPath = v
-	}
-	if v := os.Getenv("OPENSPEND_MARKETPLACE_AGENT_PATH"); v != "" {
-		cfg.Marketplace.AgentPath = v
-	}
-	if v := os.Getenv("OPENSPEND_MARKETPLACE_SEARCH_PATH"); v != "" {
-		cfg.Marketplace.SearchPath = v
-	}
-	if v := os.Getenv("OPENSPEND_AUTH_BROWSER_LOGIN_PATH"); v != "" {
-		cfg.Auth.BrowserLoginPath = v
-	}
-	if v := os.Getenv("OPENSPEND_AUTH_CLI_AUTH_START_PATH"); v != "" {
-		cfg.Auth.CliAuthStartPath = v
-	}
-	if v := os.Getenv("OPENSPEND_AUTH_CLI_AUTH_POLL_PATH"); v != "" {
-		cfg.Auth.CliAuthPollPath = v
+	if cfg.stored == nil {
+		stored := *cfg
+		cfg.stored = &stored
+	}
+	// An injected token (for example an agent token in a container) replaces
+	// the stored session. It is a bearer token unless told otherwise, and the
+	// stored expiry does not apply to it.
+	if v := os.Getenv("OPENSPEND_AUTH_SESSION_TOKEN"); v != "" {
+		cfg.Auth.SessionToken = v
+		cfg.Auth.AuthTokenType = AuthTokenBearer
+		cfg.Auth.SessionExpiresAt = time.Time{}
+	}
+	for _, override := range envOverrides {
+		if v, ok := lookupEnv(override.names); ok {
+			*override.field(cfg) = v
+		}
 	}
-	if v := os.Getenv("OPENSPEND_AUTH_CLI_AUTH_EXCHANGE_PATH"); v != "" {
-		cfg.Auth.CliAuthExchangePath = v
+}
+
+// withoutEnvOverrides returns cfg with env-overridden fields set back to
+// their values from before ApplyEnvOverrides. An injected session token is
+// only kept out while it is still the session in use, so a login or logout
+// made in the meantime is saved.
+func withoutEnvOverrides(cfg Config) Config {
+	if cfg.stored == nil {
+		return cfg
+	}
+	stored := *cfg.stored
+	for _, override := range envOverrides {
+		if _, ok := lookupEnv(override.names); ok {
+			*override.field(&cfg) = *override.field(&stored)
+		}
 	}
-	if v := os.Getenv("OPENSPEND_AUTH_SESSION_COOKIE"); v != "" {
-		cfg.Auth.SessionCookie = v
+	if v := os.Getenv("OPENSPEND_AUTH_SESSION_TOKEN"); v != "" && cfg.Auth.SessionToken == v {
+		cfg.Auth.SessionToken = stored.Auth.SessionToken
+		cfg.Auth.AuthTokenType = stored.Auth.AuthTokenType
+		cfg.Auth.SessionExpiresAt = stored.Auth.SessionExpiresAt
 	}
-	if v := os.Getenv("OPENSPEND_AUTH_SESSION_REFRESH_PATH"); v != "" {
-		cfg.Auth.SessionRefreshPath = v
+	return cfg
+}
+
+func lookupEnv(names []string) (string, bool) {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v, true
+		}
 	}
+	return "", false
 }
 
 func applyDefaults(cfg *Config) {
@@ -205,6 +308,12 @@ func applyDefaults(cfg *Config) {
 	if cfg.Marketplace.SearchPath == "" {
 		cfg.Marketplace.SearchPath = def.Marketplace.SearchPath
 	}
+	if cfg.Marketplace.SpendPath == "" {
+		cfg.Marketplace.SpendPath = def.Marketplace.SpendPath
+	}
+	if cfg.Marketplace.AuditPath == "" {
+		cfg.Marketplace.AuditPath = def.Marketplace.AuditPath
+	}
 	if cfg.Auth.BrowserLoginPath == "" {
 		cfg.Auth.BrowserLoginPath = def.Auth.BrowserLoginPath
 	}
@@ -224,6 +333,14 @@ func applyDefaults(cfg *Config) {
 	if cfg.Auth.SessionRefreshPath == "" {
 		cfg.Auth.SessionRefreshPath = def.Auth.SessionRefreshPath
 	}
+	rank := cfg.Search.Rank
+	// Individual weights may be zero on purpose; only an all-zero section means unset.
+	if rank.ScoreWeight == 0 && rank.PriceWeight == 0 && rank.NetworkWeight == 0 && rank.AssetWeight == 0 {
+		cfg.Search.Rank.ScoreWeight = def.Search.Rank.ScoreWeight
+		cfg.Search.Rank.PriceWeight = def.Search.Rank.PriceWeight
+		cfg.Search.Rank.NetworkWeight = def.Search.Rank.NetworkWeight
+		cfg.Search.Rank.AssetWeight = def.Search.Rank.AssetWeight
+	}
 }
 
 func normalizeAuthTokenType(value string) string {
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveDoesNotPersistEnvOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("OPENSPEND_AUTH_SESSION_TOKEN", "agent-secret")
+	t.Setenv("OPENSPEND_MARKETPLACE_BASE_URL", "http://127.0.0.1:9999")
+
+	path := filepath.Join(home, ".config", "openspend", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored := "[marketplace]\nbase_url = \"https://example.com\"\n\n[auth]\nsession_token = \"user-token\"\nauth_token_type = \"cookie\"\n"
+	if err := os.WriteFile(path, []byte(stored), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ApplyEnvOverrides(&cfg)
+	if cfg.Auth.SessionToken != "agent-secret" || cfg.Auth.AuthTokenType != AuthTokenBearer {
+		t.Fatalf("expected the injected bearer token, got %+v", cfg.Auth)
+	}
+	if cfg.Marketplace.BaseURL != "http://127.0.0.1:9999" {
+		t.Fatalf("expected the base URL override, got %s", cfg.Marketplace.BaseURL)
+	}
+
+	cfg.Auth.SessionCookie = "custom.session"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := readFile(t, path)
+	for _, unwanted := range []string{"agent-secret", "127.0.0.1:9999", "bearer"} {
+		if strings.Contains(data, unwanted) {
+			t.Fatalf("expected %q not to be saved, got:\n%s", unwanted, data)
+		}
+	}
+	for _, want := range []string{`session_token = 'user-token'`, `base_url = 'https://example.com'`, `session_cookie = 'custom.session'`} {
+		if !strings.Contains(data, want) {
+			t.Fatalf("expected %q to be saved, got:\n%s", want, data)
+		}
+	}
+
+	// A login replacing the injected token is saved.
+	cfg.Auth.SessionToken = "new-login"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data := readFile(t, path); !strings.Contains(data, `session_token = 'new-login'`) {
+		t.Fatalf("expected the new login to be saved, got:\n%s", data)
+	}
+}
+
+func TestLegacyMigrationDoesNotPersistInjectedToken(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("OPENSPEND_AUTH_SESSION_TOKEN", "agent-secret")
+
+	legacy := filepath.Join(home, ".openspend", "config.json")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(legacy, []byte(`{"session_token":"legacy-token"}`), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Auth.SessionToken != "agent-secret" {
+		t.Fatalf("expected the injected token, got %s", cfg.Auth.SessionToken)
+	}
+	data := readFile(t, filepath.Join(home, ".config", "openspend", "config.toml"))
+	if strings.Contains(data, "agent-secret") || !strings.Contains(data, "legacy-token") {
+		t.Fatalf("expected the legacy token and not the injected one, got:\n%s", data)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(data)
+}
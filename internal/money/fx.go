@@ -0,0 +1,95 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+var errNoRate = errors.New("no FX rate")
+
+// FXTable holds the USD value of one unit of each asset or fiat currency.
+type FXTable struct {
+	rates map[string]*big.Rat
+}
+
+// DefaultFX returns the built-in table, which only pegs USD stablecoins.
+func DefaultFX() FXTable {
+	t := FXTable{rates: make(map[string]*big.Rat)}
+	for _, symbol := range []string{"USD", "USDC", "USDT", "DAI"} {
+		t.rates[symbol] = big.NewRat(1, 1)
+	}
+	return t
+}
+
+// With returns a copy of t with rates (USD per unit) added or replaced.
+func (t FXTable) With(rates map[string]float64) (FXTable, error) {
+	out := FXTable{rates: make(map[string]*big.Rat, len(t.rates)+len(rates))}
+	for symbol, rate := range t.rates {
+		out.rates[symbol] = rate
+	}
+	for symbol, rate := range rates {
+		if rate <= 0 {
+			return FXTable{}, fmt.Errorf("FX rate for %s must be positive", symbol)
+		}
+		r, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+		if !ok {
+			return FXTable{}, fmt.Errorf("invalid FX rate for %s", symbol)
+		}
+		out.rates[NormalizeSymbol(symbol)] = r
+	}
+	return out, nil
+}
+
+// LoadFXFile reads rates from a TOML or JSON file shaped like
+//
+//	[rates]
+//	ETH = 3000
+//	EUR = 1.08
+//
+// and layers them over t.
+func (t FXTable) LoadFXFile(path string) (FXTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FXTable{}, fmt.Errorf("failed to read FX file: %w", err)
+	}
+	var file struct {
+		Rates map[string]float64 `json:"rates" toml:"rates"`
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = toml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return FXTable{}, fmt.Errorf("failed to parse FX file %s: %w", path, err)
+	}
+	return t.With(file.Rates)
+}
+
+// Convert expresses amount in the target unit. Amounts without a unit, or
+// already in the target unit, are returned unchanged.
+func (t FXTable) Convert(amount Amount, to string) (*big.Rat, error) {
+	from := NormalizeSymbol(amount.Unit)
+	to = NormalizeSymbol(to)
+	if from == "" || from == to {
+		return new(big.Rat).Set(amount.Value), nil
+	}
+	fromRate, ok := t.rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s; add it to the FX table", errNoRate, from)
+	}
+	toRate, ok := t.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s; add it to the FX table", errNoRate, to)
+	}
+	usd := new(big.Rat).Mul(amount.Value, fromRate)
+	return usd.Quo(usd, toRate), nil
+}
@@ -0,0 +1,257 @@
+// Package money converts between human amounts and on-chain base units using
+// per-asset decimals, and between assets using an FX table.
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Asset describes a payment asset on a network.
+type Asset struct {
+	Symbol   string
+	Decimals int
+}
+
+var defaultDecimals = map[string]int{
+	"USDC":  6,
+	"USDT":  6,
+	"EURC":  6,
+	"DAI":   18,
+	"ETH":   18,
+	"WETH":  18,
+	"POL":   18,
+	"MATIC": 18,
+	"SOL":   9,
+	"BTC":   8,
+	"WBTC":  8,
+}
+
+// networkDecimals lists networks where an asset uses non-default decimals.
+var networkDecimals = map[string]map[string]int{
+	"bsc": {"USDC": 18, "USDT": 18},
+}
+
+// fiatUnits are units that can be converted through the FX table but have no
+// on-chain representation.
+var fiatUnits = map[string]struct{}{
+	"USD": {},
+	"EUR": {},
+}
+
+var amountPattern = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]*)?|\.[0-9]+)\s*([A-Za-z][A-Za-z0-9.]*)?\s*$`)
+
+// LookupAsset returns metadata for symbol on network (network may be empty).
+func LookupAsset(symbol, network string) (Asset, bool) {
+	symbol = NormalizeSymbol(symbol)
+	if byAsset, ok := networkDecimals[strings.ToLower(strings.TrimSpace(network))]; ok {
+		if decimals, ok := byAsset[symbol]; ok {
+			return Asset{Symbol: symbol, Decimals: decimals}, true
+		}
+	}
+	decimals, ok := defaultDecimals[symbol]
+	if !ok {
+		return Asset{}, false
+	}
+	return Asset{Symbol: symbol, Decimals: decimals}, true
+}
+
+// IsFiat reports whether unit is a fiat currency rather than an asset.
+func IsFiat(unit string) bool {
+	_, ok := fiatUnits[NormalizeSymbol(unit)]
+	return ok
+}
+
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// Amount is a decimal value with an optional unit (asset or fiat symbol).
+type Amount struct {
+	Value *big.Rat
+	Unit  string
+}
+
+// ParseAmount parses "0.5", "5USD" or "0.25 USDC".
+func ParseAmount(raw string) (Amount, error) {
+	m := amountPattern.FindStringSubmatch(raw)
+	if m == nil {
+		return Amount{}, fmt.Errorf("invalid amount %q (expected a number with an optional unit, for example 0.5 or 5USD)", raw)
+	}
+	value, ok := new(big.Rat).SetString(m[1])
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount %q", raw)
+	}
+	return Amount{Value: value, Unit: NormalizeSymbol(m[2])}, nil
+}
+
+// Float returns the amount value as a float64.
+func (a Amount) Float() float64 {
+	f, _ := a.Value.Float64()
+	return f
+}
+
+func (a Amount) String() string {
+	return strings.TrimSpace(FormatRat(a.Value) + " " + a.Unit)
+}
+
+// ToBaseUnits converts a human amount of asset to integer base units. It fails
+// when the asset is unknown or the amount has more precision than the asset.
+func ToBaseUnits(value *big.Rat, symbol, network string) (*big.Int, error) {
+	asset, ok := LookupAsset(symbol, network)
+	if !ok {
+		return nil, fmt.Errorf("unknown asset %q; decimals are required to convert to base units", symbol)
+	}
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(pow10(asset.Decimals)))
+	if !scaled.IsInt() {
+		return nil, fmt.Errorf(
+			"amount %s has more than %d decimal places for %s",
+			FormatRat(value),
+			asset.Decimals,
+			asset.Symbol,
+		)
+	}
+	return new(big.Int).Set(scaled.Num()), nil
+}
+
+// CeilToBaseUnits is like ToBaseUnits but rounds extra precision up, for
+// prices that arrive as floats and must not be understated.
+func CeilToBaseUnits(value *big.Rat, symbol, network string) (*big.Int, error) {
+	return roundToBaseUnits(value, symbol, network, true)
+}
+
+// FloorToBaseUnits is like ToBaseUnits but drops extra precision, for caps
+// that must not be overstated.
+func FloorToBaseUnits(value *big.Rat, symbol, network string) (*big.Int, error) {
+	return roundToBaseUnits(value, symbol, network, false)
+}
+
+func roundToBaseUnits(value *big.Rat, symbol, network string, up bool) (*big.Int, error) {
+	asset, ok := LookupAsset(symbol, network)
+	if !ok {
+		return nil, fmt.Errorf("unknown asset %q; decimals are required to convert to base units", symbol)
+	}
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(pow10(asset.Decimals)))
+	quo, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if up && rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return quo, nil
+}
+
+// FromBaseUnits converts integer base units of asset to a human amount.
+func FromBaseUnits(base *big.Int, symbol, network string) (*big.Rat, error) {
+	asset, ok := LookupAsset(symbol, network)
+	if !ok {
+		return nil, fmt.Errorf("unknown asset %q; decimals are required to convert from base units", symbol)
+	}
+	return new(big.Rat).SetFrac(base, pow10(asset.Decimals)), nil
+}
+
+// ParseBaseUnits parses a base-unit integer string such as a policy maxPrice.
+func ParseBaseUnits(raw string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(strings.TrimSpace(raw), 10)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid base-unit amount %q", raw)
+	}
+	return value, nil
+}
+
+// FormatBaseUnits renders base units as "<base> (<human> <asset>)", or just
+// the base units when the asset decimals are unknown.
+func FormatBaseUnits(raw, symbol, network string) string {
+	raw = strings.TrimSpace(raw)
+	base, err := ParseBaseUnits(raw)
+	if err != nil || strings.TrimSpace(symbol) == "" {
+		return raw
+	}
+	human, err := FromBaseUnits(base, symbol, network)
+	if err != nil {
+		return raw
+	}
+	return fmt.Sprintf("%s (%s %s)", raw, FormatRat(human), NormalizeSymbol(symbol))
+}
+
+// FormatRat renders value as a plain decimal without exponent or trailing zeros.
+func FormatRat(value *big.Rat) string {
+	if value == nil {
+		return ""
+	}
+	// 36 digits covers 18-decimal assets with headroom.
+	out := value.FloatString(36)
+	if strings.Contains(out, ".") {
+		out = strings.TrimRight(out, "0")
+		out = strings.TrimSuffix(out, ".")
+	}
+	return out
+}
+
+// FormatFloat renders a float price as a plain decimal (no exponent).
+func FormatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// ResolveBaseUnits interprets a max-price style input. Plain integers are base
+// units, kept for backwards compatibility. Values with a unit ("0.5USDC",
+// "5USD") are converted to base units of asset via the FX table when the unit
+// differs from the asset. A unit-bearing value with no asset uses its unit.
+//
+// Values already in asset must fit its decimals exactly. Converted values
+// rarely land on a whole base unit and are rounded down, so a cap never
+// exceeds what was asked for.
+func ResolveBaseUnits(raw, asset, network string, fx FXTable) (*big.Int, string, error) {
+	return resolveBaseUnits(raw, asset, network, fx, FloorToBaseUnits)
+}
+
+// ResolvePriceBaseUnits is like ResolveBaseUnits but rounds converted values
+// up, for prices that must not be understated.
+func ResolvePriceBaseUnits(raw, asset, network string, fx FXTable) (*big.Int, string, error) {
+	return resolveBaseUnits(raw, asset, network, fx, CeilToBaseUnits)
+}
+
+func resolveBaseUnits(
+	raw, asset, network string,
+	fx FXTable,
+	round func(value *big.Rat, symbol, network string) (*big.Int, error),
+) (*big.Int, string, error) {
+	amount, err := ParseAmount(raw)
+	if err != nil {
+		return nil, "", err
+	}
+	asset = NormalizeSymbol(asset)
+	if amount.Unit == "" {
+		if !amount.Value.IsInt() {
+			return nil, "", fmt.Errorf("%q: plain values are base units and must be integers; add a unit such as 0.5USDC", raw)
+		}
+		return new(big.Int).Set(amount.Value.Num()), asset, nil
+	}
+	if asset == "" {
+		if IsFiat(amount.Unit) {
+			return nil, "", fmt.Errorf("%q: an asset is required to convert %s to base units", raw, amount.Unit)
+		}
+		asset = amount.Unit
+	}
+	if amount.Unit == asset {
+		base, err := ToBaseUnits(amount.Value, asset, network)
+		if err != nil {
+			return nil, "", err
+		}
+		return base, asset, nil
+	}
+	value, err := fx.Convert(amount, asset)
+	if err != nil {
+		return nil, "", err
+	}
+	base, err := round(value, asset, network)
+	if err != nil {
+		return nil, "", err
+	}
+	return base, asset, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
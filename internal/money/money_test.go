@@ -0,0 +1,235 @@
+package money
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		raw       string
+		wantValue string
+		wantUnit  string
+		wantErr   bool
+	}{
+		{raw: "0.5", wantValue: "0.5"},
+		{raw: "5USD", wantValue: "5", wantUnit: "USD"},
+		{raw: "0.25 usdc", wantValue: "0.25", wantUnit: "USDC"},
+		{raw: ".5ETH", wantValue: "0.5", wantUnit: "ETH"},
+		{raw: "-1", wantErr: true},
+		{raw: "USDC", wantErr: true},
+		{raw: "1/2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseAmount(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if FormatRat(got.Value) != tt.wantValue || got.Unit != tt.wantUnit {
+				t.Fatalf("expected %s %s, got %s %s", tt.wantValue, tt.wantUnit, FormatRat(got.Value), got.Unit)
+			}
+		})
+	}
+}
+
+func TestBaseUnitConversion(t *testing.T) {
+	tests := []struct {
+		amount  string
+		asset   string
+		network string
+		want    string
+		wantErr string
+	}{
+		{amount: "0.5", asset: "USDC", want: "500000"},
+		{amount: "0.5", asset: "usdc", network: "bsc", want: "500000000000000000"},
+		{amount: "1.5", asset: "ETH", want: "1500000000000000000"},
+		{amount: "0.0000001", asset: "USDC", wantErr: "more than 6 decimal places"},
+		{amount: "1", asset: "DOGE", wantErr: "unknown asset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+tt.asset+tt.network, func(t *testing.T) {
+			value, _ := new(big.Rat).SetString(tt.amount)
+			got, err := ToBaseUnits(value, tt.asset, tt.network)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+			back, err := FromBaseUnits(got, tt.asset, tt.network)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if FormatRat(back) != tt.amount {
+				t.Fatalf("round trip: expected %s, got %s", tt.amount, FormatRat(back))
+			}
+		})
+	}
+}
+
+func TestCeilToBaseUnits(t *testing.T) {
+	tests := map[string]string{
+		"0.5":                "500000",
+		"0.6000000000000001": "600001",
+		"0.0000001":          "1",
+	}
+	for amount, want := range tests {
+		value, _ := new(big.Rat).SetString(amount)
+		got, err := CeilToBaseUnits(value, "USDC", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.String() != want {
+			t.Fatalf("%s: expected %s, got %s", amount, want, got)
+		}
+	}
+}
+
+func TestFormatBaseUnits(t *testing.T) {
+	if got := FormatBaseUnits("500000", "USDC", ""); got != "500000 (0.5 USDC)" {
+		t.Fatalf("unexpected format: %q", got)
+	}
+	if got := FormatBaseUnits("500000", "", ""); got != "500000" {
+		t.Fatalf("expected bare base units without asset, got %q", got)
+	}
+	if got := FormatBaseUnits("12", "DOGE", ""); got != "12" {
+		t.Fatalf("expected bare base units for unknown asset, got %q", got)
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := map[float64]string{0.000001: "0.000001", 0.1: "0.1", 12: "12", 0: "0"}
+	for in, want := range tests {
+		if got := FormatFloat(in); got != want {
+			t.Fatalf("FormatFloat(%v): expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestResolveBaseUnits(t *testing.T) {
+	fx, err := DefaultFX().With(map[string]float64{"ETH": 2000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name      string
+		raw       string
+		asset     string
+		want      string
+		wantAsset string
+		wantErr   string
+	}{
+		{name: "plain base units", raw: "500000", asset: "USDC", want: "500000", wantAsset: "USDC"},
+		{name: "plain base units without asset", raw: "42", want: "42"},
+		{name: "fractional plain value", raw: "0.5", asset: "USDC", wantErr: "must be integers"},
+		{name: "asset unit", raw: "0.5USDC", want: "500000", wantAsset: "USDC"},
+		{name: "fiat into stablecoin", raw: "5USD", asset: "USDC", want: "5000000", wantAsset: "USDC"},
+		{name: "fiat into eth", raw: "5USD", asset: "ETH", want: "2500000000000000", wantAsset: "ETH"},
+		{name: "cross asset", raw: "0.001ETH", asset: "USDC", want: "2000000", wantAsset: "USDC"},
+		{name: "too precise for asset", raw: "0.0000001USDC", asset: "USDC", wantErr: "more than 6 decimal places"},
+		{name: "fiat without asset", raw: "5USD", wantErr: "an asset is required"},
+		{name: "missing rate", raw: "5EUR", asset: "USDC", wantErr: "no FX rate for EUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, asset, err := ResolveBaseUnits(tt.raw, tt.asset, "", fx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want || asset != tt.wantAsset {
+				t.Fatalf("expected %s %s, got %s %s", tt.want, tt.wantAsset, got, asset)
+			}
+		})
+	}
+}
+
+func TestResolveBaseUnitsRounding(t *testing.T) {
+	// 3000 does not divide 1 or 5 evenly, so converted values have more
+	// precision than ETH's 18 decimals.
+	fx, err := DefaultFX().With(map[string]float64{"ETH": 3000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		raw       string
+		asset     string
+		wantFloor string
+		wantCeil  string
+	}{
+		{raw: "5USD", asset: "ETH", wantFloor: "1666666666666666", wantCeil: "1666666666666667"},
+		{raw: "1USD", asset: "ETH", wantFloor: "333333333333333", wantCeil: "333333333333334"},
+		{raw: "1USDC", asset: "ETH", wantFloor: "333333333333333", wantCeil: "333333333333334"},
+		{raw: "0.001ETH", asset: "USDC", wantFloor: "3000000", wantCeil: "3000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw+" in "+tt.asset, func(t *testing.T) {
+			floor, _, err := ResolveBaseUnits(tt.raw, tt.asset, "", fx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ceil, _, err := ResolvePriceBaseUnits(tt.raw, tt.asset, "", fx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if floor.String() != tt.wantFloor || ceil.String() != tt.wantCeil {
+				t.Fatalf("expected %s/%s, got %s/%s", tt.wantFloor, tt.wantCeil, floor, ceil)
+			}
+		})
+	}
+}
+
+func TestLoadFXFile(t *testing.T) {
+	dir := t.TempDir()
+	tomlPath := filepath.Join(dir, "fx.toml")
+	if err := os.WriteFile(tomlPath, []byte("[rates]\nEUR = 1.25\n"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	jsonPath := filepath.Join(dir, "fx.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"rates":{"eth":4000}}`), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	fx, err := DefaultFX().LoadFXFile(tomlPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fx, err = fx.LoadFXFile(jsonPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := fx.Convert(Amount{Value: big.NewRat(4, 1), Unit: "EUR"}, "USDC")
+	if err != nil || FormatRat(got) != "5" {
+		t.Fatalf("expected 4 EUR = 5 USDC, got %s (err=%v)", FormatRat(got), err)
+	}
+	got, err = fx.Convert(Amount{Value: big.NewRat(1, 1), Unit: "ETH"}, "EUR")
+	if err != nil || FormatRat(got) != "3200" {
+		t.Fatalf("expected 1 ETH = 3200 EUR, got %s (err=%v)", FormatRat(got), err)
+	}
+}
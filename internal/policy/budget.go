@@ -0,0 +1,157 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+// BudgetPeriod is one budget limit checked against a candidate. Amounts are
+// base units of the budget asset.
+type BudgetPeriod struct {
+	Period    string `json:"period"`
+	Limit     string `json:"limit"`
+	Spent     string `json:"spent"`
+	Remaining string `json:"remaining"`
+	Exceeded  bool   `json:"exceeded"`
+}
+
+type BudgetCheck struct {
+	Asset   string         `json:"asset"`
+	Periods []BudgetPeriod `json:"periods"`
+}
+
+// Exceeded reports whether any period is exceeded.
+func (b BudgetCheck) Exceeded() bool {
+	for _, p := range b.Periods {
+		if p.Exceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyBudget checks a policy decision against the agent's budget. An allowed
+// purchase is denied when its price would take the spend for a period past
+// the limit; without a price, only an exhausted budget denies. A price in
+// another asset than the budget is converted through fx, rounding up; when
+// that fails the price is not counted. Denied decisions keep their reason
+// and only gain the budget check.
+func ApplyBudget(d Decision, budget *api.AgentBudget, c Candidate, fx money.FXTable) Decision {
+	if budget == nil {
+		return d
+	}
+	asset := money.NormalizeSymbol(budget.Asset)
+
+	amount := c.Price
+	switch {
+	case amount == nil:
+		d.Notes = append(d.Notes, "budget checked without a price: only an exhausted budget denies")
+	case money.NormalizeSymbol(c.Asset) != asset:
+		converted, err := budgetPrice(amount, c.Asset, c.Network, asset, fx)
+		if err != nil {
+			d.Notes = append(d.Notes, fmt.Sprintf(
+				"budget is in %s; a price in %s is not counted against it: %v",
+				asset,
+				orUnknown(c.Asset),
+				err,
+			))
+			amount = nil
+			break
+		}
+		d.Notes = append(d.Notes, fmt.Sprintf(
+			"price counted against the budget as %s",
+			money.FormatBaseUnits(converted.String(), asset, ""),
+		))
+		amount = converted
+	}
+
+	check := BudgetCheck{Asset: asset}
+	var exceeded []string
+	for _, limit := range []struct {
+		period string
+		limit  *string
+		spent  *string
+	}{
+		{"daily", budget.DailyLimit, budget.DailySpent},
+		{"monthly", budget.MonthlyLimit, budget.MonthlySpent},
+	} {
+		rawLimit := trimmed(limit.limit)
+		if rawLimit == "" {
+			continue
+		}
+		max, err := money.ParseBaseUnits(rawLimit)
+		if err != nil {
+			d.Notes = append(d.Notes, fmt.Sprintf("%s budget has an invalid limit %q", limit.period, rawLimit))
+			continue
+		}
+		spent := big.NewInt(0)
+		if raw := trimmed(limit.spent); raw != "" {
+			if parsed, err := money.ParseBaseUnits(raw); err == nil {
+				spent = parsed
+			} else {
+				d.Notes = append(d.Notes, fmt.Sprintf("%s budget has an invalid spend %q; counted as 0", limit.period, raw))
+			}
+		}
+
+		remaining := new(big.Int).Sub(max, spent)
+		if remaining.Sign() < 0 {
+			remaining.SetInt64(0)
+		}
+		period := BudgetPeriod{
+			Period:    limit.period,
+			Limit:     max.String(),
+			Spent:     spent.String(),
+			Remaining: remaining.String(),
+		}
+		if amount == nil {
+			period.Exceeded = spent.Cmp(max) >= 0
+		} else {
+			period.Exceeded = new(big.Int).Add(spent, amount).Cmp(max) > 0
+		}
+		if period.Exceeded {
+			exceeded = append(exceeded, fmt.Sprintf(
+				"%s budget exceeded (spent %s of %s %s)",
+				limit.period,
+				spent,
+				max,
+				asset,
+			))
+		}
+		check.Periods = append(check.Periods, period)
+	}
+	d.Budget = &check
+
+	if d.Allowed && len(exceeded) > 0 {
+		d.Allowed = false
+		d.Reason = fmt.Sprintf("%s but %s", d.Reason, strings.Join(exceeded, "; "))
+	}
+	return d
+}
+
+// budgetPrice converts price, in base units of priceAsset on network, to base
+// units of the budget asset. Budgets are kept in the asset's default decimals.
+func budgetPrice(price *big.Int, priceAsset, network, asset string, fx money.FXTable) (*big.Int, error) {
+	if strings.TrimSpace(priceAsset) == "" {
+		return nil, fmt.Errorf("the price has no asset")
+	}
+	human, err := money.FromBaseUnits(price, priceAsset, network)
+	if err != nil {
+		return nil, err
+	}
+	value, err := fx.Convert(money.Amount{Value: human, Unit: money.NormalizeSymbol(priceAsset)}, asset)
+	if err != nil {
+		return nil, err
+	}
+	return money.CeilToBaseUnits(value, asset, "")
+}
+
+func orUnknown(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "an unknown asset"
+	}
+	return strings.TrimSpace(value)
+}
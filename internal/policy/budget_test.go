@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+func TestApplyBudget(t *testing.T) {
+	budget := &api.AgentBudget{
+		Asset:        "USDC",
+		DailyLimit:   strPtr("20000000"),
+		MonthlyLimit: strPtr("200000000"),
+		DailySpent:   strPtr("19500000"),
+		MonthlySpent: strPtr("150000000"),
+	}
+	fx, err := money.DefaultFX().With(map[string]float64{"ETH": 3000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	allowed := Decision{Allowed: true, Reason: "allowed by rule allow_global"}
+
+	tests := []struct {
+		name        string
+		decision    Decision
+		budget      *api.AgentBudget
+		candidate   Candidate
+		wantAllowed bool
+		wantReason  string
+		wantNote    string
+		wantPeriods int
+	}{
+		{
+			name:        "within budget",
+			decision:    allowed,
+			budget:      budget,
+			candidate:   Candidate{Price: price(500000), Asset: "USDC"},
+			wantAllowed: true,
+			wantReason:  "allowed by rule allow_global",
+			wantPeriods: 2,
+		},
+		{
+			name:        "daily limit exceeded",
+			decision:    allowed,
+			budget:      budget,
+			candidate:   Candidate{Price: price(500001), Asset: "usdc"},
+			wantReason:  "allowed by rule allow_global but daily budget exceeded (spent 19500000 of 20000000 USDC)",
+			wantPeriods: 2,
+		},
+		{
+			name:        "no price only checks exhaustion",
+			decision:    allowed,
+			budget:      budget,
+			candidate:   Candidate{Asset: "USDC"},
+			wantAllowed: true,
+			wantNote:    "only an exhausted budget denies",
+			wantPeriods: 2,
+		},
+		{
+			name:     "exhausted without a price",
+			decision: allowed,
+			budget: &api.AgentBudget{
+				Asset:      "USDC",
+				DailyLimit: strPtr("1000"),
+				DailySpent: strPtr("1000"),
+			},
+			candidate:   Candidate{},
+			wantReason:  "daily budget exceeded",
+			wantPeriods: 1,
+		},
+		{
+			name:     "other asset converted",
+			decision: allowed,
+			budget:   budget,
+			// 0.0002 ETH at 3000 USD is 0.6 USDC, past the 0.5 USDC left today.
+			candidate:   Candidate{Price: price(200000000000000), Asset: "ETH"},
+			wantReason:  "daily budget exceeded",
+			wantNote:    "price counted against the budget as 600000 (0.6 USDC)",
+			wantPeriods: 2,
+		},
+		{
+			name:        "other asset without a rate not counted",
+			decision:    allowed,
+			budget:      budget,
+			candidate:   Candidate{Price: price(900000000), Asset: "DOGE"},
+			wantAllowed: true,
+			wantNote:    "a price in DOGE is not counted",
+			wantPeriods: 2,
+		},
+		{
+			name:        "denied decision keeps its reason",
+			decision:    Decision{Reason: "no rule matched (default deny)"},
+			budget:      budget,
+			candidate:   Candidate{Price: price(900000000), Asset: "USDC"},
+			wantReason:  "no rule matched (default deny)",
+			wantPeriods: 2,
+		},
+		{
+			name:        "no budget",
+			decision:    allowed,
+			candidate:   Candidate{Price: price(900000000), Asset: "USDC"},
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ApplyBudget(tt.decision, tt.budget, tt.candidate, fx)
+			if d.Allowed != tt.wantAllowed {
+				t.Fatalf("expected allowed=%t, got %+v", tt.wantAllowed, d)
+			}
+			if tt.wantReason != "" && !strings.Contains(d.Reason, tt.wantReason) {
+				t.Fatalf("expected reason containing %q, got %q", tt.wantReason, d.Reason)
+			}
+			if tt.wantNote != "" && !strings.Contains(strings.Join(d.Notes, "\n"), tt.wantNote) {
+				t.Fatalf("expected note containing %q, got %v", tt.wantNote, d.Notes)
+			}
+			if tt.budget == nil {
+				if d.Budget != nil {
+					t.Fatalf("expected no budget check, got %+v", d.Budget)
+				}
+				return
+			}
+			if d.Budget == nil || len(d.Budget.Periods) != tt.wantPeriods {
+				t.Fatalf("expected %d budget periods, got %+v", tt.wantPeriods, d.Budget)
+			}
+		})
+	}
+}
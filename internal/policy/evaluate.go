@@ -0,0 +1,264 @@
+// Package policy evaluates spend policies locally, mirroring how the
+// marketplace decides whether a purchase is allowed.
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+// Candidate is a purchase to evaluate. Unknown values are left empty: rules
+// that target an unknown host, asset or network do not match, and limits on
+// an unknown price or score are not checked.
+type Candidate struct {
+	ResourceURL string
+	// Price is in base units of Asset.
+	Price   *big.Int
+	Asset   string
+	Network string
+	// Score is on the policy's 0-100 scale.
+	Score *float64
+	// Identified reports whether the buying agent is identified.
+	Identified bool
+	// Subject is the agent's external key or subject ID, used to check that
+	// the agent is bound to the policy.
+	Subject string
+}
+
+// Host returns the candidate's lower-cased host name. ResourceURL may be a
+// full URL or a bare host.
+func (c Candidate) Host() string {
+	raw := strings.TrimSpace(c.ResourceURL)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "//" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+type Outcome string
+
+const (
+	OutcomeSkipped Outcome = "skipped"
+	OutcomeNoMatch Outcome = "no-match"
+	OutcomeAllow   Outcome = "allow"
+	OutcomeDeny    Outcome = "deny"
+)
+
+// Step records how one rule was evaluated.
+type Step struct {
+	RuleID   string  `json:"ruleId"`
+	Priority int     `json:"priority"`
+	Effect   string  `json:"effect"`
+	Scope    string  `json:"scope"`
+	Outcome  Outcome `json:"outcome"`
+	Detail   string  `json:"detail"`
+}
+
+type Decision struct {
+	Allowed bool `json:"allowed"`
+	// Rule is the rule that decided, or nil for the default deny.
+	Rule   *api.PolicyRule `json:"rule,omitempty"`
+	Reason string          `json:"reason"`
+	Trace  []Step          `json:"trace"`
+	// Notes are observations that do not change the decision.
+	Notes []string `json:"notes,omitempty"`
+	// Budget is the agent budget check, when the agent has a budget.
+	Budget *BudgetCheck `json:"budget,omitempty"`
+}
+
+// Evaluate decides a candidate against a policy. Enabled rules are tried in
+// ascending priority, deny before allow on ties; the first rule whose
+// targets match decides. A matching allow rule still denies when the
+// candidate breaks one of its limits (max price, min score, identified
+// agent). When no rule matches the purchase is denied.
+func Evaluate(details api.PolicyDetailsResponse, c Candidate) Decision {
+	var d Decision
+	if status := strings.TrimSpace(details.Policy.Status); status != "" && status != "active" {
+		d.Notes = append(d.Notes, fmt.Sprintf("policy is %s; the server does not enforce it", status))
+	}
+	if note := subjectNote(details, c.Subject); note != "" {
+		d.Notes = append(d.Notes, note)
+	}
+
+	host := c.Host()
+	for _, rule := range orderedRules(details.Rules) {
+		step := Step{RuleID: rule.ID, Priority: rule.Priority, Effect: rule.Effect, Scope: rule.Scope}
+		if !rule.Enabled {
+			step.Outcome = OutcomeSkipped
+			step.Detail = "rule is disabled"
+			d.Trace = append(d.Trace, step)
+			continue
+		}
+		if mismatch := targetMismatch(rule, host, c); mismatch != "" {
+			step.Outcome = OutcomeNoMatch
+			step.Detail = mismatch
+			d.Trace = append(d.Trace, step)
+			continue
+		}
+
+		matched := rule
+		d.Rule = &matched
+		switch rule.Effect {
+		case "deny":
+			step.Outcome = OutcomeDeny
+			step.Detail = "targets match"
+			d.Reason = fmt.Sprintf("denied by rule %s", ruleLabel(rule))
+		case "allow":
+			violations, notes := checkLimits(rule, c)
+			d.Notes = append(d.Notes, notes...)
+			if len(violations) > 0 {
+				step.Outcome = OutcomeDeny
+				step.Detail = strings.Join(violations, "; ")
+				d.Reason = fmt.Sprintf("rule %s matched but %s", ruleLabel(rule), step.Detail)
+			} else {
+				step.Outcome = OutcomeAllow
+				step.Detail = "targets match and limits pass"
+				d.Allowed = true
+				d.Reason = fmt.Sprintf("allowed by rule %s", ruleLabel(rule))
+			}
+		default:
+			step.Outcome = OutcomeNoMatch
+			step.Detail = fmt.Sprintf("unknown effect %q", rule.Effect)
+			d.Rule = nil
+			d.Trace = append(d.Trace, step)
+			continue
+		}
+		d.Trace = append(d.Trace, step)
+		return d
+	}
+
+	d.Reason = "no rule matched (default deny)"
+	return d
+}
+
+// orderedRules sorts by priority, deny before allow on ties, then by
+// original position.
+func orderedRules(rules []api.PolicyRule) []api.PolicyRule {
+	ordered := make([]api.PolicyRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+		return ordered[i].Effect == "deny" && ordered[j].Effect != "deny"
+	})
+	return ordered
+}
+
+// targetMismatch explains why a rule does not apply, or returns "" when it
+// does. Any host, asset or network set on a rule narrows it, whatever the
+// scope.
+func targetMismatch(rule api.PolicyRule, host string, c Candidate) string {
+	if ruleHost := trimmed(rule.ResourceHost); ruleHost != "" {
+		if host == "" {
+			return fmt.Sprintf("rule targets host %s; candidate host unknown", ruleHost)
+		}
+		if !HostMatches(ruleHost, host) {
+			return fmt.Sprintf("host %s does not match %s", host, ruleHost)
+		}
+	} else if rule.Scope == "host" {
+		return "host rule has no resourceHost"
+	}
+
+	if ruleAsset := trimmed(rule.Asset); ruleAsset != "" {
+		if strings.TrimSpace(c.Asset) == "" {
+			return fmt.Sprintf("rule targets asset %s; candidate asset unknown", ruleAsset)
+		}
+		if !strings.EqualFold(ruleAsset, strings.TrimSpace(c.Asset)) {
+			return fmt.Sprintf("asset %s does not match %s", c.Asset, ruleAsset)
+		}
+	}
+
+	if ruleNetwork := trimmed(rule.Network); ruleNetwork != "" {
+		if strings.TrimSpace(c.Network) == "" {
+			return fmt.Sprintf("rule targets network %s; candidate network unknown", ruleNetwork)
+		}
+		if !strings.EqualFold(ruleNetwork, strings.TrimSpace(c.Network)) {
+			return fmt.Sprintf("network %s does not match %s", c.Network, ruleNetwork)
+		}
+	}
+	return ""
+}
+
+// checkLimits returns the limits the candidate breaks, and notes for limits
+// that could not be checked.
+func checkLimits(rule api.PolicyRule, c Candidate) (violations, notes []string) {
+	if raw := trimmed(rule.MaxPrice); raw != "" {
+		maxPrice, err := money.ParseBaseUnits(raw)
+		switch {
+		case err != nil:
+			notes = append(notes, fmt.Sprintf("rule %s has an invalid maxPrice %q", rule.ID, raw))
+		case c.Price == nil:
+			notes = append(notes, fmt.Sprintf("max price %s not checked: no price given", raw))
+		case c.Price.Cmp(maxPrice) > 0:
+			violations = append(violations, fmt.Sprintf("price %s exceeds max price %s", c.Price, maxPrice))
+		}
+	}
+	if rule.MinScore != nil {
+		switch {
+		case c.Score == nil:
+			notes = append(notes, fmt.Sprintf("min score %d not checked: no score given", *rule.MinScore))
+		case *c.Score < float64(*rule.MinScore):
+			violations = append(violations, fmt.Sprintf("score %s below min score %d", money.FormatFloat(*c.Score), *rule.MinScore))
+		}
+	}
+	if rule.RequireIdentifiedAgent != nil && *rule.RequireIdentifiedAgent && !c.Identified {
+		violations = append(violations, "an identified agent is required")
+	}
+	return violations, notes
+}
+
+// HostMatches reports whether host equals pattern or is a subdomain of it.
+// A leading "*." in pattern is accepted and means the same.
+func HostMatches(pattern, host string) bool {
+	pattern = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(pattern)), "*.")
+	host = strings.ToLower(strings.TrimSpace(host))
+	if pattern == "" || host == "" {
+		return false
+	}
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+func subjectNote(details api.PolicyDetailsResponse, subject string) string {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return ""
+	}
+	for _, binding := range details.SubjectBindings {
+		if trimmed(binding.ExternalKey) != subject && binding.SubjectID != subject {
+			continue
+		}
+		if !binding.Active {
+			return fmt.Sprintf("subject %s is bound to this policy but the binding is inactive", subject)
+		}
+		return ""
+	}
+	return fmt.Sprintf("subject %s is not bound to this policy", subject)
+}
+
+func ruleLabel(rule api.PolicyRule) string {
+	if rule.ID != "" {
+		return rule.ID
+	}
+	return fmt.Sprintf("%s %s (priority %d)", rule.Effect, rule.Scope, rule.Priority)
+}
+
+func trimmed(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
@@ -0,0 +1,309 @@
+package policy
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func strPtr(v string) *string     { return &v }
+func intPtr(v int) *int           { return &v }
+func boolPtr(v bool) *bool        { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func price(v int64) *big.Int { return big.NewInt(v) }
+
+func details(rules ...api.PolicyRule) api.PolicyDetailsResponse {
+	var res api.PolicyDetailsResponse
+	res.Policy = api.PolicyInfo{ID: "pol_1", Name: "Buyer", Status: "active"}
+	res.Rules = rules
+	res.SubjectBindings = []api.PolicySubjectBinding{
+		{SubjectID: "sub_1", ExternalKey: strPtr("agent-1"), Precedence: 1, Active: true},
+		{SubjectID: "sub_2", ExternalKey: strPtr("agent-2"), Precedence: 1, Active: false},
+	}
+	return res
+}
+
+func globalAllow() api.PolicyRule {
+	return api.PolicyRule{
+		ID:       "allow_global",
+		Effect:   "allow",
+		Scope:    "global",
+		MinScore: intPtr(60),
+		MaxPrice: strPtr("500000"),
+		Priority: 100,
+		Enabled:  true,
+	}
+}
+
+func denyHost(host string) api.PolicyRule {
+	return api.PolicyRule{
+		ID:           "deny_" + host,
+		Effect:       "deny",
+		Scope:        "host",
+		ResourceHost: strPtr(host),
+		Priority:     10,
+		Enabled:      true,
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	baseAllow := api.PolicyRule{
+		ID:       "allow_base",
+		Effect:   "allow",
+		Scope:    "network",
+		Network:  strPtr("base"),
+		Asset:    strPtr("USDC"),
+		MaxPrice: strPtr("2000000"),
+		Priority: 50,
+		Enabled:  true,
+	}
+	identified := globalAllow()
+	identified.RequireIdentifiedAgent = boolPtr(true)
+	disabledDeny := denyHost("api.example.com")
+	disabledDeny.Enabled = false
+	tieAllow := api.PolicyRule{ID: "tie_allow", Effect: "allow", Scope: "global", Priority: 10, Enabled: true}
+	assetOnly := api.PolicyRule{ID: "allow_usdc", Effect: "allow", Scope: "asset", Asset: strPtr("usdc"), Priority: 100, Enabled: true}
+
+	tests := []struct {
+		name      string
+		rules     []api.PolicyRule
+		candidate Candidate
+		allowed   bool
+		ruleID    string
+		reason    string
+	}{
+		{
+			name:      "global allow within limits",
+			rules:     []api.PolicyRule{globalAllow()},
+			candidate: Candidate{ResourceURL: "https://api.example.com/v1", Price: price(400000), Score: floatPtr(80)},
+			allowed:   true,
+			ruleID:    "allow_global",
+		},
+		{
+			name:      "price at the limit is allowed",
+			rules:     []api.PolicyRule{globalAllow()},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(500000), Score: floatPtr(60)},
+			allowed:   true,
+			ruleID:    "allow_global",
+		},
+		{
+			name:      "price over the limit",
+			rules:     []api.PolicyRule{globalAllow()},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(500001), Score: floatPtr(80)},
+			ruleID:    "allow_global",
+			reason:    "exceeds max price",
+		},
+		{
+			name:      "score below minimum",
+			rules:     []api.PolicyRule{globalAllow()},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(1), Score: floatPtr(59.5)},
+			ruleID:    "allow_global",
+			reason:    "below min score",
+		},
+		{
+			name:      "unknown price and score are not checked",
+			rules:     []api.PolicyRule{globalAllow()},
+			candidate: Candidate{ResourceURL: "https://api.example.com"},
+			allowed:   true,
+			ruleID:    "allow_global",
+		},
+		{
+			name:      "deny host beats later allow",
+			rules:     []api.PolicyRule{globalAllow(), denyHost("example.com")},
+			candidate: Candidate{ResourceURL: "https://api.example.com/v1", Price: price(1), Score: floatPtr(90)},
+			ruleID:    "deny_example.com",
+			reason:    "denied by rule",
+		},
+		{
+			name:      "deny host does not match other hosts",
+			rules:     []api.PolicyRule{globalAllow(), denyHost("example.com")},
+			candidate: Candidate{ResourceURL: "https://notexample.com", Price: price(1), Score: floatPtr(90)},
+			allowed:   true,
+			ruleID:    "allow_global",
+		},
+		{
+			name:      "bare host candidate",
+			rules:     []api.PolicyRule{denyHost("example.com"), globalAllow()},
+			candidate: Candidate{ResourceURL: "EXAMPLE.com:8443"},
+			ruleID:    "deny_example.com",
+			reason:    "denied by rule",
+		},
+		{
+			name:      "disabled rule is skipped",
+			rules:     []api.PolicyRule{disabledDeny, globalAllow()},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(1), Score: floatPtr(90)},
+			allowed:   true,
+			ruleID:    "allow_global",
+		},
+		{
+			name:      "network rule with higher limit wins by priority",
+			rules:     []api.PolicyRule{globalAllow(), baseAllow},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(1500000), Asset: "USDC", Network: "Base"},
+			allowed:   true,
+			ruleID:    "allow_base",
+		},
+		{
+			name:      "network rule does not match other networks",
+			rules:     []api.PolicyRule{globalAllow(), baseAllow},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(1500000), Asset: "USDC", Network: "polygon"},
+			ruleID:    "allow_global",
+			reason:    "exceeds max price",
+		},
+		{
+			name:      "rule asset must match",
+			rules:     []api.PolicyRule{baseAllow},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(1), Asset: "ETH", Network: "base"},
+			reason:    "default deny",
+		},
+		{
+			name:      "unknown network does not match network rule",
+			rules:     []api.PolicyRule{baseAllow},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Asset: "USDC"},
+			reason:    "default deny",
+		},
+		{
+			name:      "asset comparison ignores case",
+			rules:     []api.PolicyRule{assetOnly},
+			candidate: Candidate{Asset: "USDC"},
+			allowed:   true,
+			ruleID:    "allow_usdc",
+		},
+		{
+			name:      "identified agent required",
+			rules:     []api.PolicyRule{identified},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(1), Score: floatPtr(90)},
+			ruleID:    "allow_global",
+			reason:    "identified agent is required",
+		},
+		{
+			name:      "identified agent provided",
+			rules:     []api.PolicyRule{identified},
+			candidate: Candidate{ResourceURL: "https://api.example.com", Price: price(1), Score: floatPtr(90), Identified: true},
+			allowed:   true,
+			ruleID:    "allow_global",
+		},
+		{
+			name:      "deny wins priority ties",
+			rules:     []api.PolicyRule{tieAllow, denyHost("example.com")},
+			candidate: Candidate{ResourceURL: "https://example.com"},
+			ruleID:    "deny_example.com",
+			reason:    "denied by rule",
+		},
+		{
+			name:      "no rules is default deny",
+			candidate: Candidate{ResourceURL: "https://example.com"},
+			reason:    "default deny",
+		},
+		{
+			name:      "host rule without host never matches",
+			rules:     []api.PolicyRule{{ID: "broken", Effect: "deny", Scope: "host", Priority: 1, Enabled: true}, globalAllow()},
+			candidate: Candidate{ResourceURL: "https://example.com"},
+			allowed:   true,
+			ruleID:    "allow_global",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Evaluate(details(tt.rules...), tt.candidate)
+			if d.Allowed != tt.allowed {
+				t.Fatalf("expected allowed=%t, got %t (%s)", tt.allowed, d.Allowed, d.Reason)
+			}
+			gotRule := ""
+			if d.Rule != nil {
+				gotRule = d.Rule.ID
+			}
+			if gotRule != tt.ruleID {
+				t.Fatalf("expected rule %q, got %q", tt.ruleID, gotRule)
+			}
+			if tt.reason != "" && !strings.Contains(d.Reason, tt.reason) {
+				t.Fatalf("expected reason containing %q, got %q", tt.reason, d.Reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateTrace(t *testing.T) {
+	disabled := denyHost("other.com")
+	disabled.Enabled = false
+	d := Evaluate(
+		details(globalAllow(), denyHost("example.com"), disabled),
+		Candidate{ResourceURL: "https://good.io", Price: price(600000)},
+	)
+
+	want := []struct {
+		ruleID  string
+		outcome Outcome
+	}{
+		{"deny_example.com", OutcomeNoMatch},
+		{"deny_other.com", OutcomeSkipped},
+		{"allow_global", OutcomeDeny},
+	}
+	if len(d.Trace) != len(want) {
+		t.Fatalf("expected %d steps, got %+v", len(want), d.Trace)
+	}
+	for i, w := range want {
+		if d.Trace[i].RuleID != w.ruleID || d.Trace[i].Outcome != w.outcome {
+			t.Fatalf("step %d: expected %s/%s, got %+v", i, w.ruleID, w.outcome, d.Trace[i])
+		}
+	}
+	if len(d.Notes) != 1 || !strings.Contains(d.Notes[0], "no score given") {
+		t.Fatalf("expected unchecked score note, got %v", d.Notes)
+	}
+}
+
+func TestEvaluateNotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		subject string
+		want    string
+	}{
+		{name: "inactive policy", status: "inactive", want: "policy is inactive"},
+		{name: "unbound subject", status: "active", subject: "agent-9", want: "not bound"},
+		{name: "inactive binding", status: "active", subject: "agent-2", want: "binding is inactive"},
+		{name: "bound by subject ID", status: "active", subject: "sub_1"},
+		{name: "bound subject", status: "active", subject: "agent-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := details(api.PolicyRule{ID: "a", Effect: "allow", Scope: "global", Enabled: true})
+			res.Policy.Status = tt.status
+			d := Evaluate(res, Candidate{Subject: tt.subject})
+			if tt.want == "" {
+				if len(d.Notes) != 0 {
+					t.Fatalf("expected no notes, got %v", d.Notes)
+				}
+				return
+			}
+			if len(d.Notes) != 1 || !strings.Contains(d.Notes[0], tt.want) {
+				t.Fatalf("expected note containing %q, got %v", tt.want, d.Notes)
+			}
+		})
+	}
+}
+
+func TestHostMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		host    string
+		want    bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "api.example.com", true},
+		{"*.example.com", "api.example.com", true},
+		{"Example.COM", "example.com", true},
+		{"example.com", "badexample.com", false},
+		{"api.example.com", "example.com", false},
+		{"", "example.com", false},
+		{"example.com", "", false},
+	}
+	for _, tt := range tests {
+		if got := HostMatches(tt.pattern, tt.host); got != tt.want {
+			t.Fatalf("HostMatches(%q, %q) = %t, want %t", tt.pattern, tt.host, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rank orders severities; higher is more severe. Unknown values rank 0.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseSeverity accepts error, warning or info.
+func ParseSeverity(raw string) (Severity, error) {
+	s := Severity(strings.ToLower(strings.TrimSpace(raw)))
+	if s.Rank() == 0 {
+		return "", fmt.Errorf("unknown severity %q (expected error, warning or info)", raw)
+	}
+	return s, nil
+}
+
+// Finding is one lint result. RuleID is empty for policy-wide findings.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	RuleID   string   `json:"ruleId,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Lint analyses a policy's rules and summary for mistakes that evaluation
+// would not report: rules that can never apply, conflicting or redundant
+// rules, allows without limits and inconsistent requirements. Findings are
+// ordered by severity, then by rule evaluation order.
+func Lint(details api.PolicyDetailsResponse) []Finding {
+	ordered := orderedRules(details.Rules)
+	position := make(map[string]int, len(ordered))
+	for i, rule := range ordered {
+		if _, ok := position[rule.ID]; !ok {
+			position[rule.ID] = i
+		}
+	}
+
+	findings := make([]Finding, 0)
+	add := func(severity Severity, code string, rule *api.PolicyRule, format string, args ...any) {
+		f := Finding{Severity: severity, Code: code, Message: fmt.Sprintf(format, args...)}
+		if rule != nil {
+			f.RuleID = rule.ID
+		}
+		findings = append(findings, f)
+	}
+
+	enabled := make([]api.PolicyRule, 0, len(ordered))
+	for _, rule := range ordered {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+		}
+	}
+
+	lintPolicyWide(details, enabled, add)
+	for i := range ordered {
+		lintRule(details, ordered[i], add)
+	}
+	lintShadowing(enabled, add)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity.Rank() != findings[j].Severity.Rank() {
+			return findings[i].Severity.Rank() > findings[j].Severity.Rank()
+		}
+		pi, iok := position[findings[i].RuleID]
+		pj, jok := position[findings[j].RuleID]
+		if findings[i].RuleID == "" || !iok {
+			pi = -1
+		}
+		if findings[j].RuleID == "" || !jok {
+			pj = -1
+		}
+		return pi < pj
+	})
+	return findings
+}
+
+type addFunc func(severity Severity, code string, rule *api.PolicyRule, format string, args ...any)
+
+func lintPolicyWide(details api.PolicyDetailsResponse, enabled []api.PolicyRule, add addFunc) {
+	allows := make([]api.PolicyRule, 0)
+	for _, rule := range enabled {
+		if rule.Effect == "allow" {
+			allows = append(allows, rule)
+		}
+	}
+	if len(allows) == 0 {
+		add(SeverityError, "no-allow-rule", nil, "no enabled allow rule; every purchase is denied")
+	}
+
+	hasGlobalAllow := false
+	for _, rule := range allows {
+		if isGlobalTarget(rule) {
+			hasGlobalAllow = true
+		}
+	}
+	if !hasGlobalAllow {
+		for i := range details.Rules {
+			rule := details.Rules[i]
+			if !rule.Enabled && rule.Effect == "allow" && rule.Scope == "global" {
+				add(
+					SeverityWarning,
+					"disabled-global-allow",
+					&rule,
+					"global allow rule is disabled and no other rule allows purchases everywhere",
+				)
+				break
+			}
+		}
+	}
+
+	// Identified-agent requirements only hold if every allow rule agrees.
+	requiring := make([]string, 0)
+	lax := make([]string, 0)
+	for _, rule := range allows {
+		if rule.RequireIdentifiedAgent != nil && *rule.RequireIdentifiedAgent {
+			requiring = append(requiring, rule.ID)
+		} else {
+			lax = append(lax, rule.ID)
+		}
+	}
+	switch {
+	case len(requiring) > 0 && len(lax) > 0:
+		add(
+			SeverityWarning,
+			"inconsistent-identified-agent",
+			nil,
+			"allow rules %s require an identified agent but %s do not, so unidentified agents can still buy",
+			strings.Join(requiring, ", "),
+			strings.Join(lax, ", "),
+		)
+	case details.Summary.RequireIdentifiedAgent && len(lax) > 0:
+		add(
+			SeverityWarning,
+			"inconsistent-identified-agent",
+			nil,
+			"policy summary requires an identified agent but allow rules %s do not",
+			strings.Join(lax, ", "),
+		)
+	}
+}
+
+func lintRule(details api.PolicyDetailsResponse, rule api.PolicyRule, add addFunc) {
+	if rule.Scope == "host" && trimmed(rule.ResourceHost) == "" {
+		add(SeverityError, "host-rule-without-host", &rule, "host rule has no resourceHost and never matches")
+	}
+	if raw := trimmed(rule.MaxPrice); raw != "" {
+		if _, err := money.ParseBaseUnits(raw); err != nil {
+			add(SeverityError, "invalid-max-price", &rule, "maxPrice %q is not an integer amount of base units", raw)
+		}
+	}
+	if !rule.Enabled {
+		return
+	}
+
+	if rule.Effect == "allow" {
+		limited := trimmed(rule.MaxPrice) != "" || rule.MinScore != nil
+		switch {
+		case isGlobalTarget(rule) && !limited:
+			add(SeverityWarning, "broad-allow", &rule, "allows every purchase with no max price or min score")
+		case trimmed(rule.MaxPrice) == "":
+			add(SeverityWarning, "missing-budget-cap", &rule, "allow rule has no maxPrice, so purchases are not capped")
+		}
+	}
+
+	if trimmed(rule.MaxPrice) != "" {
+		asset := trimmed(rule.Asset)
+		switch {
+		case asset == "":
+			add(SeverityInfo, "max-price-without-asset", &rule, "maxPrice has no asset, so its base units depend on the payment asset")
+		case len(details.Summary.AllowAssets) > 0 && !containsFold(details.Summary.AllowAssets, asset):
+			add(
+				SeverityWarning,
+				"asset-not-allowed",
+				&rule,
+				"maxPrice is set for %s, which is not among the allowed assets (%s)",
+				asset,
+				strings.Join(details.Summary.AllowAssets, ", "),
+			)
+		}
+	}
+}
+
+// lintShadowing reports enabled rules that an earlier rule always matches
+// first, so they never decide.
+func lintShadowing(enabled []api.PolicyRule, add addFunc) {
+	for i := range enabled {
+		later := enabled[i]
+		if later.Scope == "host" && trimmed(later.ResourceHost) == "" {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			earlier := enabled[j]
+			if earlier.Scope == "host" && trimmed(earlier.ResourceHost) == "" {
+				continue
+			}
+			if !covers(earlier, later) {
+				continue
+			}
+			if earlier.Effect != later.Effect {
+				add(
+					SeverityError,
+					"shadowed-conflict",
+					&later,
+					"%s rule never applies: %s rule %s (priority %d) matches first",
+					later.Effect,
+					earlier.Effect,
+					earlier.ID,
+					earlier.Priority,
+				)
+			} else {
+				add(
+					SeverityWarning,
+					"unreachable-rule",
+					&later,
+					"rule never applies: %s rule %s (priority %d) matches the same purchases first",
+					earlier.Effect,
+					earlier.ID,
+					earlier.Priority,
+				)
+			}
+			break
+		}
+	}
+}
+
+// covers reports whether every purchase matching b's targets also matches
+// a's.
+func covers(a, b api.PolicyRule) bool {
+	if host := trimmed(a.ResourceHost); host != "" {
+		if trimmed(b.ResourceHost) == "" || !HostMatches(host, strings.TrimPrefix(trimmed(b.ResourceHost), "*.")) {
+			return false
+		}
+	}
+	if asset := trimmed(a.Asset); asset != "" && !strings.EqualFold(asset, trimmed(b.Asset)) {
+		return false
+	}
+	if network := trimmed(a.Network); network != "" && !strings.EqualFold(network, trimmed(b.Network)) {
+		return false
+	}
+	return true
+}
+
+func isGlobalTarget(rule api.PolicyRule) bool {
+	return trimmed(rule.ResourceHost) == "" && trimmed(rule.Asset) == "" && trimmed(rule.Network) == "" && rule.Scope != "host"
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(strings.TrimSpace(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func findingCodes(findings []Finding) map[string]string {
+	codes := make(map[string]string, len(findings))
+	for _, f := range findings {
+		codes[f.Code+"/"+f.RuleID] = string(f.Severity)
+	}
+	return codes
+}
+
+func TestLint(t *testing.T) {
+	disabledGlobal := globalAllow()
+	disabledGlobal.Enabled = false
+	broad := api.PolicyRule{ID: "broad", Effect: "allow", Scope: "global", Priority: 100, Enabled: true}
+	uncapped := api.PolicyRule{ID: "uncapped", Effect: "allow", Scope: "network", Network: strPtr("base"), MinScore: intPtr(50), Priority: 50, Enabled: true}
+	lateDeny := denyHost("example.com")
+	lateDeny.Priority = 200
+	allowHost := api.PolicyRule{ID: "allow_host", Effect: "allow", Scope: "host", ResourceHost: strPtr("example.com"), MaxPrice: strPtr("1"), Priority: 1, Enabled: true}
+	subdomainDeny := denyHost("api.example.com")
+	subdomainDeny.Priority = 5
+	duplicateAllow := globalAllow()
+	duplicateAllow.ID = "allow_again"
+	duplicateAllow.Priority = 150
+	identified := globalAllow()
+	identified.RequireIdentifiedAgent = boolPtr(true)
+	ethCap := api.PolicyRule{ID: "eth_cap", Effect: "allow", Scope: "asset", Asset: strPtr("ETH"), MaxPrice: strPtr("1000"), Priority: 20, Enabled: true}
+	noAsset := globalAllow()
+	noAsset.ID = "no_asset"
+	badPrice := globalAllow()
+	badPrice.MaxPrice = strPtr("0.5")
+	hostless := api.PolicyRule{ID: "hostless", Effect: "deny", Scope: "host", Priority: 1, Enabled: true}
+
+	tests := []struct {
+		name        string
+		rules       []api.PolicyRule
+		allowAssets []string
+		requireID   bool
+		want        map[string]string
+		absent      []string
+	}{
+		{
+			name:   "clean policy",
+			rules:  []api.PolicyRule{withAsset(globalAllow(), "USDC"), denyHost("example.com")},
+			absent: []string{"no-allow-rule/", "broad-allow/allow_global", "unreachable-rule/deny_example.com"},
+			want:   map[string]string{},
+		},
+		{
+			name:  "no allow rule",
+			rules: []api.PolicyRule{denyHost("example.com")},
+			want:  map[string]string{"no-allow-rule/": "error"},
+		},
+		{
+			name:  "disabled global allow",
+			rules: []api.PolicyRule{disabledGlobal, uncapped},
+			want: map[string]string{
+				"disabled-global-allow/allow_global": "warning",
+				"missing-budget-cap/uncapped":        "warning",
+			},
+		},
+		{
+			name:  "broad allow",
+			rules: []api.PolicyRule{broad},
+			want:  map[string]string{"broad-allow/broad": "warning"},
+		},
+		{
+			name:  "deny below a covering allow",
+			rules: []api.PolicyRule{globalAllow(), lateDeny},
+			want:  map[string]string{"shadowed-conflict/deny_example.com": "error"},
+		},
+		{
+			name:   "subdomain deny below a host allow",
+			rules:  []api.PolicyRule{allowHost, subdomainDeny, globalAllow()},
+			want:   map[string]string{"shadowed-conflict/deny_api.example.com": "error"},
+			absent: []string{"unreachable-rule/allow_global"},
+		},
+		{
+			name:  "redundant allow",
+			rules: []api.PolicyRule{globalAllow(), duplicateAllow},
+			want:  map[string]string{"unreachable-rule/allow_again": "warning"},
+		},
+		{
+			name:  "inconsistent identified agent",
+			rules: []api.PolicyRule{identified, uncapped},
+			want:  map[string]string{"inconsistent-identified-agent/": "warning"},
+		},
+		{
+			name:      "summary requires identified agent",
+			rules:     []api.PolicyRule{globalAllow()},
+			requireID: true,
+			want:      map[string]string{"inconsistent-identified-agent/": "warning"},
+		},
+		{
+			name:        "max price on an asset that is not allowed",
+			rules:       []api.PolicyRule{ethCap, withAsset(globalAllow(), "USDC")},
+			allowAssets: []string{"USDC"},
+			want:        map[string]string{"asset-not-allowed/eth_cap": "warning"},
+		},
+		{
+			name:  "max price without asset",
+			rules: []api.PolicyRule{noAsset},
+			want:  map[string]string{"max-price-without-asset/no_asset": "info"},
+		},
+		{
+			name:  "invalid max price",
+			rules: []api.PolicyRule{badPrice},
+			want:  map[string]string{"invalid-max-price/allow_global": "error"},
+		},
+		{
+			name:   "host rule without host",
+			rules:  []api.PolicyRule{hostless, globalAllow()},
+			want:   map[string]string{"host-rule-without-host/hostless": "error"},
+			absent: []string{"unreachable-rule/allow_global", "shadowed-conflict/allow_global"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := details(tt.rules...)
+			res.Summary.AllowAssets = tt.allowAssets
+			res.Summary.RequireIdentifiedAgent = tt.requireID
+			findings := Lint(res)
+			codes := findingCodes(findings)
+			for key, severity := range tt.want {
+				if codes[key] != severity {
+					t.Fatalf("expected %s with severity %s, got %v", key, severity, findings)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := codes[key]; ok {
+					t.Fatalf("did not expect %s, got %v", key, findings)
+				}
+			}
+		})
+	}
+}
+
+func TestLintOrdersBySeverity(t *testing.T) {
+	noAsset := globalAllow()
+	lateDeny := denyHost("example.com")
+	lateDeny.Priority = 200
+	findings := Lint(details(noAsset, lateDeny))
+	for i := 1; i < len(findings); i++ {
+		if findings[i-1].Severity.Rank() < findings[i].Severity.Rank() {
+			t.Fatalf("findings not ordered by severity: %v", findings)
+		}
+	}
+	if len(findings) == 0 || findings[0].Severity != SeverityError {
+		t.Fatalf("expected an error first, got %v", findings)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	if s, err := ParseSeverity(" Warning "); err != nil || s != SeverityWarning {
+		t.Fatalf("unexpected result: %q, %v", s, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Fatalf("expected error for unknown severity")
+	}
+}
+
+func withAsset(rule api.PolicyRule, asset string) api.PolicyRule {
+	rule.Asset = strPtr(asset)
+	return rule
+}
@@ -0,0 +1,98 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Binding is one policy bound to a subject, as seen from the subject.
+type Binding struct {
+	PolicyID     string
+	PolicyName   string
+	PolicyStatus string
+	Precedence   int
+	Active       bool
+}
+
+// ResolvedBinding is a Binding with its place in the resolution order.
+type ResolvedBinding struct {
+	Binding
+	// Applies is false for bindings that never take part in resolution.
+	Applies bool
+	Reason  string
+}
+
+// Resolution lists a subject's bindings in resolution order. Winner indexes
+// the binding that decides, or is -1 when none applies.
+type Resolution struct {
+	Order  []ResolvedBinding
+	Winner int
+}
+
+// WinningBinding returns the deciding binding, if any.
+func (r Resolution) WinningBinding() (ResolvedBinding, bool) {
+	if r.Winner < 0 || r.Winner >= len(r.Order) {
+		return ResolvedBinding{}, false
+	}
+	return r.Order[r.Winner], true
+}
+
+// Tied reports whether another applicable binding shares the winner's
+// precedence.
+func (r Resolution) Tied() bool {
+	winner, ok := r.WinningBinding()
+	if !ok {
+		return false
+	}
+	for i, b := range r.Order {
+		if i != r.Winner && b.Applies && b.Precedence == winner.Precedence {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveBindings orders bindings the way the server picks a subject's
+// effective policy: the active binding with the lowest precedence on a
+// non-archived policy wins. Ties are broken by policy ID so the result is
+// stable; they are called out because the server may break them differently.
+func ResolveBindings(bindings []Binding) Resolution {
+	order := make([]ResolvedBinding, 0, len(bindings))
+	for _, b := range bindings {
+		order = append(order, ResolvedBinding{Binding: b})
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Precedence != order[j].Precedence {
+			return order[i].Precedence < order[j].Precedence
+		}
+		return order[i].PolicyID < order[j].PolicyID
+	})
+
+	res := Resolution{Order: order, Winner: -1}
+	for i := range order {
+		b := &order[i]
+		switch {
+		case !b.Active:
+			b.Reason = "binding is inactive"
+		case b.PolicyStatus == "archived":
+			b.Reason = "policy is archived"
+		default:
+			b.Applies = true
+		}
+		if !b.Applies {
+			continue
+		}
+		if res.Winner < 0 {
+			res.Winner = i
+			b.Reason = "lowest precedence among active bindings"
+			continue
+		}
+		winner := order[res.Winner]
+		if b.Precedence == winner.Precedence {
+			b.Reason = fmt.Sprintf("tied with %s at precedence %d; ordered by policy ID", winner.PolicyID, b.Precedence)
+			continue
+		}
+		b.Reason = fmt.Sprintf("shadowed by %s (precedence %d < %d)", winner.PolicyID, winner.Precedence, b.Precedence)
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveBindings(t *testing.T) {
+	tests := []struct {
+		name      string
+		bindings  []Binding
+		wantOrder []string
+		wantWin   string
+		wantTied  bool
+		reasons   map[string]string
+	}{
+		{
+			name:     "none",
+			bindings: nil,
+		},
+		{
+			name: "lowest precedence wins",
+			bindings: []Binding{
+				{PolicyID: "pol_b", Precedence: 20, Active: true},
+				{PolicyID: "pol_a", Precedence: 10, Active: true},
+			},
+			wantOrder: []string{"pol_a", "pol_b"},
+			wantWin:   "pol_a",
+			reasons:   map[string]string{"pol_b": "shadowed by pol_a (precedence 10 < 20)"},
+		},
+		{
+			name: "inactive and archived are skipped",
+			bindings: []Binding{
+				{PolicyID: "pol_a", Precedence: 1, Active: false},
+				{PolicyID: "pol_b", Precedence: 2, Active: true, PolicyStatus: "archived"},
+				{PolicyID: "pol_c", Precedence: 3, Active: true, PolicyStatus: "active"},
+			},
+			wantOrder: []string{"pol_a", "pol_b", "pol_c"},
+			wantWin:   "pol_c",
+			reasons: map[string]string{
+				"pol_a": "binding is inactive",
+				"pol_b": "policy is archived",
+			},
+		},
+		{
+			name: "nothing applies",
+			bindings: []Binding{
+				{PolicyID: "pol_a", Precedence: 1, Active: false},
+			},
+			wantOrder: []string{"pol_a"},
+		},
+		{
+			name: "ties ordered by policy ID",
+			bindings: []Binding{
+				{PolicyID: "pol_z", Precedence: 5, Active: true},
+				{PolicyID: "pol_y", Precedence: 5, Active: true},
+			},
+			wantOrder: []string{"pol_y", "pol_z"},
+			wantWin:   "pol_y",
+			wantTied:  true,
+			reasons:   map[string]string{"pol_z": "tied with pol_y"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := ResolveBindings(tt.bindings)
+
+			order := make([]string, 0, len(res.Order))
+			for _, b := range res.Order {
+				order = append(order, b.PolicyID)
+			}
+			if strings.Join(order, ",") != strings.Join(tt.wantOrder, ",") {
+				t.Fatalf("expected order %v, got %v", tt.wantOrder, order)
+			}
+
+			winner, ok := res.WinningBinding()
+			if ok != (tt.wantWin != "") || winner.PolicyID != tt.wantWin {
+				t.Fatalf("expected winner %q, got %q (ok=%t)", tt.wantWin, winner.PolicyID, ok)
+			}
+			if res.Tied() != tt.wantTied {
+				t.Fatalf("expected tied=%t", tt.wantTied)
+			}
+			for _, b := range res.Order {
+				want, ok := tt.reasons[b.PolicyID]
+				if ok && !strings.Contains(b.Reason, want) {
+					t.Fatalf("%s: expected reason containing %q, got %q", b.PolicyID, want, b.Reason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package policyfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ChangeKind string
+
+const (
+	Added    ChangeKind = "add"
+	Removed  ChangeKind = "remove"
+	Modified ChangeKind = "change"
+)
+
+// FieldChange is a single changed value. Empty strings stand for unset.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type RuleChange struct {
+	Kind ChangeKind
+	// Label identifies the rule: its ID when known, otherwise its match key.
+	Label  string
+	Fields []FieldChange
+}
+
+type BindingChange struct {
+	Kind    ChangeKind
+	Subject string
+	Fields  []FieldChange
+}
+
+// Plan lists the differences between two policies.
+type Plan struct {
+	Metadata []FieldChange
+	Summary  []FieldChange
+	Rules    []RuleChange
+	Bindings []BindingChange
+}
+
+// DiffOptions controls how rules are paired between the two sides.
+type DiffOptions struct {
+	// MatchRuleIDs pairs rules by ID first. Disable it when comparing two
+	// different policies, whose rule IDs never overlap.
+	MatchRuleIDs bool
+}
+
+func (p Plan) HasChanges() bool {
+	return len(p.Metadata) > 0 || len(p.Summary) > 0 || len(p.Rules) > 0 || len(p.Bindings) > 0
+}
+
+// Counts returns the number of added, changed and removed items. Summary
+// changes follow from rule changes and are not counted separately.
+func (p Plan) Counts() (added, changed, removed int) {
+	if len(p.Metadata) > 0 {
+		changed++
+	}
+	for _, r := range p.Rules {
+		switch r.Kind {
+		case Added:
+			added++
+		case Removed:
+			removed++
+		default:
+			changed++
+		}
+	}
+	for _, b := range p.Bindings {
+		switch b.Kind {
+		case Added:
+			added++
+		case Removed:
+			removed++
+		default:
+			changed++
+		}
+	}
+	return added, changed, removed
+}
+
+// Diff computes the changes needed to turn current into desired. IDs and
+// versions in metadata are ignored. Bindings are compared only when desired
+// declares them (see Document.Bindings).
+func Diff(current, desired Document, opts DiffOptions) Plan {
+	var plan Plan
+
+	// Omitted description, mode and status are not sent by apply (see
+	// ApplyRequest), so the server keeps them. An empty description is sent
+	// and clears it.
+	desiredMeta := desired.Metadata
+	if desiredMeta.Description == nil {
+		desiredMeta.Description = current.Metadata.Description
+	}
+	if strings.TrimSpace(desiredMeta.Mode) == "" {
+		desiredMeta.Mode = current.Metadata.Mode
+	}
+	if strings.TrimSpace(desiredMeta.Status) == "" {
+		desiredMeta.Status = current.Metadata.Status
+	}
+	plan.Metadata = diffFields([][3]string{
+		{"name", strings.TrimSpace(current.Metadata.Name), strings.TrimSpace(desiredMeta.Name)},
+		{"description", deref(current.Metadata.Description), deref(desiredMeta.Description)},
+		{"mode", current.Metadata.Mode, desiredMeta.Mode},
+		{"status", current.Metadata.Status, desiredMeta.Status},
+	})
+
+	currentSummary := Summarize(current.Rules)
+	desiredSummary := Summarize(desired.Rules)
+	plan.Summary = diffFields([][3]string{
+		{"min_score", currentSummary.MinScore, desiredSummary.MinScore},
+		{"budget_max", currentSummary.BudgetMax, desiredSummary.BudgetMax},
+		{"allow_assets", strings.Join(currentSummary.AllowAssets, ","), strings.Join(desiredSummary.AllowAssets, ",")},
+		{"allow_networks", strings.Join(currentSummary.AllowNetworks, ","), strings.Join(desiredSummary.AllowNetworks, ",")},
+		{"deny_hosts", strings.Join(currentSummary.DenyHosts, ","), strings.Join(desiredSummary.DenyHosts, ",")},
+		{"require_identified_agent", strconv.FormatBool(currentSummary.RequireIdentifiedAgent), strconv.FormatBool(desiredSummary.RequireIdentifiedAgent)},
+	})
+
+	plan.Rules = diffRules(current.Rules, desired.Rules, opts)
+	if desired.Bindings != nil {
+		plan.Bindings = diffBindings(current.Bindings, desired.Bindings)
+	}
+	return plan
+}
+
+func diffRules(current, desired []Rule, opts DiffOptions) []RuleChange {
+	changes := make([]RuleChange, 0)
+	matched := make([]bool, len(current))
+
+	pair := func(desiredRule Rule) int {
+		if opts.MatchRuleIDs && desiredRule.ID != "" {
+			for i, rule := range current {
+				if !matched[i] && rule.ID == desiredRule.ID {
+					return i
+				}
+			}
+		}
+		key := desiredRule.MatchKey()
+		for i, rule := range current {
+			if matched[i] {
+				continue
+			}
+			// An explicit ID that does not exist on the server means a new rule.
+			if opts.MatchRuleIDs && desiredRule.ID != "" && rule.ID != "" {
+				continue
+			}
+			if rule.MatchKey() == key {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, rule := range desired {
+		idx := pair(rule)
+		if idx < 0 {
+			changes = append(changes, RuleChange{Kind: Added, Label: rule.Label(), Fields: ruleFields(Rule{}, rule, true)})
+			continue
+		}
+		matched[idx] = true
+		if fields := ruleFields(current[idx], rule, false); len(fields) > 0 {
+			label := current[idx].Label()
+			changes = append(changes, RuleChange{Kind: Modified, Label: label, Fields: fields})
+		}
+	}
+	for i, rule := range current {
+		if !matched[i] {
+			changes = append(changes, RuleChange{Kind: Removed, Label: rule.Label(), Fields: ruleFields(rule, Rule{}, true)})
+		}
+	}
+	return changes
+}
+
+func ruleFields(from, to Rule, includeUnchanged bool) []FieldChange {
+	enabled := func(r Rule, zero bool) string {
+		if zero {
+			return ""
+		}
+		return strconv.FormatBool(r.IsEnabled())
+	}
+	fromZero := from.Effect == "" && from.Scope == ""
+	toZero := to.Effect == "" && to.Scope == ""
+	fields := [][3]string{
+		{"effect", from.Effect, to.Effect},
+		{"scope", from.Scope, to.Scope},
+		{"resource_host", deref(from.ResourceHost), deref(to.ResourceHost)},
+		{"asset", deref(from.Asset), deref(to.Asset)},
+		{"network", deref(from.Network), deref(to.Network)},
+		{"min_score", intString(from.MinScore), intString(to.MinScore)},
+		{"max_price", deref(from.MaxPrice), deref(to.MaxPrice)},
+		{"require_identified_agent", boolString(from.RequireIdentifiedAgent), boolString(to.RequireIdentifiedAgent)},
+		{"priority", priorityString(from, fromZero), priorityString(to, toZero)},
+		{"enabled", enabled(from, fromZero), enabled(to, toZero)},
+		{"condition_json", jsonString(from.ConditionJSON), jsonString(to.ConditionJSON)},
+	}
+	if !includeUnchanged {
+		return diffFields(fields)
+	}
+	out := make([]FieldChange, 0, len(fields))
+	for _, f := range fields {
+		if f[1] == "" && f[2] == "" {
+			continue
+		}
+		out = append(out, FieldChange{Field: f[0], From: f[1], To: f[2]})
+	}
+	return out
+}
+
+func diffBindings(current, desired []Binding) []BindingChange {
+	changes := make([]BindingChange, 0)
+	currentByKey := make(map[string]Binding, len(current))
+	for _, b := range current {
+		currentByKey[b.subjectLabel()] = b
+	}
+	seen := make(map[string]struct{}, len(desired))
+	for _, b := range desired {
+		key := b.subjectLabel()
+		seen[key] = struct{}{}
+		existing, ok := currentByKey[key]
+		if !ok {
+			changes = append(changes, BindingChange{Kind: Added, Subject: key, Fields: bindingFields(Binding{}, b, true)})
+			continue
+		}
+		if fields := bindingFields(existing, b, false); len(fields) > 0 {
+			changes = append(changes, BindingChange{Kind: Modified, Subject: key, Fields: fields})
+		}
+	}
+	for _, b := range current {
+		if _, ok := seen[b.subjectLabel()]; !ok {
+			changes = append(changes, BindingChange{Kind: Removed, Subject: b.subjectLabel(), Fields: bindingFields(b, Binding{}, true)})
+		}
+	}
+	return changes
+}
+
+func bindingFields(from, to Binding, includeUnchanged bool) []FieldChange {
+	side := func(b Binding) (string, string) {
+		if b.subjectLabel() == "" {
+			return "", ""
+		}
+		return strconv.Itoa(b.Precedence), strconv.FormatBool(b.IsActive())
+	}
+	fromPrecedence, fromActive := side(from)
+	toPrecedence, toActive := side(to)
+	fields := [][3]string{
+		{"precedence", fromPrecedence, toPrecedence},
+		{"active", fromActive, toActive},
+	}
+	if !includeUnchanged {
+		return diffFields(fields)
+	}
+	out := make([]FieldChange, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, FieldChange{Field: f[0], From: f[1], To: f[2]})
+	}
+	return out
+}
+
+func (b Binding) subjectLabel() string {
+	if b.SubjectKey != "" {
+		return b.SubjectKey
+	}
+	if b.SubjectID != "" {
+		return "id:" + b.SubjectID
+	}
+	return ""
+}
+
+// MatchKey identifies a rule by what it targets, independent of its ID.
+func (r Rule) MatchKey() string {
+	return strings.Join([]string{
+		r.Effect,
+		r.Scope,
+		strings.ToLower(deref(r.ResourceHost)),
+		strings.ToUpper(deref(r.Asset)),
+		strings.ToLower(deref(r.Network)),
+	}, "|")
+}
+
+// Label is a short human description of the rule.
+func (r Rule) Label() string {
+	parts := []string{r.Effect, r.Scope}
+	if host := deref(r.ResourceHost); host != "" {
+		parts = append(parts, "host="+host)
+	}
+	if asset := deref(r.Asset); asset != "" {
+		parts = append(parts, "asset="+asset)
+	}
+	if network := deref(r.Network); network != "" {
+		parts = append(parts, "network="+network)
+	}
+	label := strings.Join(parts, " ")
+	if r.ID != "" {
+		return fmt.Sprintf("%s (%s)", r.ID, label)
+	}
+	return label
+}
+
+// DerivedSummary mirrors api.PolicySummary, computed from rules so a local
+// file and a server policy can be compared on equal terms.
+type DerivedSummary struct {
+	MinScore               string
+	BudgetMax              string
+	AllowAssets            []string
+	AllowNetworks          []string
+	DenyHosts              []string
+	RequireIdentifiedAgent bool
+}
+
+// Summarize derives summary values from enabled rules: limits from the
+// highest-priority global allow rule, allowed assets and networks from allow
+// rules, and deny hosts from deny rules.
+func Summarize(rules []Rule) DerivedSummary {
+	var s DerivedSummary
+	assets := make(map[string]struct{})
+	networks := make(map[string]struct{})
+	hosts := make(map[string]struct{})
+	globalFound := false
+
+	ordered := make([]Rule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority < ordered[j].Priority })
+
+	for _, rule := range ordered {
+		if !rule.IsEnabled() {
+			continue
+		}
+		switch rule.Effect {
+		case "allow":
+			if asset := deref(rule.Asset); asset != "" {
+				assets[asset] = struct{}{}
+			}
+			if network := deref(rule.Network); network != "" {
+				networks[network] = struct{}{}
+			}
+			if rule.Scope == "global" && !globalFound {
+				globalFound = true
+				s.MinScore = intString(rule.MinScore)
+				s.BudgetMax = deref(rule.MaxPrice)
+				s.RequireIdentifiedAgent = rule.RequireIdentifiedAgent != nil && *rule.RequireIdentifiedAgent
+			}
+		case "deny":
+			if host := deref(rule.ResourceHost); host != "" {
+				hosts[host] = struct{}{}
+			}
+		}
+	}
+	s.AllowAssets = sortedKeys(assets)
+	s.AllowNetworks = sortedKeys(networks)
+	s.DenyHosts = sortedKeys(hosts)
+	return s
+}
+
+func diffFields(fields [][3]string) []FieldChange {
+	out := make([]FieldChange, 0)
+	for _, f := range fields {
+		if f[1] != f[2] {
+			out = append(out, FieldChange{Field: f[0], From: f[1], To: f[2]})
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func deref(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return strings.TrimSpace(*v)
+}
+
+func intString(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func boolString(v *bool) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v)
+}
+
+func priorityString(r Rule, zero bool) string {
+	if zero {
+		return ""
+	}
+	return strconv.Itoa(r.Priority)
+}
+
+func jsonString(v any) string {
+	if v == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,165 @@
+package policyfile
+
+import (
+	"testing"
+)
+
+func TestDiffNoChanges(t *testing.T) {
+	current := FromDetails(detailsFixture())
+	desired := FromDetails(detailsFixture())
+	desired.Metadata.Version = 7
+
+	plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true})
+	if plan.HasChanges() {
+		t.Fatalf("expected no changes, got %+v", plan)
+	}
+}
+
+func TestDiffRules(t *testing.T) {
+	current := FromDetails(detailsFixture())
+	desired := FromDetails(detailsFixture())
+	desired.Rules[0].MaxPrice = strPtr("600000")
+	desired.Rules = desired.Rules[:1]
+	desired.Rules = append(desired.Rules, Rule{Effect: "allow", Scope: "network", Network: strPtr("base"), Priority: 50})
+
+	plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true})
+	if len(plan.Rules) != 3 {
+		t.Fatalf("expected 3 rule changes, got %+v", plan.Rules)
+	}
+	kinds := map[ChangeKind]RuleChange{}
+	for _, change := range plan.Rules {
+		kinds[change.Kind] = change
+	}
+	modified := kinds[Modified]
+	if len(modified.Fields) != 1 || modified.Fields[0] != (FieldChange{Field: "max_price", From: "500000", To: "600000"}) {
+		t.Fatalf("unexpected modified rule: %+v", modified)
+	}
+	if kinds[Removed].Label != "rule_deny (deny host host=bad.example.com)" {
+		t.Fatalf("unexpected removed rule: %+v", kinds[Removed])
+	}
+	if kinds[Added].Label != "allow network network=base" {
+		t.Fatalf("unexpected added rule: %+v", kinds[Added])
+	}
+
+	added, changed, removed := plan.Counts()
+	if added != 1 || changed != 1 || removed != 1 {
+		t.Fatalf("unexpected counts: %d %d %d", added, changed, removed)
+	}
+
+	foundBudget := false
+	for _, f := range plan.Summary {
+		if f.Field == "budget_max" && f.From == "500000" && f.To == "600000" {
+			foundBudget = true
+		}
+	}
+	if !foundBudget {
+		t.Fatalf("expected budget_max summary change, got %+v", plan.Summary)
+	}
+}
+
+func TestDiffMatchesRulesWithoutIDs(t *testing.T) {
+	current := FromDetails(detailsFixture())
+	desired := FromDetails(detailsFixture())
+	for i := range desired.Rules {
+		desired.Rules[i].ID = ""
+	}
+	desired.Rules[1].Enabled = nil
+
+	plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true})
+	if len(plan.Rules) != 1 || plan.Rules[0].Kind != Modified {
+		t.Fatalf("expected one modified rule, got %+v", plan.Rules)
+	}
+	if got := plan.Rules[0].Fields; len(got) != 1 || got[0].Field != "enabled" || got[0].To != "true" {
+		t.Fatalf("unexpected fields: %+v", got)
+	}
+}
+
+func TestDiffUnknownIDIsAdded(t *testing.T) {
+	current := FromDetails(detailsFixture())
+	desired := FromDetails(detailsFixture())
+	desired.Rules[0].ID = "rule_other"
+
+	plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true})
+	added, _, removed := plan.Counts()
+	if added != 1 || removed != 1 {
+		t.Fatalf("expected rule replaced, got %+v", plan.Rules)
+	}
+
+	// Comparing two policies ignores IDs.
+	plan = Diff(current, desired, DiffOptions{})
+	if len(plan.Rules) != 0 {
+		t.Fatalf("expected rules to match by content, got %+v", plan.Rules)
+	}
+}
+
+func TestDiffMetadata(t *testing.T) {
+	current := FromDetails(detailsFixture())
+	desired := FromDetails(detailsFixture())
+	desired.Metadata.Name = "Renamed"
+	desired.Metadata.Description = nil
+	desired.Metadata.Mode = ""
+
+	plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true})
+	if len(plan.Metadata) != 1 || plan.Metadata[0] != (FieldChange{Field: "name", From: "Buyer", To: "Renamed"}) {
+		t.Fatalf("unexpected metadata changes: %+v", plan.Metadata)
+	}
+}
+
+func TestDiffDescription(t *testing.T) {
+	current := FromDetails(detailsFixture())
+	desired := FromDetails(detailsFixture())
+	desired.Metadata.Description = strPtr("")
+
+	plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true})
+	want := FieldChange{Field: "description", From: "Team buyer policy", To: ""}
+	if len(plan.Metadata) != 1 || plan.Metadata[0] != want {
+		t.Fatalf("expected the description to be cleared, got %+v", plan.Metadata)
+	}
+	if req := desired.ApplyRequest(); req.Description == nil || *req.Description != "" {
+		t.Fatalf("expected apply to send an empty description, got %v", req.Description)
+	}
+}
+
+func TestDiffBindings(t *testing.T) {
+	current := FromDetails(detailsFixture())
+
+	desired := FromDetails(detailsFixture())
+	desired.Bindings = nil
+	if plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true}); len(plan.Bindings) != 0 {
+		t.Fatalf("omitted bindings should be unchanged, got %+v", plan.Bindings)
+	}
+
+	desired.Bindings = []Binding{
+		{SubjectKey: "agent-1", Precedence: 5},
+		{SubjectKey: "agent-3", Precedence: 1},
+	}
+	plan := Diff(current, desired, DiffOptions{MatchRuleIDs: true})
+	want := map[string]ChangeKind{
+		"agent-1":  Modified,
+		"agent-3":  Added,
+		"id:sub_2": Removed,
+	}
+	if len(plan.Bindings) != len(want) {
+		t.Fatalf("unexpected binding changes: %+v", plan.Bindings)
+	}
+	for _, change := range plan.Bindings {
+		if want[change.Subject] != change.Kind {
+			t.Fatalf("unexpected change for %s: %+v", change.Subject, change)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	doc := FromDetails(detailsFixture())
+	s := Summarize(doc.Rules)
+	if s.MinScore != "60" || s.BudgetMax != "500000" {
+		t.Fatalf("unexpected limits: %+v", s)
+	}
+	if len(s.AllowAssets) != 1 || s.AllowAssets[0] != "USDC" {
+		t.Fatalf("unexpected assets: %+v", s.AllowAssets)
+	}
+	// The deny rule is disabled in the fixture.
+	if len(s.DenyHosts) != 0 {
+		t.Fatalf("unexpected deny hosts: %+v", s.DenyHosts)
+	}
+}
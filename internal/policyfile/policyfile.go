@@ -0,0 +1,352 @@
+// Package policyfile converts between server policies and declarative policy
+// files (YAML or JSON) that can be kept in version control.
+package policyfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	APIVersion = "openspend.ai/v1"
+	Kind       = "Policy"
+)
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// Document is the declarative form of a policy.
+type Document struct {
+	APIVersion string   `yaml:"apiVersion" json:"apiVersion"`
+	Kind       string   `yaml:"kind" json:"kind"`
+	Metadata   Metadata `yaml:"metadata" json:"metadata"`
+	Rules      []Rule   `yaml:"rules" json:"rules"`
+	// Bindings is nil when the file has no bindings key, which leaves server
+	// bindings untouched on apply. An empty list removes every binding.
+	Bindings []Binding `yaml:"bindings" json:"bindings"`
+}
+
+type Metadata struct {
+	ID          string  `yaml:"id,omitempty" json:"id,omitempty"`
+	Name        string  `yaml:"name" json:"name"`
+	Description *string `yaml:"description,omitempty" json:"description,omitempty"`
+	Mode        string  `yaml:"mode,omitempty" json:"mode,omitempty"`
+	Status      string  `yaml:"status,omitempty" json:"status,omitempty"`
+	// Version is informational on export and ignored on apply.
+	Version int `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
+type Rule struct {
+	ID                     string  `yaml:"id,omitempty" json:"id,omitempty"`
+	Effect                 string  `yaml:"effect" json:"effect"`
+	Scope                  string  `yaml:"scope" json:"scope"`
+	ResourceHost           *string `yaml:"resourceHost,omitempty" json:"resourceHost,omitempty"`
+	Asset                  *string `yaml:"asset,omitempty" json:"asset,omitempty"`
+	Network                *string `yaml:"network,omitempty" json:"network,omitempty"`
+	MinScore               *int    `yaml:"minScore,omitempty" json:"minScore,omitempty"`
+	MaxPrice               *string `yaml:"maxPrice,omitempty" json:"maxPrice,omitempty"`
+	RequireIdentifiedAgent *bool   `yaml:"requireIdentifiedAgent,omitempty" json:"requireIdentifiedAgent,omitempty"`
+	Priority               int     `yaml:"priority" json:"priority"`
+	// Enabled defaults to true when omitted.
+	Enabled       *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	ConditionJSON any   `yaml:"conditionJson,omitempty" json:"conditionJson,omitempty"`
+}
+
+type Binding struct {
+	SubjectKey string `yaml:"subjectKey,omitempty" json:"subjectKey,omitempty"`
+	SubjectID  string `yaml:"subjectId,omitempty" json:"subjectId,omitempty"`
+	Precedence int    `yaml:"precedence" json:"precedence"`
+	Active     *bool  `yaml:"active,omitempty" json:"active,omitempty"`
+}
+
+// IsEnabled reports the effective enabled flag.
+func (r Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// IsActive reports the effective active flag.
+func (b Binding) IsActive() bool {
+	return b.Active == nil || *b.Active
+}
+
+// FromDetails builds a document from a server policy.
+func FromDetails(res api.PolicyDetailsResponse) Document {
+	doc := Document{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Metadata: Metadata{
+			ID:          res.Policy.ID,
+			Name:        res.Policy.Name,
+			Description: trimmedPtr(res.Policy.Description),
+			Mode:        res.Policy.Mode,
+			Status:      res.Policy.Status,
+			Version:     res.Policy.Version,
+		},
+		Rules:    make([]Rule, 0, len(res.Rules)),
+		Bindings: make([]Binding, 0, len(res.SubjectBindings)),
+	}
+	for _, rule := range res.Rules {
+		enabled := rule.Enabled
+		doc.Rules = append(doc.Rules, Rule{
+			ID:                     rule.ID,
+			Effect:                 rule.Effect,
+			Scope:                  rule.Scope,
+			ResourceHost:           trimmedPtr(rule.ResourceHost),
+			Asset:                  trimmedPtr(rule.Asset),
+			Network:                trimmedPtr(rule.Network),
+			MinScore:               rule.MinScore,
+			MaxPrice:               trimmedPtr(rule.MaxPrice),
+			RequireIdentifiedAgent: rule.RequireIdentifiedAgent,
+			Priority:               rule.Priority,
+			Enabled:                &enabled,
+			ConditionJSON:          rule.ConditionJSON,
+		})
+	}
+	for _, binding := range res.SubjectBindings {
+		active := binding.Active
+		b := Binding{Precedence: binding.Precedence, Active: &active}
+		if key := trimmedPtr(binding.ExternalKey); key != nil {
+			b.SubjectKey = *key
+		} else {
+			b.SubjectID = binding.SubjectID
+		}
+		doc.Bindings = append(doc.Bindings, b)
+	}
+	return doc
+}
+
+// ToDetails converts a document into the response shape so local files can
+// be inspected with the same tools as server policies. Summary is left empty.
+func (d Document) ToDetails() api.PolicyDetailsResponse {
+	var res api.PolicyDetailsResponse
+	res.Policy = api.PolicyInfo{
+		ID:          d.Metadata.ID,
+		Name:        d.Metadata.Name,
+		Description: d.Metadata.Description,
+		Mode:        d.Metadata.Mode,
+		Status:      d.Metadata.Status,
+		Version:     d.Metadata.Version,
+	}
+	for _, rule := range d.Rules {
+		res.Rules = append(res.Rules, api.PolicyRule{
+			ID:                     rule.ID,
+			Effect:                 rule.Effect,
+			Scope:                  rule.Scope,
+			ResourceHost:           rule.ResourceHost,
+			Asset:                  rule.Asset,
+			Network:                rule.Network,
+			MinScore:               rule.MinScore,
+			MaxPrice:               rule.MaxPrice,
+			RequireIdentifiedAgent: rule.RequireIdentifiedAgent,
+			Priority:               rule.Priority,
+			Enabled:                rule.IsEnabled(),
+			ConditionJSON:          rule.ConditionJSON,
+		})
+	}
+	for _, binding := range d.Bindings {
+		b := api.PolicySubjectBinding{
+			SubjectID:  binding.SubjectID,
+			Precedence: binding.Precedence,
+			Active:     binding.IsActive(),
+		}
+		if binding.SubjectKey != "" {
+			key := binding.SubjectKey
+			b.ExternalKey = &key
+		}
+		res.SubjectBindings = append(res.SubjectBindings, b)
+	}
+	return res
+}
+
+// ApplyRequest converts the document into an API apply payload.
+func (d Document) ApplyRequest() api.ApplyPolicyRequest {
+	req := api.ApplyPolicyRequest{
+		Name:        strings.TrimSpace(d.Metadata.Name),
+		Description: d.Metadata.Description,
+		Mode:        d.Metadata.Mode,
+		Status:      d.Metadata.Status,
+		Rules:       make([]api.PolicyRuleInput, 0, len(d.Rules)),
+	}
+	for _, rule := range d.Rules {
+		req.Rules = append(req.Rules, rule.Input())
+	}
+	if d.Bindings != nil {
+		bindings := make([]api.PolicyBindingInput, 0, len(d.Bindings))
+		for _, binding := range d.Bindings {
+			bindings = append(bindings, api.PolicyBindingInput{
+				SubjectID:          binding.SubjectID,
+				SubjectExternalKey: binding.SubjectKey,
+				Precedence:         binding.Precedence,
+				Active:             binding.IsActive(),
+			})
+		}
+		req.SubjectBindings = &bindings
+	}
+	return req
+}
+
+// Input converts the rule into an API rule payload.
+func (r Rule) Input() api.PolicyRuleInput {
+	return api.PolicyRuleInput{
+		ID:                     r.ID,
+		Effect:                 r.Effect,
+		Scope:                  r.Scope,
+		ResourceHost:           r.ResourceHost,
+		Asset:                  r.Asset,
+		Network:                r.Network,
+		MinScore:               r.MinScore,
+		MaxPrice:               r.MaxPrice,
+		RequireIdentifiedAgent: r.RequireIdentifiedAgent,
+		Priority:               r.Priority,
+		Enabled:                r.IsEnabled(),
+		ConditionJSON:          r.ConditionJSON,
+	}
+}
+
+// Validate checks the document for values the server would reject.
+func (d Document) Validate() error {
+	if d.APIVersion != "" && d.APIVersion != APIVersion {
+		return fmt.Errorf("unsupported apiVersion %q (expected %s)", d.APIVersion, APIVersion)
+	}
+	if d.Kind != "" && d.Kind != Kind {
+		return fmt.Errorf("unsupported kind %q (expected %s)", d.Kind, Kind)
+	}
+	if strings.TrimSpace(d.Metadata.Name) == "" {
+		return errors.New("metadata.name is required")
+	}
+	switch d.Metadata.Mode {
+	case "", "buy", "sell", "both":
+	default:
+		return fmt.Errorf("metadata.mode must be one of: buy, sell, both")
+	}
+	switch d.Metadata.Status {
+	case "", "active", "inactive", "archived":
+	default:
+		return fmt.Errorf("metadata.status must be one of: active, inactive, archived")
+	}
+
+	ruleIDs := make(map[string]struct{})
+	for i, rule := range d.Rules {
+		switch rule.Effect {
+		case "allow", "deny":
+		default:
+			return fmt.Errorf("rules[%d].effect must be one of: allow, deny", i)
+		}
+		if strings.TrimSpace(rule.Scope) == "" {
+			return fmt.Errorf("rules[%d].scope is required", i)
+		}
+		if rule.MinScore != nil && *rule.MinScore < 0 {
+			return fmt.Errorf("rules[%d].minScore must be non-negative", i)
+		}
+		if rule.MaxPrice != nil {
+			if _, err := money.ParseBaseUnits(*rule.MaxPrice); err != nil {
+				return fmt.Errorf("rules[%d].maxPrice must be a non-negative integer in base units", i)
+			}
+		}
+		if rule.ID != "" {
+			if _, dup := ruleIDs[rule.ID]; dup {
+				return fmt.Errorf("rules[%d].id %q is duplicated", i, rule.ID)
+			}
+			ruleIDs[rule.ID] = struct{}{}
+		}
+	}
+
+	subjects := make(map[string]struct{})
+	for i, binding := range d.Bindings {
+		key := binding.SubjectKey
+		if key == "" {
+			key = "id:" + binding.SubjectID
+		}
+		if binding.SubjectKey == "" && binding.SubjectID == "" {
+			return fmt.Errorf("bindings[%d] requires subjectKey or subjectId", i)
+		}
+		if _, dup := subjects[key]; dup {
+			return fmt.Errorf("bindings[%d] binds the same subject twice", i)
+		}
+		subjects[key] = struct{}{}
+	}
+	return nil
+}
+
+// FormatFromPath picks the file format from its extension (YAML by default).
+func FormatFromPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// Load reads and validates a policy file.
+func Load(path string) (Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Document{}, err
+	}
+	doc, err := Decode(data, FormatFromPath(path))
+	if err != nil {
+		return Document{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// Decode parses and validates a policy document.
+func Decode(data []byte, format Format) (Document, error) {
+	var doc Document
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return Document{}, err
+		}
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+			return Document{}, err
+		}
+	}
+	if err := doc.Validate(); err != nil {
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+// Encode renders doc in format.
+func Encode(w io.Writer, doc Document, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	default:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+}
+
+func trimmedPtr(value *string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
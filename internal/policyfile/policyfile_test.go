@@ -0,0 +1,185 @@
+package policyfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func strPtr(v string) *string { return &v }
+func intPtr(v int) *int       { return &v }
+
+func detailsFixture() api.PolicyDetailsResponse {
+	var res api.PolicyDetailsResponse
+	res.Policy = api.PolicyInfo{
+		ID:          "pol_1",
+		Name:        "Buyer",
+		Description: strPtr("Team buyer policy"),
+		Mode:        "buy",
+		Status:      "active",
+		Version:     3,
+	}
+	res.Rules = []api.PolicyRule{
+		{
+			ID:            "rule_allow",
+			Effect:        "allow",
+			Scope:         "global",
+			Asset:         strPtr("USDC"),
+			MinScore:      intPtr(60),
+			MaxPrice:      strPtr("500000"),
+			Priority:      100,
+			Enabled:       true,
+			ConditionJSON: map[string]any{"hours": []any{"09-17"}},
+		},
+		{
+			ID:           "rule_deny",
+			Effect:       "deny",
+			Scope:        "host",
+			ResourceHost: strPtr("bad.example.com"),
+			Priority:     10,
+			Enabled:      false,
+		},
+	}
+	res.SubjectBindings = []api.PolicySubjectBinding{
+		{SubjectID: "sub_1", ExternalKey: strPtr("agent-1"), Precedence: 1, Active: true},
+		{SubjectID: "sub_2", Precedence: 2, Active: false},
+	}
+	return res
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatYAML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			doc := FromDetails(detailsFixture())
+			var buf bytes.Buffer
+			if err := Encode(&buf, doc, format); err != nil {
+				t.Fatalf("encode failed: %v", err)
+			}
+			got, err := Decode(buf.Bytes(), format)
+			if err != nil {
+				t.Fatalf("decode failed: %v\n%s", err, buf.String())
+			}
+			if !reflect.DeepEqual(got.ApplyRequest(), doc.ApplyRequest()) {
+				t.Fatalf("round trip mismatch\n got: %+v\nwant: %+v", got.ApplyRequest(), doc.ApplyRequest())
+			}
+		})
+	}
+}
+
+func TestFromDetails_Bindings(t *testing.T) {
+	doc := FromDetails(detailsFixture())
+	if doc.Bindings[0].SubjectKey != "agent-1" || doc.Bindings[0].SubjectID != "" {
+		t.Fatalf("expected keyed binding, got %+v", doc.Bindings[0])
+	}
+	if doc.Bindings[1].SubjectID != "sub_2" || doc.Bindings[1].IsActive() {
+		t.Fatalf("expected inactive binding by subject ID, got %+v", doc.Bindings[1])
+	}
+}
+
+func TestDecode_Defaults(t *testing.T) {
+	doc, err := Decode([]byte(`
+apiVersion: openspend.ai/v1
+kind: Policy
+metadata:
+  id: pol_1
+  name: Buyer
+rules:
+  - effect: allow
+    scope: global
+    priority: 100
+`), FormatYAML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := doc.ApplyRequest()
+	if !req.Rules[0].Enabled {
+		t.Fatalf("expected omitted enabled to default to true")
+	}
+	if req.SubjectBindings != nil {
+		t.Fatalf("expected missing bindings key to leave bindings untouched")
+	}
+
+	doc, err = Decode([]byte("metadata:\n  name: Buyer\nbindings: []\n"), FormatYAML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req = doc.ApplyRequest()
+	if req.SubjectBindings == nil || len(*req.SubjectBindings) != 0 {
+		t.Fatalf("expected empty bindings list to clear bindings, got %v", req.SubjectBindings)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "missing name", body: "metadata: {}\n", wantErr: "metadata.name is required"},
+		{name: "unknown field", body: "metadata:\n  name: A\n  color: red\n", wantErr: "field color not found"},
+		{name: "bad version", body: "apiVersion: v2\nmetadata:\n  name: A\n", wantErr: "unsupported apiVersion"},
+		{name: "bad mode", body: "metadata:\n  name: A\n  mode: rent\n", wantErr: "metadata.mode"},
+		{name: "bad effect", body: "metadata:\n  name: A\nrules:\n  - effect: maybe\n    scope: global\n", wantErr: "rules[0].effect"},
+		{name: "missing scope", body: "metadata:\n  name: A\nrules:\n  - effect: allow\n", wantErr: "rules[0].scope"},
+		{name: "bad max price", body: "metadata:\n  name: A\nrules:\n  - effect: allow\n    scope: global\n    maxPrice: \"0.5\"\n", wantErr: "maxPrice"},
+		{name: "duplicate rule id", body: "metadata:\n  name: A\nrules:\n  - {id: r, effect: allow, scope: global}\n  - {id: r, effect: deny, scope: global}\n", wantErr: "duplicated"},
+		{name: "binding without subject", body: "metadata:\n  name: A\nbindings:\n  - precedence: 1\n", wantErr: "subjectKey or subjectId"},
+		{name: "duplicate binding", body: "metadata:\n  name: A\nbindings:\n  - subjectKey: a\n  - subjectKey: a\n", wantErr: "same subject twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.body), FormatYAML)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestToDetails(t *testing.T) {
+	doc := FromDetails(detailsFixture())
+	res := doc.ToDetails()
+	if res.Policy.ID != "pol_1" || len(res.Rules) != 2 || len(res.SubjectBindings) != 2 {
+		t.Fatalf("unexpected details: %+v", res)
+	}
+	if res.Rules[1].Enabled {
+		t.Fatalf("expected disabled rule to stay disabled")
+	}
+	if *res.SubjectBindings[0].ExternalKey != "agent-1" {
+		t.Fatalf("expected external key on binding")
+	}
+}
+
+func TestApplyRequestDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description *string
+		want        string
+	}{
+		{name: "omitted is left out", description: nil, want: ""},
+		{name: "empty clears", description: strPtr(""), want: `"description":""`},
+		{name: "set", description: strPtr("Team"), want: `"description":"Team"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := FromDetails(detailsFixture())
+			doc.Metadata.Description = tt.description
+			payload, err := json.Marshal(doc.ApplyRequest())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := string(payload)
+			if tt.want == "" && strings.Contains(got, `"description"`) {
+				t.Fatalf("expected no description, got %s", got)
+			}
+			if tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Fatalf("expected %s in %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Package policytemplate provides built-in and user-defined starting points
+// for new policies.
+package policytemplate
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/policyfile"
+	"gopkg.in/yaml.v3"
+)
+
+const Kind = "PolicyTemplate"
+
+// SourceBuiltin marks templates shipped with the CLI. User templates carry
+// their file path as the source.
+const SourceBuiltin = "builtin"
+
+//go:embed templates/*.yaml
+var builtinFS embed.FS
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+type Template struct {
+	Name        string
+	Version     int
+	Description string
+	Source      string
+	Policy      policyfile.Document
+}
+
+type file struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Template   struct {
+		Name        string `yaml:"name"`
+		Version     int    `yaml:"version"`
+		Description string `yaml:"description"`
+	} `yaml:"template"`
+	Policy policyfile.Document `yaml:"policy"`
+}
+
+// Decode parses and validates a template file (YAML or JSON).
+func Decode(data []byte) (Template, error) {
+	var f file
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return Template{}, err
+	}
+	if f.APIVersion != "" && f.APIVersion != policyfile.APIVersion {
+		return Template{}, fmt.Errorf("unsupported apiVersion %q (expected %s)", f.APIVersion, policyfile.APIVersion)
+	}
+	if f.Kind != Kind {
+		return Template{}, fmt.Errorf("kind must be %s", Kind)
+	}
+	if !namePattern.MatchString(f.Template.Name) {
+		return Template{}, fmt.Errorf("template.name must be lowercase letters, digits and dashes")
+	}
+	if f.Template.Version <= 0 {
+		return Template{}, fmt.Errorf("template.version must be positive")
+	}
+
+	doc := f.Policy
+	doc.APIVersion = policyfile.APIVersion
+	doc.Kind = policyfile.Kind
+	if strings.TrimSpace(doc.Metadata.ID) != "" {
+		return Template{}, fmt.Errorf("policy.metadata.id must not be set in a template")
+	}
+	if err := doc.Validate(); err != nil {
+		return Template{}, fmt.Errorf("policy: %w", err)
+	}
+	return Template{
+		Name:        f.Template.Name,
+		Version:     f.Template.Version,
+		Description: strings.TrimSpace(f.Template.Description),
+		Policy:      doc,
+	}, nil
+}
+
+// Builtin returns the templates shipped with the CLI.
+func Builtin() ([]Template, error) {
+	entries, err := builtinFS.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Template, 0, len(entries))
+	for _, entry := range entries {
+		data, err := builtinFS.ReadFile(path.Join("templates", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("builtin template %s: %w", entry.Name(), err)
+		}
+		tmpl.Source = SourceBuiltin
+		out = append(out, tmpl)
+	}
+	return out, nil
+}
+
+// LoadDir reads user templates (.yaml, .yml or .json) from dir. A missing
+// directory yields no templates.
+func LoadDir(dir string) ([]Template, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	out := make([]Template, 0, len(entries))
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		filePath := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filePath, err)
+		}
+		tmpl.Source = filePath
+		out = append(out, tmpl)
+	}
+	return out, nil
+}
+
+// List returns built-in and user templates sorted by name. A user template
+// replaces a built-in one with the same name.
+func List(dir string) ([]Template, error) {
+	builtin, err := Builtin()
+	if err != nil {
+		return nil, err
+	}
+	user, err := LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]Template, len(builtin)+len(user))
+	for _, tmpl := range builtin {
+		byName[tmpl.Name] = tmpl
+	}
+	for _, tmpl := range user {
+		if existing, ok := byName[tmpl.Name]; ok && existing.Source != SourceBuiltin {
+			return nil, fmt.Errorf("template %q is defined in both %s and %s", tmpl.Name, existing.Source, tmpl.Source)
+		}
+		byName[tmpl.Name] = tmpl
+	}
+
+	out := make([]Template, 0, len(byName))
+	for _, tmpl := range byName {
+		out = append(out, tmpl)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Find returns the named template from List.
+func Find(dir, name string) (Template, error) {
+	templates, err := List(dir)
+	if err != nil {
+		return Template{}, err
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	names := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			return tmpl, nil
+		}
+		names = append(names, tmpl.Name)
+	}
+	return Template{}, fmt.Errorf("unknown policy template %q (available: %s)", name, strings.Join(names, ", "))
+}
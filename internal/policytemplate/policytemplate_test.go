@@ -0,0 +1,146 @@
+package policytemplate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltin(t *testing.T) {
+	templates, err := Builtin()
+	if err != nil {
+		t.Fatalf("builtin templates failed: %v", err)
+	}
+	modes := map[string]string{}
+	for _, tmpl := range templates {
+		modes[tmpl.Name] = tmpl.Policy.Metadata.Mode
+		if tmpl.Source != SourceBuiltin {
+			t.Fatalf("unexpected source %q for %s", tmpl.Source, tmpl.Name)
+		}
+	}
+	want := map[string]string{
+		"strict-buyer":     "buy",
+		"research-sandbox": "buy",
+		"seller":           "sell",
+		"both":             "both",
+	}
+	for name, mode := range want {
+		if modes[name] != mode {
+			t.Fatalf("expected template %s with mode %s, got %q", name, mode, modes[name])
+		}
+	}
+}
+
+const userTemplate = `apiVersion: openspend.ai/v1
+kind: PolicyTemplate
+template:
+  name: %s
+  version: 2
+  description: Team default
+policy:
+  metadata:
+    name: Team Policy
+    mode: buy
+  rules:
+    - effect: allow
+      scope: global
+      maxPrice: "250000"
+      priority: 100
+`
+
+func writeTemplate(t *testing.T, dir, file, name string) {
+	t.Helper()
+	content := fmt.Sprintf(userTemplate, name)
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func TestListMergesUserTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "team.yaml", "team-buyer")
+	writeTemplate(t, dir, "seller.yml", "seller")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	templates, err := List(dir)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(templates) != 5 {
+		t.Fatalf("expected 5 templates, got %d", len(templates))
+	}
+	for i := 1; i < len(templates); i++ {
+		if templates[i-1].Name > templates[i].Name {
+			t.Fatalf("templates not sorted: %s before %s", templates[i-1].Name, templates[i].Name)
+		}
+	}
+
+	seller, err := Find(dir, "Seller")
+	if err != nil {
+		t.Fatalf("find failed: %v", err)
+	}
+	if seller.Version != 2 || seller.Source != filepath.Join(dir, "seller.yml") {
+		t.Fatalf("expected user template to replace builtin, got %+v", seller)
+	}
+}
+
+func TestListRejectsDuplicateUserTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "a.yaml", "team")
+	writeTemplate(t, dir, "b.yaml", "team")
+	if _, err := List(dir); err == nil || !strings.Contains(err.Error(), "defined in both") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+}
+
+func TestListMissingDir(t *testing.T) {
+	templates, err := List(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(templates) != 4 {
+		t.Fatalf("expected builtin templates only, got %d", len(templates))
+	}
+}
+
+func TestFindUnknown(t *testing.T) {
+	_, err := Find("", "nope")
+	if err == nil || !strings.Contains(err.Error(), "available: both, research-sandbox, seller, strict-buyer") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "wrong kind", data: "kind: Policy\n", wantErr: "kind must be PolicyTemplate"},
+		{name: "bad name", data: "kind: PolicyTemplate\ntemplate: {name: Bad Name, version: 1}\n", wantErr: "template.name"},
+		{name: "missing version", data: "kind: PolicyTemplate\ntemplate: {name: ok}\n", wantErr: "template.version"},
+		{name: "unknown field", data: "kind: PolicyTemplate\nextra: true\n", wantErr: "extra"},
+		{
+			name:    "policy id set",
+			data:    "kind: PolicyTemplate\ntemplate: {name: ok, version: 1}\npolicy: {metadata: {id: pol_1, name: x}}\n",
+			wantErr: "metadata.id",
+		},
+		{
+			name:    "invalid policy",
+			data:    "kind: PolicyTemplate\ntemplate: {name: ok, version: 1}\npolicy: {metadata: {name: x, mode: trade}}\n",
+			wantErr: "metadata.mode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
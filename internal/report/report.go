@@ -0,0 +1,141 @@
+// Package report renders search results into self-contained procurement
+// reports in Markdown or HTML.
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// Filter is a single applied search filter, rendered as name=value.
+type Filter struct {
+	Name  string
+	Value string
+}
+
+// Compliance is the policy verdict for one result row.
+type Compliance struct {
+	Allowed bool
+	Reasons []string
+}
+
+type Row struct {
+	Rank       int
+	Item       api.SearchResultItem
+	RankScore  *float64
+	Compliance *Compliance
+}
+
+type PolicyRef struct {
+	ID   string
+	Name string
+}
+
+// Data is the value passed to report templates.
+type Data struct {
+	Query       string
+	Filters     []Filter
+	GeneratedAt time.Time
+	Policy      *PolicyRef
+	Rows        []Row
+}
+
+// FormatFromPath picks the report format from the output file extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return FormatMarkdown, nil
+	case ".html", ".htm":
+		return FormatHTML, nil
+	default:
+		return "", fmt.Errorf("unsupported report extension %q (use .md or .html)", filepath.Ext(path))
+	}
+}
+
+// Render writes data to w in the given format. When templatePath is set, that
+// file replaces the built-in template for the format.
+func Render(w io.Writer, format Format, templatePath string, data Data) error {
+	name := "templates/report.md.tmpl"
+	if format == FormatHTML {
+		name = "templates/report.html.tmpl"
+	}
+
+	var source []byte
+	var err error
+	if strings.TrimSpace(templatePath) != "" {
+		source, err = os.ReadFile(templatePath)
+	} else {
+		source, err = templateFS.ReadFile(name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read report template: %w", err)
+	}
+
+	switch format {
+	case FormatMarkdown:
+		tmpl, err := texttemplate.New("report").Funcs(texttemplate.FuncMap(funcs())).Parse(string(source))
+		if err != nil {
+			return fmt.Errorf("failed to parse report template: %w", err)
+		}
+		return tmpl.Execute(w, data)
+	case FormatHTML:
+		tmpl, err := htmltemplate.New("report").Funcs(htmltemplate.FuncMap(funcs())).Parse(string(source))
+		if err != nil {
+			return fmt.Errorf("failed to parse report template: %w", err)
+		}
+		return tmpl.Execute(w, data)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+func funcs() map[string]any {
+	return map[string]any{
+		"md":       markdownCell,
+		"join":     strings.Join,
+		"title":    originTitle,
+		"price":    formatPrice,
+		"score":    func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) },
+		"deref":    func(v *float64) float64 { return *v },
+		"datetime": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+	}
+}
+
+// markdownCell escapes text so it stays inside a single table cell.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	value = strings.ReplaceAll(value, "\r", " ")
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+func originTitle(item api.SearchResultItem) string {
+	if item.Origin.Title != nil && strings.TrimSpace(*item.Origin.Title) != "" {
+		return strings.TrimSpace(*item.Origin.Title)
+	}
+	return strings.TrimSpace(item.Origin.URL)
+}
+
+func formatPrice(item api.SearchResultItem) string {
+	return strings.TrimSpace(money.FormatFloat(item.MinPrice) + " " + item.Asset)
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func reportFixture() Data {
+	title := "Example | Images"
+	item := api.SearchResultItem{
+		ResourceURL: "https://api.example.com/generate",
+		Type:        "http",
+		Networks:    []string{"base", "polygon"},
+		Description: "Image <generation>",
+		MinPrice:    0.25,
+		Asset:       "USDC",
+		Score:       0.9,
+	}
+	item.Origin.URL = "https://example.com"
+	item.Origin.Title = &title
+	rank := 0.75
+	return Data{
+		Query:       "image generation",
+		Filters:     []Filter{{Name: "networks", Value: "base"}},
+		GeneratedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Policy:      &PolicyRef{ID: "pol_1", Name: "Buyer"},
+		Rows: []Row{
+			{Rank: 1, Item: item, RankScore: &rank, Compliance: &Compliance{Allowed: true}},
+			{Rank: 2, Item: item, RankScore: &rank, Compliance: &Compliance{Reasons: []string{"asset USDC not allowed"}}},
+		},
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"report.md":   FormatMarkdown,
+		"REPORT.HTML": FormatHTML,
+		"out/r.htm":   FormatHTML,
+	}
+	for path, want := range tests {
+		got, err := FormatFromPath(path)
+		if err != nil || got != want {
+			t.Fatalf("%s: expected %q, got %q (err=%v)", path, want, got, err)
+		}
+	}
+	if _, err := FormatFromPath("report.pdf"); err == nil {
+		t.Fatalf("expected error for unsupported extension")
+	}
+}
+
+func TestRender_Markdown(t *testing.T) {
+	var out bytes.Buffer
+	if err := Render(&out, FormatMarkdown, "", reportFixture()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"# Procurement report: image generation",
+		"Generated at 2026-01-02T03:04:05Z.",
+		"Policy: Buyer (`pol_1`)",
+		"- networks: base",
+		"| Rank |",
+		"| 1 | [https://api.example.com/generate](https://api.example.com/generate)",
+		"0.25 USDC",
+		`[Example \| Images](https://example.com)`,
+		"| allowed |",
+		"| denied: asset USDC not allowed |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRender_HTMLEscapes(t *testing.T) {
+	var out bytes.Buffer
+	if err := Render(&out, FormatHTML, "", reportFixture()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Image &lt;generation&gt;") {
+		t.Fatalf("expected escaped description, got:\n%s", got)
+	}
+	if !strings.Contains(got, `<span class="denied">denied: asset USDC not allowed</span>`) {
+		t.Fatalf("expected compliance column, got:\n%s", got)
+	}
+}
+
+func TestRender_TemplateOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.tmpl")
+	if err := os.WriteFile(path, []byte("{{ .Query }}: {{ len .Rows }} rows"), 0o600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	var out bytes.Buffer
+	if err := Render(&out, FormatMarkdown, path, reportFixture()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "image generation: 2 rows" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
@@ -0,0 +1,298 @@
+// Package search implements client-side helpers for marketplace search:
+// parsing the inline query DSL into api.SearchRequest filters.
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+// Query is the result of parsing a search string with inline qualifiers.
+type Query struct {
+	Request api.SearchRequest
+	// Types filters results by SearchResultItem.Type. The search API has no
+	// type parameter, so this filter is applied client-side.
+	Types []string
+	// BudgetUnit is the unit written after the price qualifier (for example
+	// "USD" in price:<5USD). Callers convert Request.BudgetMax when it differs
+	// from the budget asset.
+	BudgetUnit string
+}
+
+type qualifierHandler func(q *Query, key, value string) error
+
+var qualifierAliases = map[string]string{
+	"network":  "network",
+	"networks": "network",
+	"price":    "price",
+	"budget":   "price",
+	"asset":    "asset",
+	"provider": "provider",
+	"service":  "service",
+	"payment":  "payment",
+	"type":     "type",
+	"limit":    "limit",
+}
+
+var qualifierHandlers = map[string]qualifierHandler{
+	"network":  parseNetworkQualifier,
+	"price":    parsePriceQualifier,
+	"asset":    parseAssetQualifier,
+	"provider": scoreQualifier(func(r *api.SearchRequest) **float64 { return &r.MinProviderScore }),
+	"service":  scoreQualifier(func(r *api.SearchRequest) **float64 { return &r.MinServiceScore }),
+	"payment":  scoreQualifier(func(r *api.SearchRequest) **float64 { return &r.MinPaymentScore }),
+	"type":     parseTypeQualifier,
+	"limit":    parseLimitQualifier,
+}
+
+// ParseQuery splits raw into free text and key:value qualifiers, for example
+//
+//	image generation network:base,polygon price:<0.5 asset:USDC provider:>=0.8 type:http
+//
+// Free text becomes the query string. Values may be double-quoted, and a
+// quoted token is always treated as free text.
+func ParseQuery(raw string) (Query, error) {
+	tokens, err := tokenize(raw)
+	if err != nil {
+		return Query{}, err
+	}
+
+	var q Query
+	var text []string
+	seen := make(map[string]struct{})
+	for _, tok := range tokens {
+		key, value, isQualifier := splitQualifier(tok)
+		if !isQualifier {
+			text = append(text, tok.text)
+			continue
+		}
+
+		canonical, known := qualifierAliases[key]
+		if !known {
+			return Query{}, fmt.Errorf(
+				"unknown search qualifier %q in %q (supported: %s)",
+				key,
+				tok.text,
+				strings.Join(SupportedQualifiers(), ", "),
+			)
+		}
+		if value == "" {
+			return Query{}, fmt.Errorf("search qualifier %q requires a value", key)
+		}
+		if canonical != "network" && canonical != "type" {
+			if _, dup := seen[canonical]; dup {
+				return Query{}, fmt.Errorf("search qualifier %q specified more than once", canonical)
+			}
+		}
+		seen[canonical] = struct{}{}
+
+		if err := qualifierHandlers[canonical](&q, key, value); err != nil {
+			return Query{}, err
+		}
+	}
+
+	q.Request.Query = strings.Join(text, " ")
+	if q.Request.Query == "" {
+		return Query{}, fmt.Errorf("query text is required in addition to qualifiers")
+	}
+	return q, nil
+}
+
+// SupportedQualifiers returns the canonical qualifier names in sorted order.
+func SupportedQualifiers() []string {
+	out := make([]string, 0, len(qualifierHandlers))
+	for name := range qualifierHandlers {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// FilterTypes keeps items whose Type matches one of types (case-insensitive).
+// An empty types list keeps every item.
+func FilterTypes(items []api.SearchResultItem, types []string) []api.SearchResultItem {
+	if len(types) == 0 {
+		return items
+	}
+	out := make([]api.SearchResultItem, 0, len(items))
+	for _, item := range items {
+		for _, t := range types {
+			if strings.EqualFold(strings.TrimSpace(item.Type), t) {
+				out = append(out, item)
+				break
+			}
+		}
+	}
+	return out
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(raw string) ([]token, error) {
+	var tokens []token
+	var current strings.Builder
+	inQuotes := false
+	hasToken := false
+	startsQuoted := false
+
+	flush := func() {
+		if hasToken {
+			tokens = append(tokens, token{text: current.String(), quoted: startsQuoted})
+		}
+		current.Reset()
+		hasToken = false
+		startsQuoted = false
+	}
+
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			if !hasToken {
+				startsQuoted = true
+			}
+			inQuotes = !inQuotes
+			hasToken = true
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			flush()
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in search query")
+	}
+	flush()
+	return tokens, nil
+}
+
+func splitQualifier(tok token) (string, string, bool) {
+	if tok.quoted {
+		return "", "", false
+	}
+	idx := strings.IndexByte(tok.text, ':')
+	if idx <= 0 {
+		return "", "", false
+	}
+	key := tok.text[:idx]
+	value := tok.text[idx+1:]
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r == '-') {
+			return "", "", false
+		}
+	}
+	// Leave URLs such as https://example.com in the free text.
+	if strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+	return strings.ToLower(key), strings.TrimSpace(value), true
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, raw := range strings.Split(value, ",") {
+		item := strings.TrimSpace(raw)
+		if item == "" {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func splitComparison(value string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, op) {
+			return op, strings.TrimSpace(value[len(op):])
+		}
+	}
+	return "", value
+}
+
+func parseNonNegativeFloat(key, raw string) (float64, error) {
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("search qualifier %q: invalid number %q", key, raw)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("search qualifier %q must be non-negative", key)
+	}
+	return value, nil
+}
+
+func parseNetworkQualifier(q *Query, key, value string) error {
+	networks := splitList(value)
+	if len(networks) == 0 {
+		return fmt.Errorf("search qualifier %q requires at least one network", key)
+	}
+	q.Request.Networks = append(q.Request.Networks, networks...)
+	return nil
+}
+
+func parsePriceQualifier(q *Query, key, value string) error {
+	op, raw := splitComparison(value)
+	switch op {
+	case "", "<", "<=", "=":
+	default:
+		return fmt.Errorf("search qualifier %q only supports an upper bound (<, <= or a plain value), got %q", key, op)
+	}
+	amount, err := money.ParseAmount(raw)
+	if err != nil {
+		return fmt.Errorf("search qualifier %q: %w", key, err)
+	}
+	budget := amount.Float()
+	q.Request.BudgetMax = &budget
+	q.BudgetUnit = amount.Unit
+	return nil
+}
+
+func parseAssetQualifier(q *Query, key, value string) error {
+	if strings.Contains(value, ",") {
+		return fmt.Errorf("search qualifier %q accepts a single asset", key)
+	}
+	q.Request.BudgetAsset = value
+	return nil
+}
+
+func scoreQualifier(field func(*api.SearchRequest) **float64) qualifierHandler {
+	return func(q *Query, key, value string) error {
+		op, raw := splitComparison(value)
+		switch op {
+		case "", ">", ">=", "=":
+		default:
+			return fmt.Errorf("search qualifier %q only supports a lower bound (>, >= or a plain value), got %q", key, op)
+		}
+		score, err := parseNonNegativeFloat(key, raw)
+		if err != nil {
+			return err
+		}
+		*field(&q.Request) = &score
+		return nil
+	}
+}
+
+func parseTypeQualifier(q *Query, key, value string) error {
+	types := splitList(value)
+	if len(types) == 0 {
+		return fmt.Errorf("search qualifier %q requires at least one type", key)
+	}
+	q.Types = append(q.Types, types...)
+	return nil
+}
+
+func parseLimitQualifier(q *Query, key, value string) error {
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return fmt.Errorf("search qualifier %q must be a positive integer, got %q", key, value)
+	}
+	q.Request.Limit = limit
+	return nil
+}
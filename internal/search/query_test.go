@@ -0,0 +1,160 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		want      api.SearchRequest
+		wantTypes []string
+		wantUnit  string
+	}{
+		{
+			name: "free text only",
+			raw:  "image generation",
+			want: api.SearchRequest{Query: "image generation"},
+		},
+		{
+			name: "all qualifiers",
+			raw:  "image generation network:base,polygon price:<0.5 asset:USDC provider:>=0.8 type:http",
+			want: api.SearchRequest{
+				Query:            "image generation",
+				Networks:         []string{"base", "polygon"},
+				BudgetMax:        floatPtr(0.5),
+				BudgetAsset:      "USDC",
+				MinProviderScore: floatPtr(0.8),
+			},
+			wantTypes: []string{"http"},
+		},
+		{
+			name: "qualifiers interleaved with text",
+			raw:  "network:base speech price:1 to text",
+			want: api.SearchRequest{
+				Query:     "speech to text",
+				Networks:  []string{"base"},
+				BudgetMax: floatPtr(1),
+			},
+		},
+		{
+			name: "repeated network qualifiers accumulate",
+			raw:  "ocr network:base network:polygon",
+			want: api.SearchRequest{Query: "ocr", Networks: []string{"base", "polygon"}},
+		},
+		{
+			name: "aliases and case-insensitive keys",
+			raw:  "ocr Networks:base budget:<=2 Service:0.5 payment:>0.25",
+			want: api.SearchRequest{
+				Query:           "ocr",
+				Networks:        []string{"base"},
+				BudgetMax:       floatPtr(2),
+				MinServiceScore: floatPtr(0.5),
+				MinPaymentScore: floatPtr(0.25),
+			},
+		},
+		{
+			name:     "price with unit",
+			raw:      "ocr price:<5USD asset:USDC",
+			want:     api.SearchRequest{Query: "ocr", BudgetMax: floatPtr(5), BudgetAsset: "USDC"},
+			wantUnit: "USD",
+		},
+		{
+			name: "limit qualifier",
+			raw:  "ocr limit:20",
+			want: api.SearchRequest{Query: "ocr", Limit: 20},
+		},
+		{
+			name: "quoted token stays free text",
+			raw:  `"price:cheap" ocr`,
+			want: api.SearchRequest{Query: "price:cheap ocr"},
+		},
+		{
+			name: "urls stay free text",
+			raw:  "https://example.com/api ocr",
+			want: api.SearchRequest{Query: "https://example.com/api ocr"},
+		},
+		{
+			name: "quoted qualifier value",
+			raw:  `ocr asset:"USDC"`,
+			want: api.SearchRequest{Query: "ocr", BudgetAsset: "USDC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Request, tt.want) {
+				t.Fatalf("request mismatch\n got: %+v\nwant: %+v", got.Request, tt.want)
+			}
+			if got.BudgetUnit != tt.wantUnit {
+				t.Fatalf("budget unit mismatch: got %q want %q", got.BudgetUnit, tt.wantUnit)
+			}
+			if !reflect.DeepEqual(got.Types, tt.wantTypes) {
+				t.Fatalf("types mismatch: got %v want %v", got.Types, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{name: "unknown qualifier", raw: "ocr color:red", wantErr: `unknown search qualifier "color"`},
+		{name: "missing value", raw: "ocr asset:", wantErr: "requires a value"},
+		{name: "price lower bound", raw: "ocr price:>1", wantErr: "only supports an upper bound"},
+		{name: "score upper bound", raw: "ocr provider:<0.5", wantErr: "only supports a lower bound"},
+		{name: "invalid price", raw: "ocr price:<cheap", wantErr: "invalid amount"},
+		{name: "negative price", raw: "ocr price:-1", wantErr: "invalid amount"},
+		{name: "invalid score", raw: "ocr provider:high", wantErr: "invalid number"},
+		{name: "negative score", raw: "ocr provider:-1", wantErr: "non-negative"},
+		{name: "duplicate scalar", raw: "ocr price:1 budget:2", wantErr: "more than once"},
+		{name: "multiple assets", raw: "ocr asset:USDC,ETH", wantErr: "single asset"},
+		{name: "bad limit", raw: "ocr limit:0", wantErr: "positive integer"},
+		{name: "qualifiers only", raw: "network:base", wantErr: "query text is required"},
+		{name: "unterminated quote", raw: `ocr "asset:USDC`, wantErr: "unterminated quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.raw)
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestFilterTypes(t *testing.T) {
+	items := []api.SearchResultItem{
+		{ID: "a", Type: "http"},
+		{ID: "b", Type: "MCP"},
+		{ID: "c", Type: "grpc"},
+	}
+
+	got := FilterTypes(items, []string{"mcp", "http"})
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "b" {
+		t.Fatalf("unexpected filter result: %+v", got)
+	}
+	if len(FilterTypes(items, nil)) != len(items) {
+		t.Fatalf("expected empty type filter to keep all items")
+	}
+}
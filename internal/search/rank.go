@@ -0,0 +1,263 @@
+package search
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+// Weights controls how much each component contributes to a rank total.
+type Weights struct {
+	Score   float64
+	Price   float64
+	Network float64
+	Asset   float64
+}
+
+// RankOptions configures Rank.
+type RankOptions struct {
+	Weights Weights
+	// PreferredNetworks and PreferredAssets are ordered most preferred first.
+	PreferredNetworks []string
+	PreferredAssets   []string
+	// FX converts prices to USD when items are priced in different assets.
+	FX money.FXTable
+}
+
+// Breakdown holds the weighted contribution of each component to Total.
+type Breakdown struct {
+	Score   float64 `json:"score"`
+	Price   float64 `json:"price"`
+	Network float64 `json:"network"`
+	Asset   float64 `json:"asset"`
+	Total   float64 `json:"total"`
+}
+
+// RankedItem pairs a search result with its rank breakdown.
+type RankedItem struct {
+	Item      api.SearchResultItem `json:"item"`
+	Breakdown Breakdown            `json:"breakdown"`
+}
+
+// Rank orders items by a weighted combination of the server score, price,
+// preferred networks and preferred asset. Each component is normalized to
+// [0,1] within the result set before weighting: the best server score and
+// the cheapest price score 1, and preferences score by list position.
+// Prices are compared as described for comparablePrices; a price that cannot
+// be compared scores 0. Ties are broken by price and then resource URL, so
+// output is deterministic.
+func Rank(items []api.SearchResultItem, opts RankOptions) []RankedItem {
+	ranked := make([]RankedItem, 0, len(items))
+	if len(items) == 0 {
+		return ranked
+	}
+
+	prices := comparablePrices(items, opts.FX)
+	maxScore := 0.0
+	minPrice, maxPrice, anyPrice := 0.0, 0.0, false
+	for i, item := range items {
+		if item.Score > maxScore {
+			maxScore = item.Score
+		}
+		if !prices[i].ok {
+			continue
+		}
+		if !anyPrice || prices[i].value < minPrice {
+			minPrice = prices[i].value
+		}
+		if !anyPrice || prices[i].value > maxPrice {
+			maxPrice = prices[i].value
+		}
+		anyPrice = true
+	}
+
+	type entry struct {
+		ranked RankedItem
+		price  price
+	}
+	entries := make([]entry, 0, len(items))
+	for i, item := range items {
+		scoreComponent := 0.0
+		if maxScore > 0 {
+			scoreComponent = item.Score / maxScore
+		}
+		priceComponent := 0.0
+		switch {
+		case !prices[i].ok:
+		case maxPrice > minPrice:
+			priceComponent = (maxPrice - prices[i].value) / (maxPrice - minPrice)
+		default:
+			priceComponent = 1
+		}
+		networkComponent := 0.0
+		for _, network := range item.Networks {
+			if v := preference(opts.PreferredNetworks, network); v > networkComponent {
+				networkComponent = v
+			}
+		}
+		assetComponent := preference(opts.PreferredAssets, item.Asset)
+
+		b := Breakdown{
+			Score:   opts.Weights.Score * scoreComponent,
+			Price:   opts.Weights.Price * priceComponent,
+			Network: opts.Weights.Network * networkComponent,
+			Asset:   opts.Weights.Asset * assetComponent,
+		}
+		b.Total = b.Score + b.Price + b.Network + b.Asset
+		entries = append(entries, entry{ranked: RankedItem{Item: item, Breakdown: b}, price: prices[i]})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.ranked.Breakdown.Total != b.ranked.Breakdown.Total {
+			return a.ranked.Breakdown.Total > b.ranked.Breakdown.Total
+		}
+		if less, equal := a.price.less(b.price); !equal {
+			return less
+		}
+		return a.ranked.Item.ResourceURL < b.ranked.Item.ResourceURL
+	})
+	for _, e := range entries {
+		ranked = append(ranked, e.ranked)
+	}
+	return ranked
+}
+
+// price is an item price in a unit shared by the result set; ok is false when
+// it could not be converted to that unit.
+type price struct {
+	value float64
+	ok    bool
+}
+
+// less orders prices cheapest first, with prices that cannot be compared
+// last.
+func (p price) less(other price) (less, equal bool) {
+	if p.ok != other.ok {
+		return p.ok, false
+	}
+	return p.value < other.value, p.value == other.value
+}
+
+// comparablePrices returns the items' prices in a common unit. Items all in
+// one asset are compared as they are. Otherwise prices are converted to USD
+// with fx, and prices in an asset without a rate, or with no asset, cannot
+// be compared.
+func comparablePrices(items []api.SearchResultItem, fx money.FXTable) []price {
+	prices := make([]price, len(items))
+	sameAsset := true
+	for _, item := range items {
+		if money.NormalizeSymbol(item.Asset) != money.NormalizeSymbol(items[0].Asset) {
+			sameAsset = false
+			break
+		}
+	}
+	for i, item := range items {
+		if sameAsset {
+			prices[i] = price{value: item.MinPrice, ok: true}
+			continue
+		}
+		value := new(big.Rat).SetFloat64(item.MinPrice)
+		if value == nil || strings.TrimSpace(item.Asset) == "" {
+			continue
+		}
+		usd, err := fx.Convert(money.Amount{Value: value, Unit: item.Asset}, "USD")
+		if err != nil {
+			continue
+		}
+		prices[i].value, _ = usd.Float64()
+		prices[i].ok = true
+	}
+	return prices
+}
+
+// preference scores value by its position in preferred: 1 for the first
+// entry, decreasing linearly, and 0 when absent.
+func preference(preferred []string, value string) float64 {
+	value = strings.TrimSpace(value)
+	for i, candidate := range preferred {
+		if strings.EqualFold(strings.TrimSpace(candidate), value) {
+			return 1 - float64(i)/float64(len(preferred))
+		}
+	}
+	return 0
+}
+
+// SortKeys lists the values accepted by SortItems.
+var SortKeys = []string{"price", "-price", "score", "-score", "network", "-network"}
+
+// SortItems orders items by key. Each key has a natural direction (price
+// cheapest first, score best first, network alphabetical) which a leading
+// "-" reverses. Prices are compared as described for comparablePrices, and
+// prices that cannot be compared sort last in either direction. Ties are
+// broken by resource URL.
+func SortItems(items []api.SearchResultItem, key string, fx money.FXTable) ([]api.SearchResultItem, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	reverse := strings.HasPrefix(key, "-")
+	field := strings.TrimPrefix(key, "-")
+
+	type entry struct {
+		item  api.SearchResultItem
+		price price
+	}
+	var less func(a, b entry) (bool, bool)
+	switch field {
+	case "price":
+		less = func(a, b entry) (bool, bool) {
+			return a.price.value < b.price.value, a.price.value == b.price.value
+		}
+	case "score":
+		less = func(a, b entry) (bool, bool) {
+			return a.item.Score > b.item.Score, a.item.Score == b.item.Score
+		}
+	case "network":
+		less = func(a, b entry) (bool, bool) {
+			na, nb := firstNetwork(a.item), firstNetwork(b.item)
+			return na < nb, na == nb
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sort key %q (supported: %s)", key, strings.Join(SortKeys, ", "))
+	}
+
+	prices := comparablePrices(items, fx)
+	entries := make([]entry, len(items))
+	for i, item := range items {
+		entries[i] = entry{item: item, price: prices[i]}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if field == "price" && a.price.ok != b.price.ok {
+			return a.price.ok
+		}
+		isLess, equal := less(a, b)
+		if equal {
+			return a.item.ResourceURL < b.item.ResourceURL
+		}
+		if reverse {
+			return !isLess
+		}
+		return isLess
+	})
+
+	out := make([]api.SearchResultItem, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.item)
+	}
+	return out, nil
+}
+
+func firstNetwork(item api.SearchResultItem) string {
+	networks := make([]string, 0, len(item.Networks))
+	for _, network := range item.Networks {
+		networks = append(networks, strings.ToLower(strings.TrimSpace(network)))
+	}
+	sort.Strings(networks)
+	if len(networks) == 0 {
+		return ""
+	}
+	return networks[0]
+}
@@ -0,0 +1,189 @@
+package search
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+func rankFixture() []api.SearchResultItem {
+	return []api.SearchResultItem{
+		{ID: "a", ResourceURL: "https://a.example/api", Score: 0.9, MinPrice: 1.0, Asset: "USDC", Networks: []string{"polygon"}},
+		{ID: "b", ResourceURL: "https://b.example/api", Score: 0.6, MinPrice: 0.1, Asset: "USDC", Networks: []string{"base"}},
+		{ID: "c", ResourceURL: "https://c.example/api", Score: 0.3, MinPrice: 0.0002, Asset: "ETH", Networks: []string{"base", "arbitrum"}},
+	}
+}
+
+// rankFX prices c at 0.6 USD, between a and b.
+func rankFX(t *testing.T) money.FXTable {
+	t.Helper()
+	fx, err := money.DefaultFX().With(map[string]float64{"ETH": 3000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return fx
+}
+
+func ids(items []api.SearchResultItem) string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RankOptions
+		want string
+	}{
+		{
+			name: "score only keeps server order",
+			opts: RankOptions{Weights: Weights{Score: 1}},
+			want: "a,b,c",
+		},
+		{
+			name: "price only prefers cheapest",
+			opts: RankOptions{Weights: Weights{Price: 1}},
+			want: "b,c,a",
+		},
+		{
+			name: "network preference",
+			opts: RankOptions{Weights: Weights{Network: 1}, PreferredNetworks: []string{"arbitrum", "base"}},
+			want: "c,b,a",
+		},
+		{
+			name: "asset preference ties broken by price",
+			opts: RankOptions{Weights: Weights{Asset: 1}, PreferredAssets: []string{"usdc"}},
+			want: "b,a,c",
+		},
+		{
+			name: "combined weights",
+			opts: RankOptions{
+				Weights:           Weights{Score: 0.5, Price: 0.3, Network: 0.1, Asset: 0.1},
+				PreferredNetworks: []string{"base"},
+				PreferredAssets:   []string{"USDC"},
+			},
+			want: "b,a,c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.FX = rankFX(t)
+			ranked := Rank(rankFixture(), tt.opts)
+			items := make([]api.SearchResultItem, 0, len(ranked))
+			for _, r := range ranked {
+				items = append(items, r.Item)
+			}
+			if got := ids(items); got != tt.want {
+				t.Fatalf("expected order %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRank_Breakdown(t *testing.T) {
+	ranked := Rank(rankFixture(), RankOptions{
+		Weights:           Weights{Score: 0.5, Price: 0.3, Network: 0.1, Asset: 0.1},
+		PreferredNetworks: []string{"base"},
+		PreferredAssets:   []string{"USDC"},
+		FX:                rankFX(t),
+	})
+	b := ranked[0].Breakdown
+	if ranked[0].Item.ID != "b" {
+		t.Fatalf("expected b first, got %s", ranked[0].Item.ID)
+	}
+	want := Breakdown{Score: 0.5 * 0.6 / 0.9, Price: 0.3, Network: 0.1, Asset: 0.1}
+	want.Total = want.Score + want.Price + want.Network + want.Asset
+	for name, pair := range map[string][2]float64{
+		"score":   {b.Score, want.Score},
+		"price":   {b.Price, want.Price},
+		"network": {b.Network, want.Network},
+		"asset":   {b.Asset, want.Asset},
+		"total":   {b.Total, want.Total},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Fatalf("%s: expected %.6f, got %.6f", name, pair[1], pair[0])
+		}
+	}
+}
+
+func TestRank_Empty(t *testing.T) {
+	if got := Rank(nil, RankOptions{}); len(got) != 0 {
+		t.Fatalf("expected no ranked items, got %d", len(got))
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "price", want: "b,c,a"},
+		{key: "-price", want: "a,c,b"},
+		{key: "score", want: "a,b,c"},
+		{key: "-score", want: "c,b,a"},
+		{key: "network", want: "c,b,a"},
+		{key: "-network", want: "a,b,c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := SortItems(rankFixture(), tt.key, rankFX(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ids(got) != tt.want {
+				t.Fatalf("expected order %s, got %s", tt.want, ids(got))
+			}
+		})
+	}
+
+	if _, err := SortItems(rankFixture(), "popularity", rankFX(t)); err == nil {
+		t.Fatalf("expected error for unsupported sort key")
+	}
+}
+
+func TestPricesAcrossAssets(t *testing.T) {
+	// Raw prices would put 0.5 ETH before 1 USDC. SOL has no rate, so it
+	// cannot be compared and goes last.
+	items := []api.SearchResultItem{
+		{ID: "eth", ResourceURL: "https://eth.example/api", MinPrice: 0.5, Asset: "ETH"},
+		{ID: "usdc", ResourceURL: "https://usdc.example/api", MinPrice: 1, Asset: "USDC"},
+		{ID: "sol", ResourceURL: "https://sol.example/api", MinPrice: 0.01, Asset: "SOL"},
+	}
+	fx := rankFX(t)
+
+	for key, want := range map[string]string{"price": "usdc,eth,sol", "-price": "eth,usdc,sol"} {
+		got, err := SortItems(items, key, fx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ids(got) != want {
+			t.Fatalf("%s: expected order %s, got %s", key, want, ids(got))
+		}
+	}
+
+	ranked := Rank(items, RankOptions{Weights: Weights{Price: 1}, FX: fx})
+	if ranked[0].Item.ID != "usdc" || ranked[2].Item.ID != "sol" || ranked[2].Breakdown.Price != 0 {
+		t.Fatalf("unexpected ranking: %+v", ranked)
+	}
+
+	// One asset throughout needs no rates, even an unknown one.
+	same := []api.SearchResultItem{
+		{ID: "x", ResourceURL: "https://x.example/api", MinPrice: 2, Asset: "FOO"},
+		{ID: "y", ResourceURL: "https://y.example/api", MinPrice: 1, Asset: "foo"},
+	}
+	got, err := SortItems(same, "price", money.FXTable{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids(got) != "y,x" {
+		t.Fatalf("expected y,x, got %s", ids(got))
+	}
+}
@@ -0,0 +1,242 @@
+// Package spend turns the marketplace's spend summary into report rows with
+// per-asset totals, shares, budget use and sparklines.
+package spend
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+	"github.com/promptingcompany/openspend-cli/internal/money"
+)
+
+// GroupBys lists the accepted --group-by values.
+var GroupBys = []string{"agent", "policy", "host", "network", "asset"}
+
+// Intervals lists the accepted sparkline bucket sizes.
+var Intervals = []string{"hour", "day", "week"}
+
+// sparkTicks are the sparkline levels, lowest first.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Row is one group's spend in one asset. Total and Series are base units;
+// Amount is Total in asset units, or "" when the decimals are unknown.
+type Row struct {
+	Key       string     `json:"key"`
+	Label     string     `json:"label,omitempty"`
+	Asset     string     `json:"asset"`
+	Total     string     `json:"total"`
+	Amount    string     `json:"amount"`
+	Count     int        `json:"count"`
+	Share     float64    `json:"share"`
+	Budget    *BudgetUse `json:"budget,omitempty"`
+	Series    []string   `json:"series"`
+	Sparkline string     `json:"sparkline"`
+}
+
+// BudgetUse is how much of an agent's budget is used. The monthly limit is
+// preferred; Used is Spent over Limit.
+type BudgetUse struct {
+	Period string  `json:"period"`
+	Limit  string  `json:"limit"`
+	Spent  string  `json:"spent"`
+	Used   float64 `json:"used"`
+}
+
+// Total is the spend across all groups in one asset.
+type Total struct {
+	Asset  string `json:"asset"`
+	Total  string `json:"total"`
+	Amount string `json:"amount"`
+	Count  int    `json:"count"`
+}
+
+type Report struct {
+	Since    string  `json:"since"`
+	Until    string  `json:"until"`
+	GroupBy  string  `json:"groupBy"`
+	Interval string  `json:"interval"`
+	Rows     []Row   `json:"rows"`
+	Totals   []Total `json:"totals"`
+}
+
+// ParseGroupBy accepts one of GroupBys, case-insensitively.
+func ParseGroupBy(raw string) (string, error) {
+	return parseChoice(raw, GroupBys, "group-by")
+}
+
+// ParseInterval accepts one of Intervals, case-insensitively.
+func ParseInterval(raw string) (string, error) {
+	return parseChoice(raw, Intervals, "interval")
+}
+
+func parseChoice(raw string, choices []string, name string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	for _, choice := range choices {
+		if value == choice {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unknown %s %q (expected %s)", name, raw, strings.Join(choices, ", "))
+}
+
+// Build turns a spend response into a report. Rows are ordered by asset, then
+// by total, largest first. Groups with an unparsable total are counted as 0.
+func Build(res api.SpendResponse) Report {
+	report := Report{
+		Since:    res.Since,
+		Until:    res.Until,
+		GroupBy:  res.GroupBy,
+		Interval: res.Interval,
+		Rows:     make([]Row, 0, len(res.Groups)),
+	}
+
+	totals := make(map[string]*big.Int)
+	counts := make(map[string]int)
+	rowTotals := make([]*big.Int, 0, len(res.Groups))
+	for _, group := range res.Groups {
+		asset := money.NormalizeSymbol(group.Asset)
+		total := parseBase(group.Total)
+		series := make([]*big.Int, len(group.Series))
+		for i, raw := range group.Series {
+			series[i] = parseBase(raw)
+		}
+
+		row := Row{
+			Key:       strings.TrimSpace(group.Key),
+			Asset:     asset,
+			Total:     total.String(),
+			Amount:    formatAmount(total, asset),
+			Count:     group.Count,
+			Budget:    budgetUse(group.Budget, asset),
+			Series:    group.Series,
+			Sparkline: Sparkline(series),
+		}
+		if group.Label != nil {
+			row.Label = strings.TrimSpace(*group.Label)
+		}
+		report.Rows = append(report.Rows, row)
+		rowTotals = append(rowTotals, total)
+
+		if totals[asset] == nil {
+			totals[asset] = new(big.Int)
+		}
+		totals[asset].Add(totals[asset], total)
+		counts[asset] += group.Count
+	}
+
+	for i := range report.Rows {
+		assetTotal := totals[report.Rows[i].Asset]
+		if assetTotal.Sign() > 0 {
+			report.Rows[i].Share, _ = new(big.Rat).SetFrac(rowTotals[i], assetTotal).Float64()
+		}
+	}
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Asset != b.Asset {
+			return a.Asset < b.Asset
+		}
+		if a.Share != b.Share {
+			return a.Share > b.Share
+		}
+		return a.Key < b.Key
+	})
+
+	assets := make([]string, 0, len(totals))
+	for asset := range totals {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	for _, asset := range assets {
+		report.Totals = append(report.Totals, Total{
+			Asset:  asset,
+			Total:  totals[asset].String(),
+			Amount: formatAmount(totals[asset], asset),
+			Count:  counts[asset],
+		})
+	}
+	return report
+}
+
+// Sparkline renders values as block characters scaled to the largest value.
+// Zero is the lowest block and any spend is at least one level above it.
+func Sparkline(values []*big.Int) string {
+	max := new(big.Int)
+	for _, v := range values {
+		if v != nil && v.Cmp(max) > 0 {
+			max = v
+		}
+	}
+
+	top := int64(len(sparkTicks) - 1)
+	var b strings.Builder
+	for _, v := range values {
+		level := int64(0)
+		if v != nil && v.Sign() > 0 && max.Sign() > 0 {
+			// 1 + v*(top-1)/max, so the largest value reaches the top block.
+			scaled := new(big.Int).Mul(v, big.NewInt(top-1))
+			level = 1 + scaled.Quo(scaled, max).Int64()
+		}
+		b.WriteRune(sparkTicks[level])
+	}
+	return b.String()
+}
+
+func budgetUse(budget *api.AgentBudget, asset string) *BudgetUse {
+	if budget == nil || money.NormalizeSymbol(budget.Asset) != asset {
+		return nil
+	}
+	for _, period := range []struct {
+		name  string
+		limit *string
+		spent *string
+	}{
+		{"monthly", budget.MonthlyLimit, budget.MonthlySpent},
+		{"daily", budget.DailyLimit, budget.DailySpent},
+	} {
+		if period.limit == nil || strings.TrimSpace(*period.limit) == "" {
+			continue
+		}
+		limit := parseBase(*period.limit)
+		spent := new(big.Int)
+		if period.spent != nil {
+			spent = parseBase(*period.spent)
+		}
+		use := &BudgetUse{Period: period.name, Limit: limit.String(), Spent: spent.String()}
+		if limit.Sign() > 0 {
+			use.Used, _ = new(big.Rat).SetFrac(spent, limit).Float64()
+		}
+		return use
+	}
+	return nil
+}
+
+// FormatBudget renders budget use as "42% of 200 USDC monthly".
+func FormatBudget(use *BudgetUse, asset string) string {
+	if use == nil {
+		return ""
+	}
+	limit := formatAmount(parseBase(use.Limit), asset)
+	if limit == "" {
+		limit = use.Limit
+	}
+	return fmt.Sprintf("%.0f%% of %s %s %s", use.Used*100, limit, asset, use.Period)
+}
+
+func parseBase(raw string) *big.Int {
+	value, err := money.ParseBaseUnits(raw)
+	if err != nil {
+		return new(big.Int)
+	}
+	return value
+}
+
+func formatAmount(base *big.Int, asset string) string {
+	human, err := money.FromBaseUnits(base, asset, "")
+	if err != nil {
+		return ""
+	}
+	return money.FormatRat(human)
+}
@@ -0,0 +1,104 @@
+package spend
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/promptingcompany/openspend-cli/internal/api"
+)
+
+func strPtr(v string) *string { return &v }
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+		want   string
+	}{
+		{name: "empty", values: nil, want: ""},
+		{name: "all zero", values: []int64{0, 0, 0}, want: "▁▁▁"},
+		{name: "scaled", values: []int64{0, 1, 50, 100}, want: "▁▂▅█"},
+		{name: "flat spend", values: []int64{5, 5}, want: "██"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make([]*big.Int, len(tt.values))
+			for i, v := range tt.values {
+				values[i] = big.NewInt(v)
+			}
+			if got := Sparkline(values); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	res := api.SpendResponse{
+		Since:    "2026-09-18T00:00:00Z",
+		GroupBy:  "agent",
+		Interval: "day",
+		Groups: []api.SpendGroup{
+			{Key: "small", Asset: "usdc", Total: "250000", Count: 1, Series: []string{"0", "250000"}},
+			{
+				Key:    "big",
+				Label:  strPtr("Big Agent"),
+				Asset:  "USDC",
+				Total:  "750000",
+				Count:  3,
+				Series: []string{"500000", "250000"},
+				Budget: &api.AgentBudget{
+					Asset:        "USDC",
+					DailyLimit:   strPtr("1000000"),
+					MonthlyLimit: strPtr("3000000"),
+					MonthlySpent: strPtr("750000"),
+				},
+			},
+			{Key: "eth", Asset: "ETH", Total: "1000000000000000", Count: 2},
+		},
+	}
+
+	report := Build(res)
+	if len(report.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %+v", report.Rows)
+	}
+	if report.Rows[0].Key != "eth" || report.Rows[1].Key != "big" || report.Rows[2].Key != "small" {
+		t.Fatalf("unexpected row order: %+v", report.Rows)
+	}
+
+	top := report.Rows[1]
+	if top.Label != "Big Agent" || top.Amount != "0.75" || top.Share != 0.75 || top.Sparkline != "█▅" {
+		t.Fatalf("unexpected row: %+v", top)
+	}
+	if top.Budget == nil || top.Budget.Period != "monthly" || top.Budget.Used != 0.25 {
+		t.Fatalf("expected monthly budget use, got %+v", top.Budget)
+	}
+	if got := FormatBudget(top.Budget, top.Asset); got != "25% of 3 USDC monthly" {
+		t.Fatalf("unexpected budget text %q", got)
+	}
+	if report.Rows[2].Budget != nil {
+		t.Fatalf("expected no budget for small, got %+v", report.Rows[2].Budget)
+	}
+
+	if len(report.Totals) != 2 {
+		t.Fatalf("expected totals for 2 assets, got %+v", report.Totals)
+	}
+	if report.Totals[1] != (Total{Asset: "USDC", Total: "1000000", Amount: "1", Count: 4}) {
+		t.Fatalf("unexpected USDC total: %+v", report.Totals[1])
+	}
+	if report.Totals[0].Amount != "0.001" {
+		t.Fatalf("unexpected ETH total: %+v", report.Totals[0])
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	if got, err := ParseGroupBy(" Host "); err != nil || got != "host" {
+		t.Fatalf("expected host, got %q (%v)", got, err)
+	}
+	if _, err := ParseGroupBy("provider"); err == nil {
+		t.Fatalf("expected error for unknown group-by")
+	}
+	if _, err := ParseInterval("month"); err == nil {
+		t.Fatalf("expected error for unknown interval")
+	}
+}